@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/breaking"
+	"github.com/yuemori/protobuf-mcp-server/internal/gitrepo"
+	"github.com/yuemori/protobuf-mcp-server/internal/snapshot"
+)
+
+// runCheckBreaking compares the project's proto files with a git ref or a
+// saved baseline and prints the breaking changes. It reports whether any
+// were found.
+func runCheckBreaking(args []string) (bool, error) {
+	flags := flag.NewFlagSet("check-breaking", flag.ContinueOnError)
+	against := flags.String("against", "", "git ref to compare against (default: HEAD unless a baseline is configured or saved)")
+	baseline := flags.String("baseline", "", "FileDescriptorSet baseline to compare against, relative to the project root (default: breaking.baseline, or "+snapshot.DefaultPath+" when it exists)")
+	categoriesFlag := flags.String("categories", "", "comma-separated rule categories: FILE, PACKAGE, WIRE_JSON, WIRE")
+	wireOnly := flags.Bool("wire-only", false, "only report wire-breaking changes (same as --categories WIRE)")
+	if err := flags.Parse(args); err != nil {
+		return false, err
+	}
+	if *against != "" && *baseline != "" {
+		return false, fmt.Errorf("--against and --baseline cannot be used together")
+	}
+
+	project, err := loadProject(flags)
+	if err != nil {
+		return false, err
+	}
+
+	categoryNames := project.Config.Breaking.Categories
+	if *categoriesFlag != "" {
+		categoryNames = strings.Split(*categoriesFlag, ",")
+	}
+	if *wireOnly {
+		categoryNames = []string{breaking.CategoryWire}
+	}
+	categories, err := breaking.ParseCategories(categoryNames)
+	if err != nil {
+		return false, err
+	}
+
+	if *against == "" && *baseline == "" {
+		*baseline = snapshot.Baseline(project.ProjectRoot, project.Config.Breaking.Baseline)
+		if *baseline == "" {
+			*against = "HEAD"
+		}
+	}
+
+	ctx := context.Background()
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var previous []protoreflect.FileDescriptor
+	target := *against
+	if *baseline != "" {
+		target = *baseline
+		previous, err = snapshot.Read(projectPath(project, *baseline))
+		if err != nil {
+			return false, err
+		}
+	} else {
+		previousFiles, err := gitrepo.CompileProtos(ctx, project.ProjectRoot, project.Config, *against)
+		if err != nil {
+			return false, err
+		}
+		previous = breaking.FileDescriptors(previousFiles)
+	}
+
+	violations := breaking.Filter(breaking.Check(previous, breaking.FileDescriptors(files)), categories)
+	for _, violation := range violations {
+		position := violation.File
+		if violation.Location != nil {
+			position = fmt.Sprintf("%s:%d:%d", violation.File, violation.Location.StartLine, violation.Location.StartColumn)
+		}
+		fmt.Printf("%s: %s [%s, %s]\n", position, violation.Message, violation.Rule, violation.Category)
+	}
+	if len(violations) == 0 {
+		fmt.Printf("No breaking changes against %s\n", target)
+		return false, nil
+	}
+	fmt.Fprintf(os.Stderr, "Found %d breaking changes against %s\n", len(violations), target)
+	return true, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/format"
+)
+
+// runFmt formats the project's proto files in place and prints the files it
+// changed. With --check, nothing is written and the files that need
+// formatting are printed instead. It reports whether the check found files
+// that need formatting.
+func runFmt(args []string) (bool, error) {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	check := flags.Bool("check", false, "report the files that need formatting without rewriting them")
+	if err := flags.Parse(args); err != nil {
+		return false, err
+	}
+
+	project, err := loadProject(flags)
+	if err != nil {
+		return false, err
+	}
+	files, err := project.SourceFiles()
+	if err != nil {
+		return false, err
+	}
+
+	changed := 0
+	for _, file := range files {
+		needed, err := format.File(filepath.Join(project.ProjectRoot, filepath.FromSlash(file)), !*check)
+		if err != nil {
+			return false, err
+		}
+		if needed {
+			fmt.Println(file)
+			changed++
+		}
+	}
+
+	if *check && changed > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d files need formatting\n", changed, len(files))
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/breaking"
+	"github.com/yuemori/protobuf-mcp-server/internal/lint"
+	"github.com/yuemori/protobuf-mcp-server/internal/textedit"
+)
+
+// runLint lints the project's proto files and prints the findings. With
+// --fix, the fixable findings are fixed first and the remaining ones are
+// printed. It reports whether any were found.
+func runLint(args []string) (bool, error) {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	rules := flags.String("rules", "", "comma-separated rule IDs or groups to run instead of the configured rules")
+	listRules := flags.Bool("list-rules", false, "list the available rules")
+	fix := flags.Bool("fix", false, "write the fixes of fixable findings to the proto files")
+	if err := flags.Parse(args); err != nil {
+		return false, err
+	}
+
+	if *listRules {
+		for _, rule := range lint.Rules() {
+			fmt.Printf("%-28s %-9s %s\n", rule.ID, rule.Group, rule.Description)
+		}
+		return false, nil
+	}
+
+	project, err := loadProject(flags)
+	if err != nil {
+		return false, err
+	}
+
+	cfg := project.Config.Lint
+	if *rules != "" {
+		cfg.Rules = strings.Split(*rules, ",")
+		cfg.Except = nil
+	}
+	linter, err := lint.New(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	files, err := project.CompileProtos(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	findings := linter.Run(breaking.FileDescriptors(files), nil)
+	if edits := lint.Edits(findings); *fix && len(edits) > 0 {
+		applied, err := textedit.ApplyFiles(edits, project.SourcePath)
+		if err != nil {
+			return false, err
+		}
+		fmt.Fprintf(os.Stderr, "Applied %d edits to %d files\n", len(edits), len(applied))
+
+		if files, err = project.CompileProtos(context.Background()); err != nil {
+			return false, err
+		}
+		findings = linter.Run(breaking.FileDescriptors(files), nil)
+	}
+	for _, finding := range findings {
+		position := finding.File
+		if finding.Location != nil {
+			position = fmt.Sprintf("%s:%d:%d", finding.File, finding.Location.StartLine, finding.Location.StartColumn)
+		}
+		fmt.Printf("%s: %s [%s]\n", position, finding.Message, finding.Rule)
+	}
+	if len(findings) == 0 {
+		fmt.Println("No lint findings")
+		return false, nil
+	}
+	fmt.Fprintf(os.Stderr, "Found %d lint findings\n", len(findings))
+	return true, nil
+}
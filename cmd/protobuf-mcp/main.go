@@ -39,6 +39,38 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+	case "snapshot":
+		if err := runSnapshot(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "check-breaking":
+		found, err := runCheckBreaking(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if found {
+			os.Exit(1)
+		}
+	case "lint":
+		found, err := runLint(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if found {
+			os.Exit(1)
+		}
+	case "fmt":
+		unformatted, err := runFmt(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if unformatted {
+			os.Exit(1)
+		}
 	default:
 		fmt.Printf("Unknown command: %s\n\n", command)
 		showHelp()
@@ -54,6 +86,14 @@ func showHelp() {
 	fmt.Println("Commands:")
 	fmt.Println("  init [project-path]  - Initialize project configuration")
 	fmt.Println("  server               - Start MCP server")
+	fmt.Println("  snapshot [--output FILE] [project-path]")
+	fmt.Println("                       - Save the compiled project as a FileDescriptorSet baseline")
+	fmt.Println("  check-breaking [--against REF | --baseline FILE] [--categories LIST] [--wire-only] [project-path]")
+	fmt.Println("                       - Report breaking changes against a git ref or a baseline (default: the saved baseline, else HEAD)")
+	fmt.Println("  lint [--rules LIST] [--fix] [--list-rules] [project-path]")
+	fmt.Println("                       - Check proto files against the configured style rules")
+	fmt.Println("  fmt [--check] [project-path]")
+	fmt.Println("                       - Format proto files in place, or list the unformatted ones with --check")
 	fmt.Println("  help                 - Show this help message")
 	fmt.Println("  version              - Show version information")
 	fmt.Println()
@@ -64,5 +104,11 @@ func showHelp() {
 	fmt.Println("  protobuf-mcp init                    # Initialize in current directory")
 	fmt.Println("  protobuf-mcp init /path/to/project   # Initialize in specific directory")
 	fmt.Println("  protobuf-mcp server                  # Start MCP server")
+	fmt.Println("  protobuf-mcp check-breaking --against main  # Compare with the main branch")
+	fmt.Println("  protobuf-mcp snapshot                # Save a baseline for check-breaking")
+	fmt.Println("  protobuf-mcp check-breaking --baseline .protobuf-mcp-baseline.binpb --categories WIRE_JSON")
+	fmt.Println("  protobuf-mcp lint --rules STYLE      # Lint naming only, without comment coverage")
+	fmt.Println("  protobuf-mcp lint --fix              # Fix casing, zero values, comments and import order")
+	fmt.Println("  protobuf-mcp fmt --check             # Fail if any proto file is not formatted")
 	fmt.Println("  protobuf-mcp help                    # Show this help")
 }
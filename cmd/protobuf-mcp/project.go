@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/compiler"
+	"github.com/yuemori/protobuf-mcp-server/internal/config"
+)
+
+// loadProject loads the project configured in the directory given as the
+// only argument left after parsing flags, or in the current directory when
+// there is none. The flag package stops at the first positional argument, so
+// flags after the project path would otherwise be silently ignored.
+func loadProject(flags *flag.FlagSet) (*compiler.ProtobufProject, error) {
+	if flags.NArg() > 1 {
+		return nil, fmt.Errorf("unexpected arguments after the project path: %s (flags must come before the project path)", strings.Join(flags.Args()[1:], " "))
+	}
+	projectPath := flags.Arg(0)
+	if projectPath == "" {
+		projectPath = "."
+	}
+	projectRoot, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project path: %w", err)
+	}
+
+	cfg, err := config.LoadProjectConfig(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	return compiler.NewProtobufProject(projectRoot, cfg)
+}
+
+// projectPath resolves a path given relative to the project root
+func projectPath(project *compiler.ProtobufProject, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(project.ProjectRoot, path)
+}
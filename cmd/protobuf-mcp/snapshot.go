@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/snapshot"
+)
+
+// runSnapshot compiles the project and saves it as a FileDescriptorSet
+// baseline for check-breaking
+func runSnapshot(args []string) error {
+	flags := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	output := flags.String("output", "", "path of the baseline, relative to the project root (default: breaking.baseline or "+snapshot.DefaultPath+")")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	project, err := loadProject(flags)
+	if err != nil {
+		return err
+	}
+
+	path := *output
+	if path == "" {
+		path = project.Config.Breaking.Baseline
+	}
+	if path == "" {
+		path = snapshot.DefaultPath
+	}
+	path = projectPath(project, path)
+
+	files, err := project.CompileProtos(context.Background())
+	if err != nil {
+		return err
+	}
+	if err := snapshot.Write(path, files); err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved %d files to %s\n", len(files), path)
+	return nil
+}
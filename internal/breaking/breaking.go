@@ -0,0 +1,492 @@
+// Package breaking detects breaking changes between two versions of a set of
+// proto files.
+package breaking
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bufbuild/protocompile/linker"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+)
+
+// Rule categories, from the strictest to the most lenient. Every violation
+// belongs to the most lenient category that still considers it breaking:
+//
+//   - WIRE: breaks the binary encoding, e.g. a field number reused or an RPC
+//     signature changed
+//   - WIRE_JSON: additionally breaks the JSON encoding, e.g. a field renamed
+//   - PACKAGE: additionally breaks code generated per package, e.g. a message
+//     deleted
+//   - FILE: additionally breaks code generated per file, e.g. a message moved
+//     to another file
+//
+// Selecting a category also selects every category after it in this list.
+const (
+	CategoryFile     = "FILE"
+	CategoryPackage  = "PACKAGE"
+	CategoryWireJSON = "WIRE_JSON"
+	CategoryWire     = "WIRE"
+)
+
+// Categories lists the rule categories from the strictest to the most lenient
+var Categories = []string{CategoryFile, CategoryPackage, CategoryWireJSON, CategoryWire}
+
+// Violation describes one breaking change. Location points at the affected
+// element in the current files, or at its closest surviving parent when the
+// element was deleted; PreviousLocation points at the element in the previous
+// files.
+type Violation struct {
+	Rule             string          `json:"rule"`
+	Category         string          `json:"category"`
+	Message          string          `json:"message"`
+	File             string          `json:"file"`
+	Element          string          `json:"element,omitempty"`
+	Location         *protoutil.Span `json:"location,omitempty"`
+	PreviousLocation *protoutil.Span `json:"previous_location,omitempty"`
+}
+
+// ParseCategories validates rule category names, accepting any case. An
+// empty list selects FILE, which reports every violation.
+func ParseCategories(names []string) ([]string, error) {
+	var categories []string
+	for _, name := range names {
+		category := strings.ToUpper(strings.TrimSpace(name))
+		if category == "" {
+			continue
+		}
+		if !slices.Contains(Categories, category) {
+			return nil, fmt.Errorf("unknown breaking rule category %q: expected one of %s", name, strings.Join(Categories, ", "))
+		}
+		categories = append(categories, category)
+	}
+	if len(categories) == 0 {
+		categories = []string{CategoryFile}
+	}
+	return categories, nil
+}
+
+// Filter returns the violations reported by any of the given categories
+func Filter(violations []Violation, categories []string) []Violation {
+	strictest := len(Categories)
+	for _, category := range categories {
+		if i := slices.Index(Categories, category); i >= 0 {
+			strictest = min(strictest, i)
+		}
+	}
+
+	var result []Violation
+	for _, violation := range violations {
+		if slices.Index(Categories, violation.Category) >= strictest {
+			result = append(result, violation)
+		}
+	}
+	return result
+}
+
+// FileDescriptors returns the descriptors of compiled files
+func FileDescriptors(files linker.Files) []protoreflect.FileDescriptor {
+	result := make([]protoreflect.FileDescriptor, 0, len(files))
+	for _, file := range files {
+		result = append(result, file)
+	}
+	return result
+}
+
+// Check compares the previous version of a set of files with the current one
+// and returns the breaking changes of every category, ordered by file and
+// element. Types are matched by fully-qualified name across all files, so
+// moving a type to another file of the same package is only a FILE
+// violation. When a file changes its package, its types are matched under
+// the new package so that the move is reported once rather than as the
+// deletion of every type.
+func Check(previous, current []protoreflect.FileDescriptor) []Violation {
+	c := &checker{
+		currentFiles: make(map[string]protoreflect.FileDescriptor),
+		current:      make(map[protoreflect.FullName]protoreflect.Descriptor),
+		moves:        make(map[string]protoreflect.FullName),
+	}
+	for _, file := range current {
+		c.currentFiles[file.Path()] = file
+		forEachType(file, func(desc protoreflect.Descriptor) {
+			c.current[desc.FullName()] = desc
+		})
+	}
+
+	previous = append([]protoreflect.FileDescriptor(nil), previous...)
+	sort.Slice(previous, func(i, j int) bool { return previous[i].Path() < previous[j].Path() })
+
+	for _, file := range previous {
+		c.checkFile(file)
+	}
+	for _, file := range previous {
+		forEachType(file, c.checkType)
+	}
+
+	sort.SliceStable(c.violations, func(i, j int) bool {
+		if c.violations[i].File != c.violations[j].File {
+			return c.violations[i].File < c.violations[j].File
+		}
+		return c.violations[i].Element < c.violations[j].Element
+	})
+	return c.violations
+}
+
+// checker holds the state of one comparison
+type checker struct {
+	currentFiles map[string]protoreflect.FileDescriptor
+	current      map[protoreflect.FullName]protoreflect.Descriptor
+	// moves maps the paths of files that changed package to the new package
+	moves      map[string]protoreflect.FullName
+	violations []Violation
+}
+
+// add records a violation against an element of the previous files. current
+// is the element in the current files, its closest surviving parent when the
+// element was deleted, or nil. The element is named as it is in the current
+// files.
+func (c *checker) add(rule, category string, previous, current protoreflect.Descriptor, format string, args ...interface{}) {
+	violation := Violation{
+		Rule:             rule,
+		Category:         category,
+		Message:          fmt.Sprintf(format, args...),
+		File:             previous.ParentFile().Path(),
+		PreviousLocation: protoutil.SpanOf(previous),
+	}
+	if _, ok := previous.(protoreflect.FileDescriptor); !ok {
+		violation.Element = string(c.translateType(previous))
+	}
+	if current != nil {
+		violation.File = current.ParentFile().Path()
+		violation.Location = protoutil.SpanOf(current)
+	}
+	c.violations = append(c.violations, violation)
+}
+
+// translate returns the name a declaration of a previous file has in the
+// current files, accounting for a package move of the file. Placeholders for
+// unresolved imports have no file and keep their name.
+func (c *checker) translate(name protoreflect.FullName, file protoreflect.FileDescriptor) protoreflect.FullName {
+	if file == nil {
+		return name
+	}
+	pkg, moved := c.moves[file.Path()]
+	if !moved {
+		return name
+	}
+	return joinName(pkg, strings.TrimPrefix(string(name), string(file.Package())+"."))
+}
+
+// translateType translates the name of a declaration of a previous file,
+// which may itself live in a file that moved package
+func (c *checker) translateType(desc protoreflect.Descriptor) protoreflect.FullName {
+	return c.translate(desc.FullName(), desc.ParentFile())
+}
+
+func (c *checker) checkFile(file protoreflect.FileDescriptor) {
+	current, ok := c.currentFiles[file.Path()]
+	if !ok {
+		c.add("FILE_NO_DELETE", CategoryFile, file, nil, "file %s was deleted", file.Path())
+		return
+	}
+	if current.Package() != file.Package() {
+		c.moves[file.Path()] = current.Package()
+		c.add("FILE_SAME_PACKAGE", CategoryWire, file, current, "file %s moved from package %q to %q", file.Path(), file.Package(), current.Package())
+	}
+}
+
+func (c *checker) checkType(desc protoreflect.Descriptor) {
+	name := c.translateType(desc)
+	current := c.current[name]
+
+	var rule, kind string
+	switch desc.(type) {
+	case protoreflect.MessageDescriptor:
+		rule, kind = "MESSAGE_NO_DELETE", "message"
+	case protoreflect.EnumDescriptor:
+		rule, kind = "ENUM_NO_DELETE", "enum"
+	case protoreflect.ServiceDescriptor:
+		rule, kind = "SERVICE_NO_DELETE", "service"
+	}
+
+	// A type replaced by one of another kind counts as deleted
+	if current == nil || typeKind(current) != kind {
+		category := CategoryPackage
+		if kind == "service" {
+			category = CategoryWire
+		}
+		c.add(rule, category, desc, nil, "%s %s was deleted", kind, name)
+		return
+	}
+
+	// Nested types move along with their parent, so only top-level types are
+	// checked for a change of file
+	if _, topLevel := desc.Parent().(protoreflect.FileDescriptor); topLevel && current.ParentFile().Path() != desc.ParentFile().Path() {
+		c.add(rule, CategoryFile, desc, current, "%s %s was moved from %s to %s", kind, name, desc.ParentFile().Path(), current.ParentFile().Path())
+	}
+
+	switch d := desc.(type) {
+	case protoreflect.MessageDescriptor:
+		c.checkMessage(d, current.(protoreflect.MessageDescriptor))
+	case protoreflect.EnumDescriptor:
+		c.checkEnum(d, current.(protoreflect.EnumDescriptor))
+	case protoreflect.ServiceDescriptor:
+		c.checkService(d, current.(protoreflect.ServiceDescriptor))
+	}
+}
+
+func (c *checker) checkMessage(previous, current protoreflect.MessageDescriptor) {
+	for i := 0; i < previous.Fields().Len(); i++ {
+		field := previous.Fields().Get(i)
+		if next := current.Fields().ByNumber(field.Number()); next != nil {
+			c.checkField(field, next)
+			continue
+		}
+
+		switch next := current.Fields().ByName(field.Name()); {
+		case next != nil:
+			c.add("FIELD_SAME_NUMBER", CategoryWire, field, next, "field %q changed number from %d to %d", field.Name(), field.Number(), next.Number())
+		case !current.ReservedRanges().Has(field.Number()):
+			c.add("FIELD_NO_DELETE", CategoryWire, field, current, "field %d %q was deleted without reserving its number", field.Number(), field.Name())
+		case !current.ReservedNames().Has(field.Name()):
+			c.add("FIELD_NO_DELETE", CategoryWireJSON, field, current, "field %d %q was deleted without reserving its name", field.Number(), field.Name())
+		default:
+			c.add("FIELD_NO_DELETE", CategoryPackage, field, current, "field %d %q was deleted", field.Number(), field.Name())
+		}
+	}
+}
+
+func (c *checker) checkField(previous, current protoreflect.FieldDescriptor) {
+	switch {
+	case previous.Name() != current.Name():
+		category := CategoryWireJSON
+		if previous.JSONName() == current.JSONName() {
+			category = CategoryPackage
+		}
+		c.add("FIELD_SAME_NAME", category, previous, current, "field %d changed name from %q to %q", previous.Number(), previous.Name(), current.Name())
+	case previous.JSONName() != current.JSONName():
+		c.add("FIELD_SAME_JSON_NAME", CategoryWireJSON, previous, current, "field %q changed JSON name from %q to %q", previous.Name(), previous.JSONName(), current.JSONName())
+	}
+
+	if before, after := cardinality(previous), cardinality(current); before != after {
+		category := CategoryPackage
+		if before == "repeated" || after == "repeated" || before == "required" || after == "required" {
+			category = CategoryWire
+		}
+		c.add("FIELD_SAME_CARDINALITY", category, previous, current, "field %q changed cardinality from %s to %s", previous.Name(), before, after)
+	}
+
+	if before, after := c.fieldType(previous, true), c.fieldType(current, false); before != after {
+		category := CategoryWire
+		if wireCompatible(previous, current) {
+			category = CategoryWireJSON
+		}
+		c.add("FIELD_SAME_TYPE", category, previous, current, "field %q changed type from %s to %s", previous.Name(), before, after)
+	}
+
+	if before, after := oneofName(previous), oneofName(current); before != after {
+		c.add("FIELD_SAME_ONEOF", CategoryWire, previous, current, "field %q changed oneof from %s to %s", previous.Name(), orNone(before), orNone(after))
+	}
+}
+
+func (c *checker) checkEnum(previous, current protoreflect.EnumDescriptor) {
+	for i := 0; i < previous.Values().Len(); i++ {
+		value := previous.Values().Get(i)
+		if next := current.Values().ByNumber(value.Number()); next != nil {
+			if next.Name() != value.Name() && current.Values().ByName(value.Name()) == nil {
+				c.add("ENUM_VALUE_SAME_NAME", CategoryWireJSON, value, next, "enum value %d changed name from %s to %s", value.Number(), value.Name(), next.Name())
+			}
+			continue
+		}
+
+		switch next := current.Values().ByName(value.Name()); {
+		case next != nil:
+			c.add("ENUM_VALUE_SAME_NUMBER", CategoryWire, value, next, "enum value %s changed number from %d to %d", value.Name(), value.Number(), next.Number())
+		case !current.ReservedRanges().Has(value.Number()):
+			c.add("ENUM_VALUE_NO_DELETE", CategoryWire, value, current, "enum value %d %s was deleted without reserving its number", value.Number(), value.Name())
+		case !current.ReservedNames().Has(value.Name()):
+			c.add("ENUM_VALUE_NO_DELETE", CategoryWireJSON, value, current, "enum value %d %s was deleted without reserving its name", value.Number(), value.Name())
+		default:
+			c.add("ENUM_VALUE_NO_DELETE", CategoryPackage, value, current, "enum value %d %s was deleted", value.Number(), value.Name())
+		}
+	}
+}
+
+func (c *checker) checkService(previous, current protoreflect.ServiceDescriptor) {
+	for i := 0; i < previous.Methods().Len(); i++ {
+		method := previous.Methods().Get(i)
+		next := current.Methods().ByName(method.Name())
+		if next == nil {
+			if renamed := c.renamedMethod(method, previous, current); renamed != nil {
+				c.add("RPC_NO_DELETE", CategoryWire, method, renamed, "rpc %s was deleted, possibly renamed to %s", method.Name(), renamed.Name())
+			} else {
+				c.add("RPC_NO_DELETE", CategoryWire, method, current, "rpc %s was deleted", method.Name())
+			}
+			continue
+		}
+
+		if before, after := c.translateType(method.Input()), next.Input().FullName(); before != after {
+			c.add("RPC_SAME_REQUEST_TYPE", CategoryWire, method, next, "rpc %s changed request type from %s to %s", method.Name(), before, after)
+		}
+		if before, after := c.translateType(method.Output()), next.Output().FullName(); before != after {
+			c.add("RPC_SAME_RESPONSE_TYPE", CategoryWire, method, next, "rpc %s changed response type from %s to %s", method.Name(), before, after)
+		}
+		if method.IsStreamingClient() != next.IsStreamingClient() {
+			c.add("RPC_SAME_CLIENT_STREAMING", CategoryWire, method, next, "rpc %s changed client streaming from %t to %t", method.Name(), method.IsStreamingClient(), next.IsStreamingClient())
+		}
+		if method.IsStreamingServer() != next.IsStreamingServer() {
+			c.add("RPC_SAME_SERVER_STREAMING", CategoryWire, method, next, "rpc %s changed server streaming from %t to %t", method.Name(), method.IsStreamingServer(), next.IsStreamingServer())
+		}
+	}
+}
+
+// renamedMethod finds a method added to the current service with the same
+// request and response types and streaming as a deleted method
+func (c *checker) renamedMethod(method protoreflect.MethodDescriptor, previous, current protoreflect.ServiceDescriptor) protoreflect.MethodDescriptor {
+	for i := 0; i < current.Methods().Len(); i++ {
+		candidate := current.Methods().Get(i)
+		if previous.Methods().ByName(candidate.Name()) != nil {
+			continue
+		}
+		if candidate.Input().FullName() == c.translateType(method.Input()) &&
+			candidate.Output().FullName() == c.translateType(method.Output()) &&
+			candidate.IsStreamingClient() == method.IsStreamingClient() &&
+			candidate.IsStreamingServer() == method.IsStreamingServer() {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// fieldType describes the type of a field, with message and enum types of
+// previous files translated to their current names
+func (c *checker) fieldType(field protoreflect.FieldDescriptor, previous bool) string {
+	if field.IsMap() {
+		return fmt.Sprintf("map<%s, %s>", c.fieldType(field.MapKey(), previous), c.fieldType(field.MapValue(), previous))
+	}
+
+	var desc protoreflect.Descriptor
+	switch {
+	case field.Message() != nil:
+		desc = field.Message()
+	case field.Enum() != nil:
+		desc = field.Enum()
+	default:
+		return field.Kind().String()
+	}
+	if previous {
+		return string(c.translateType(desc))
+	}
+	return string(desc.FullName())
+}
+
+// cardinality describes the label of a field. Membership of a real oneof is
+// checked separately and does not count as explicit presence here.
+func cardinality(field protoreflect.FieldDescriptor) string {
+	switch {
+	case field.IsMap():
+		return "map"
+	case field.IsList():
+		return "repeated"
+	case field.Cardinality() == protoreflect.Required:
+		return "required"
+	case field.HasPresence() && oneofName(field) == "":
+		return "optional"
+	default:
+		return "implicit"
+	}
+}
+
+// wireCompatibleGroups lists scalar kinds that share an encoding, so changing
+// a field between kinds of the same group keeps existing data readable
+var wireCompatibleGroups = [][]protoreflect.Kind{
+	{protoreflect.Int32Kind, protoreflect.Uint32Kind, protoreflect.Int64Kind, protoreflect.Uint64Kind, protoreflect.BoolKind, protoreflect.EnumKind},
+	{protoreflect.Sint32Kind, protoreflect.Sint64Kind},
+	{protoreflect.Fixed32Kind, protoreflect.Sfixed32Kind},
+	{protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind},
+	{protoreflect.StringKind, protoreflect.BytesKind},
+}
+
+// wireCompatible reports whether a type change keeps the binary encoding
+func wireCompatible(previous, current protoreflect.FieldDescriptor) bool {
+	if previous.IsMap() || current.IsMap() {
+		return false
+	}
+	if previous.Kind() == current.Kind() {
+		// Only the referenced enum changed, which is encoded the same way
+		return previous.Kind() == protoreflect.EnumKind
+	}
+	for _, group := range wireCompatibleGroups {
+		if slices.Contains(group, previous.Kind()) && slices.Contains(group, current.Kind()) {
+			return true
+		}
+	}
+	return false
+}
+
+// oneofName returns the name of the real oneof containing a field, or an
+// empty string
+func oneofName(field protoreflect.FieldDescriptor) protoreflect.Name {
+	if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+		return oneof.Name()
+	}
+	return ""
+}
+
+func orNone(name protoreflect.Name) string {
+	if name == "" {
+		return "none"
+	}
+	return string(name)
+}
+
+func joinName(pkg protoreflect.FullName, name string) protoreflect.FullName {
+	if pkg == "" {
+		return protoreflect.FullName(name)
+	}
+	return protoreflect.FullName(string(pkg) + "." + name)
+}
+
+// typeKind returns the kind of a type matched by name
+func typeKind(desc protoreflect.Descriptor) string {
+	switch desc.(type) {
+	case protoreflect.MessageDescriptor:
+		return "message"
+	case protoreflect.EnumDescriptor:
+		return "enum"
+	case protoreflect.ServiceDescriptor:
+		return "service"
+	default:
+		return ""
+	}
+}
+
+// forEachType calls fn for every message, enum and service declared in a
+// file, including nested messages and enums but excluding map entries
+func forEachType(file protoreflect.FileDescriptor, fn func(protoreflect.Descriptor)) {
+	var visitMessages func(messages protoreflect.MessageDescriptors)
+	visitMessages = func(messages protoreflect.MessageDescriptors) {
+		for i := 0; i < messages.Len(); i++ {
+			message := messages.Get(i)
+			if message.IsMapEntry() {
+				continue
+			}
+			fn(message)
+			for j := 0; j < message.Enums().Len(); j++ {
+				fn(message.Enums().Get(j))
+			}
+			visitMessages(message.Messages())
+		}
+	}
+	visitMessages(file.Messages())
+	for i := 0; i < file.Enums().Len(); i++ {
+		fn(file.Enums().Get(i))
+	}
+	for i := 0; i < file.Services().Len(); i++ {
+		fn(file.Services().Get(i))
+	}
+}
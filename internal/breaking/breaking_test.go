@@ -0,0 +1,297 @@
+package breaking
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/testutil"
+)
+
+// rules returns "RULE CATEGORY element" for each violation
+func rules(violations []Violation) []string {
+	result := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		result = append(result, violation.Rule+" "+violation.Category+" "+violation.Element)
+	}
+	return result
+}
+
+func TestCheck(t *testing.T) {
+	previous := testutil.CompileDescriptors(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test.v1;
+
+message Book {
+  string name = 1;
+  int32 pages = 2;
+  string title = 3;
+  string isbn = 4;
+  repeated string tags = 5;
+  int64 likes = 6;
+  string author = 7;
+  string notes = 8;
+  oneof source {
+    string url = 9;
+  }
+}
+
+message Unused {}
+
+enum State {
+  STATE_UNSPECIFIED = 0;
+  STATE_DRAFT = 1;
+  STATE_PUBLISHED = 2;
+  STATE_ARCHIVED = 3;
+}
+
+service Library {
+  rpc GetBook(Book) returns (Book);
+  rpc ListBooks(Book) returns (Book);
+  rpc WatchBooks(Book) returns (stream Book);
+}
+`,
+		"old.proto": `syntax = "proto3";
+package test.v1;
+`,
+	})
+	current := testutil.CompileDescriptors(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test.v1;
+
+message Book {
+  string name = 1;
+  reserved 2;
+  string book_title = 3;
+  bytes isbn = 4;
+  string tags = 5;
+  string likes = 6;
+  string author = 17;
+  string notes = 8 [json_name = "remarks"];
+  string url = 9;
+}
+
+enum State {
+  STATE_UNSPECIFIED = 0;
+  STATE_WRITING = 1;
+  reserved 2;
+  reserved "STATE_PUBLISHED";
+}
+
+service Library {
+  rpc FetchBook(Book) returns (Book);
+  rpc ListBooks(stream Book) returns (Book);
+  rpc WatchBooks(Book) returns (stream Shelf);
+}
+
+message Shelf {}
+`,
+	})
+
+	got := rules(Check(previous, current))
+	// Violations are ordered by file, then element
+	want := []string{
+		"FIELD_SAME_NUMBER WIRE test.v1.Book.author",
+		"FIELD_SAME_TYPE WIRE_JSON test.v1.Book.isbn",
+		"FIELD_SAME_TYPE WIRE test.v1.Book.likes",
+		"FIELD_SAME_JSON_NAME WIRE_JSON test.v1.Book.notes",
+		"FIELD_NO_DELETE WIRE_JSON test.v1.Book.pages",
+		"FIELD_SAME_CARDINALITY WIRE test.v1.Book.tags",
+		"FIELD_SAME_NAME WIRE_JSON test.v1.Book.title",
+		"FIELD_SAME_ONEOF WIRE test.v1.Book.url",
+		"RPC_NO_DELETE WIRE test.v1.Library.GetBook",
+		"RPC_SAME_CLIENT_STREAMING WIRE test.v1.Library.ListBooks",
+		"RPC_SAME_RESPONSE_TYPE WIRE test.v1.Library.WatchBooks",
+		"ENUM_VALUE_NO_DELETE WIRE test.v1.STATE_ARCHIVED",
+		"ENUM_VALUE_SAME_NAME WIRE_JSON test.v1.STATE_DRAFT",
+		"ENUM_VALUE_NO_DELETE PACKAGE test.v1.STATE_PUBLISHED",
+		"MESSAGE_NO_DELETE PACKAGE test.v1.Unused",
+		"FILE_NO_DELETE FILE ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected violations:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestCheck_RenamedMethodHint(t *testing.T) {
+	previous := testutil.CompileDescriptors(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test;
+
+message Request {}
+service Books { rpc GetBook(Request) returns (Request); }
+`,
+	})
+	current := testutil.CompileDescriptors(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test;
+
+message Request {}
+service Books { rpc FetchBook(Request) returns (Request); }
+`,
+	})
+
+	violations := Check(previous, current)
+	if len(violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %+v", violations)
+	}
+	if want := "rpc GetBook was deleted, possibly renamed to FetchBook"; violations[0].Message != want {
+		t.Errorf("Expected message %q, got %q", want, violations[0].Message)
+	}
+}
+
+func TestCheck_PackageMove(t *testing.T) {
+	previous := testutil.CompileDescriptors(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test.v1;
+
+message Book { string name = 1; Author author = 2; }
+message Author { string name = 1; }
+service Books { rpc GetBook(Book) returns (Book); }
+`,
+	})
+	current := testutil.CompileDescriptors(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test.v2;
+
+message Book { string name = 1; Author author = 2; }
+message Author { string name = 1; }
+service Books { rpc GetBook(Book) returns (Book); }
+`,
+	})
+
+	got := rules(Check(previous, current))
+	want := []string{"FILE_SAME_PACKAGE WIRE "}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected only the package move, got %q", got)
+	}
+}
+
+func TestCheck_NoChanges(t *testing.T) {
+	sources := map[string]string{
+		"api.proto": `syntax = "proto3";
+package test;
+
+message Book { string name = 1; map<string, int32> counts = 2; }
+`,
+	}
+	if violations := Check(testutil.CompileDescriptors(t, sources), testutil.CompileDescriptors(t, sources)); len(violations) != 0 {
+		t.Errorf("Expected no violations, got %+v", violations)
+	}
+}
+
+func TestCheck_MovedToAnotherFile(t *testing.T) {
+	previous := testutil.CompileDescriptors(t, map[string]string{
+		"a.proto": `syntax = "proto3";
+package test;
+
+message Book { string name = 1; }
+`,
+		"b.proto": `syntax = "proto3";
+package test;
+`,
+	})
+	current := testutil.CompileDescriptors(t, map[string]string{
+		"a.proto": `syntax = "proto3";
+package test;
+`,
+		"b.proto": `syntax = "proto3";
+package test;
+
+message Book { string name = 1; }
+`,
+	})
+
+	violations := Check(previous, current)
+	got := rules(violations)
+	want := []string{"MESSAGE_NO_DELETE FILE test.Book"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected only the file move, got %q", got)
+	}
+	if violations[0].File != "b.proto" {
+		t.Errorf("Expected the violation in b.proto, got %s", violations[0].File)
+	}
+	if len(Filter(violations, []string{CategoryPackage})) != 0 {
+		t.Errorf("Expected moving a type within a package to pass the PACKAGE category")
+	}
+}
+
+func TestCheck_Locations(t *testing.T) {
+	previous := testutil.CompileDescriptors(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test;
+
+message Book {
+  string name = 1;
+  string title = 2;
+}
+`,
+	})
+	current := testutil.CompileDescriptors(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test;
+
+// A book
+message Book {
+  string name = 1;
+}
+`,
+	})
+
+	violations := Check(previous, current)
+	if len(violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %+v", violations)
+	}
+	violation := violations[0]
+
+	// The deleted field is located at its message in the current file
+	if violation.Location == nil || violation.Location.StartLine != 5 {
+		t.Errorf("Expected location at line 5, got %+v", violation.Location)
+	}
+	if violation.PreviousLocation == nil || violation.PreviousLocation.StartLine != 6 || violation.PreviousLocation.StartColumn != 3 {
+		t.Errorf("Expected previous location at 6:3, got %+v", violation.PreviousLocation)
+	}
+}
+
+func TestParseCategories(t *testing.T) {
+	categories, err := ParseCategories([]string{" wire_json ", ""})
+	if err != nil {
+		t.Fatalf("ParseCategories failed: %v", err)
+	}
+	if !reflect.DeepEqual(categories, []string{CategoryWireJSON}) {
+		t.Errorf("Expected [WIRE_JSON], got %v", categories)
+	}
+
+	categories, err = ParseCategories(nil)
+	if err != nil || !reflect.DeepEqual(categories, []string{CategoryFile}) {
+		t.Errorf("Expected FILE by default, got %v, %v", categories, err)
+	}
+
+	if _, err := ParseCategories([]string{"SOURCE"}); err == nil {
+		t.Errorf("Expected an error for an unknown category")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	violations := []Violation{
+		{Rule: "FILE_NO_DELETE", Category: CategoryFile},
+		{Rule: "MESSAGE_NO_DELETE", Category: CategoryPackage},
+		{Rule: "FIELD_SAME_NAME", Category: CategoryWireJSON},
+		{Rule: "FIELD_NO_DELETE", Category: CategoryWire},
+	}
+
+	tests := []struct {
+		categories []string
+		expected   int
+	}{
+		{[]string{CategoryFile}, 4},
+		{[]string{CategoryPackage}, 3},
+		{[]string{CategoryWireJSON}, 2},
+		{[]string{CategoryWire}, 1},
+		{[]string{CategoryWire, CategoryPackage}, 3},
+	}
+	for _, tt := range tests {
+		if got := Filter(violations, tt.categories); len(got) != tt.expected {
+			t.Errorf("Filter(%v): expected %d violations, got %+v", tt.categories, tt.expected, got)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 
 	"github.com/bufbuild/protocompile"
@@ -197,3 +198,43 @@ func (p *ProtobufProject) GetEnums(files linker.Files) ([]protoreflect.EnumDescr
 
 	return enums, nil
 }
+
+// SourceFiles returns the proto files of the project, as sorted
+// slash-separated paths relative to the project root
+func (p *ProtobufProject) SourceFiles() ([]string, error) {
+	paths, err := config.ResolveProtoFiles(p.Config, p.ProjectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve proto files: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, path := range paths {
+		rel, err := filepath.Rel(p.ProjectRoot, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get relative path for %s: %w", path, err)
+		}
+		rel = filepath.ToSlash(rel)
+		if !seen[rel] {
+			seen[rel] = true
+			files = append(files, rel)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// SourcePath returns the path on disk of a proto file, given its path as
+// imported. Each import path is tried in order, like the compiler does.
+func (p *ProtobufProject) SourcePath(file string) (string, error) {
+	for _, importPath := range p.Config.ImportPaths {
+		if !filepath.IsAbs(importPath) {
+			importPath = filepath.Join(p.ProjectRoot, importPath)
+		}
+		path := filepath.Join(importPath, filepath.FromSlash(file))
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("source file %s not found in import paths", file)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/yuemori/protobuf-mcp-server/internal/config"
@@ -144,3 +145,61 @@ func TestCompileProtosWithNestedImportPaths(t *testing.T) {
 		t.Errorf("Expected at least 2 files (hoge.proto and foo.proto), got %d", len(compiledProtos))
 	}
 }
+
+func TestSourcePath(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "proto", "foo"), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	protoFile := filepath.Join(tempDir, "proto", "foo", "foo.proto")
+	if err := os.WriteFile(protoFile, []byte(`syntax = "proto3";`), 0o644); err != nil {
+		t.Fatalf("Failed to create test proto file: %v", err)
+	}
+
+	project := &ProtobufProject{
+		ProjectRoot: tempDir,
+		Config: &config.ProjectConfig{
+			ImportPaths: []string{".", "proto"},
+		},
+	}
+
+	path, err := project.SourcePath("foo/foo.proto")
+	if err != nil {
+		t.Fatalf("SourcePath failed: %v", err)
+	}
+	if path != protoFile {
+		t.Errorf("Expected %s, got %s", protoFile, path)
+	}
+
+	if _, err := project.SourcePath("bar/bar.proto"); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+}
+
+func TestSourceFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, file := range []string{"b/b.proto", "a.proto", "b/c.txt"} {
+		path := filepath.Join(tempDir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(`syntax = "proto3";`), 0o644); err != nil {
+			t.Fatalf("Failed to create %s: %v", file, err)
+		}
+	}
+
+	project := &ProtobufProject{
+		ProjectRoot: tempDir,
+		Config: &config.ProjectConfig{
+			ProtoFiles: []string{"**/*.proto", "b/*.proto"},
+		},
+	}
+
+	files, err := project.SourceFiles()
+	if err != nil {
+		t.Fatalf("SourceFiles failed: %v", err)
+	}
+	if expected := []string{"a.proto", "b/b.proto"}; !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %v, got %v", expected, files)
+	}
+}
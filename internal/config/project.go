@@ -11,8 +11,35 @@ import (
 
 // ProjectConfig represents the configuration for a protobuf project
 type ProjectConfig struct {
-	ProtoFiles  []string `yaml:"proto_files"`
-	ImportPaths []string `yaml:"import_paths"`
+	ProtoFiles  []string       `yaml:"proto_files"`
+	ImportPaths []string       `yaml:"import_paths"`
+	Breaking    BreakingConfig `yaml:"breaking"`
+	Lint        LintConfig     `yaml:"lint"`
+}
+
+// BreakingConfig configures breaking-change detection
+type BreakingConfig struct {
+	// Categories selects the rule categories to report: FILE, PACKAGE,
+	// WIRE_JSON or WIRE. Defaults to FILE, which reports every violation.
+	Categories []string `yaml:"categories"`
+	// Baseline is the FileDescriptorSet written by the snapshot command,
+	// relative to the project root
+	Baseline string `yaml:"baseline"`
+}
+
+// LintConfig configures the proto style linter. Files are the paths of
+// proto files as imported, e.g. "foo/v1/foo.proto"; a directory stands for
+// every file below it and glob patterns are accepted.
+type LintConfig struct {
+	// Rules selects the rules to run, by rule ID or group name. Defaults to
+	// the DEFAULT group.
+	Rules []string `yaml:"rules"`
+	// Except removes rules or groups from the selection
+	Except []string `yaml:"except"`
+	// Ignore lists files that are not linted at all
+	Ignore []string `yaml:"ignore"`
+	// IgnoreOnly lists, per rule ID or group name, files the rule skips
+	IgnoreOnly map[string][]string `yaml:"ignore_only"`
 }
 
 // DefaultProjectConfig returns a default configuration for a new project
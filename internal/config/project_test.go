@@ -412,3 +412,67 @@ import_paths:
 		})
 	}
 }
+
+func TestLoadProjectConfigWithBreakingSection(t *testing.T) {
+	tempDir := t.TempDir()
+	content := `proto_files:
+  - "**/*.proto"
+breaking:
+  categories:
+    - WIRE_JSON
+  baseline: build/baseline.binpb
+`
+	if err := os.WriteFile(filepath.Join(tempDir, ".protobuf-mcp.yml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	config, err := LoadProjectConfig(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if len(config.Breaking.Categories) != 1 || config.Breaking.Categories[0] != "WIRE_JSON" {
+		t.Errorf("Expected breaking categories [WIRE_JSON], got %v", config.Breaking.Categories)
+	}
+	if config.Breaking.Baseline != "build/baseline.binpb" {
+		t.Errorf("Expected baseline build/baseline.binpb, got %s", config.Breaking.Baseline)
+	}
+}
+
+func TestLoadProjectConfigWithLintSection(t *testing.T) {
+	tempDir := t.TempDir()
+	content := `proto_files:
+  - "**/*.proto"
+lint:
+  rules:
+    - DEFAULT
+  except:
+    - COMMENTS
+  ignore:
+    - vendor/**
+  ignore_only:
+    FIELD_LOWER_SNAKE_CASE:
+      - legacy/v1/legacy.proto
+`
+	if err := os.WriteFile(filepath.Join(tempDir, ".protobuf-mcp.yml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	config, err := LoadProjectConfig(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if len(config.Lint.Rules) != 1 || config.Lint.Rules[0] != "DEFAULT" {
+		t.Errorf("Expected lint rules [DEFAULT], got %v", config.Lint.Rules)
+	}
+	if len(config.Lint.Except) != 1 || config.Lint.Except[0] != "COMMENTS" {
+		t.Errorf("Expected lint except [COMMENTS], got %v", config.Lint.Except)
+	}
+	if len(config.Lint.Ignore) != 1 || config.Lint.Ignore[0] != "vendor/**" {
+		t.Errorf("Expected lint ignore [vendor/**], got %v", config.Lint.Ignore)
+	}
+	if files := config.Lint.IgnoreOnly["FIELD_LOWER_SNAKE_CASE"]; len(files) != 1 || files[0] != "legacy/v1/legacy.proto" {
+		t.Errorf("Expected ignore_only for FIELD_LOWER_SNAKE_CASE, got %v", config.Lint.IgnoreOnly)
+	}
+}
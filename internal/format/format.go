@@ -0,0 +1,524 @@
+// Package format rewrites proto source files in a canonical style.
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bufbuild/protocompile/ast"
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
+)
+
+// Source formats the content of a proto file. The file is parsed, not
+// compiled, so its imports need not resolve; filename is used in errors.
+//
+// The output is indented with two spaces and has one declaration per line.
+// The syntax statement comes first, followed by the package, the imports
+// sorted by path and the file options, and top-level declarations are
+// separated by blank lines. Inside blocks, single blank lines between
+// declarations are kept. Compact options with more than one option and
+// message values of options are laid out one entry per line. Every comment
+// is kept next to the token it belongs to.
+func Source(filename string, content []byte) ([]byte, error) {
+	file, err := parser.Parse(filename, bytes.NewReader(content), reporter.NewHandler(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &printer{file: file, atLineStart: true}
+	p.printFile()
+	return []byte(p.b.String()), nil
+}
+
+// spacing tells how the first token of a node is separated from the
+// previous one
+type spacing int
+
+const (
+	autoSpace spacing = iota
+	withSpace
+	noSpace
+	// nameSpace separates a token like the start of a name, regardless of
+	// its text
+	nameSpace
+)
+
+// printer writes the tokens and comments of a file with canonical
+// whitespace
+type printer struct {
+	file  *ast.FileNode
+	b     strings.Builder
+	depth int
+	// prev is the text of the last token written
+	prev string
+	// afterComment is set when a comment was written after prev, which is
+	// then separated from the next token
+	afterComment bool
+	atLineStart  bool
+	// midStatement is set while a declaration has more tokens to print
+	midStatement bool
+	// continued is set when a comment broke a declaration onto another
+	// line, which is then indented one level deeper
+	continued bool
+	// pendingBreak is set after a line comment, which must end its line
+	pendingBreak bool
+	// afterOpen is set right after the opening brace of a block, where no
+	// blank line is written
+	afterOpen bool
+}
+
+// printFile prints the whole file
+func (p *printer) printFile() {
+	var syntax, packages, imports, options, declarations []ast.Node
+	if p.file.Syntax != nil {
+		syntax = append(syntax, p.file.Syntax)
+	} else if p.file.Edition != nil {
+		syntax = append(syntax, p.file.Edition)
+	}
+	for _, decl := range p.file.Decls {
+		switch decl := decl.(type) {
+		case *ast.PackageNode:
+			packages = append(packages, decl)
+		case *ast.ImportNode:
+			imports = append(imports, decl)
+		case *ast.OptionNode:
+			options = append(options, decl)
+		default:
+			declarations = append(declarations, decl)
+		}
+	}
+	sort.SliceStable(imports, func(i, j int) bool {
+		return imports[i].(*ast.ImportNode).Name.AsString() < imports[j].(*ast.ImportNode).Name.AsString()
+	})
+
+	// Sections are separated by a blank line. Imports are sorted, so their
+	// blank lines are dropped; other top-level declarations always get one.
+	first := true
+	section := func(nodes []ast.Node, blank func(n ast.Node) bool) {
+		started := false
+		for _, n := range nodes {
+			if p.omitted(n) {
+				continue
+			}
+			p.newline()
+			if !first && (!started || blank(n)) {
+				p.blank()
+			}
+			first, started = false, true
+			p.statement(n)
+		}
+	}
+	never := func(ast.Node) bool { return false }
+	section(syntax, never)
+	section(packages, never)
+	section(imports, never)
+	section(options, p.blankBefore)
+	section(declarations, func(ast.Node) bool { return true })
+
+	p.leadingComments(p.file.EOF)
+	p.newline()
+}
+
+// statements prints the declarations of a block, keeping single blank lines
+// from the source
+func (p *printer) statements(nodes []ast.Node) {
+	first := true
+	for _, n := range nodes {
+		if p.omitted(n) {
+			continue
+		}
+		p.newline()
+		if !first && p.blankBefore(n) {
+			p.blank()
+		}
+		first = false
+		p.statement(n)
+	}
+}
+
+// omitted reports whether a declaration is dropped from the output. Empty
+// declarations are dropped, but their comments are kept.
+func (p *printer) omitted(n ast.Node) bool {
+	empty, ok := n.(*ast.EmptyDeclNode)
+	if !ok {
+		return false
+	}
+	if p.hasComments(empty.Semicolon) {
+		p.leadingComments(empty.Semicolon)
+		p.trailingComments(empty.Semicolon)
+	}
+	return true
+}
+
+// statement prints a declaration. Declarations with a body print their
+// header on one line and their members as nested statements.
+func (p *printer) statement(n ast.Node) {
+	open, close := braces(n)
+	if open == nil {
+		p.inline(n, autoSpace)
+		return
+	}
+
+	var members []ast.Node
+	inBody := false
+	for _, child := range n.(ast.CompositeNode).Children() {
+		switch {
+		case child == ast.Node(open):
+			inBody = true
+		case child == ast.Node(close):
+			inBody = false
+		case inBody:
+			members = append(members, child)
+		default:
+			p.inline(child, spacingOf(n, child))
+		}
+	}
+	p.block(open, close, members, p.statements)
+}
+
+// braces returns the braces enclosing the body of a declaration, or nils if
+// it has none
+func braces(n ast.Node) (*ast.RuneNode, *ast.RuneNode) {
+	switch n := n.(type) {
+	case *ast.MessageNode:
+		return n.OpenBrace, n.CloseBrace
+	case *ast.GroupNode:
+		return n.OpenBrace, n.CloseBrace
+	case *ast.EnumNode:
+		return n.OpenBrace, n.CloseBrace
+	case *ast.ServiceNode:
+		return n.OpenBrace, n.CloseBrace
+	case *ast.OneofNode:
+		return n.OpenBrace, n.CloseBrace
+	case *ast.ExtendNode:
+		return n.OpenBrace, n.CloseBrace
+	case *ast.RPCNode:
+		return n.OpenBrace, n.CloseBrace
+	}
+	return nil, nil
+}
+
+// block prints braces around members, one per line, or {} when there is
+// nothing inside
+func (p *printer) block(open, close *ast.RuneNode, members []ast.Node, print func([]ast.Node)) {
+	p.token(open, withSpace)
+	if !p.hasContent(members) && p.file.NodeInfo(open).TrailingComments().Len() == 0 &&
+		p.file.NodeInfo(close).LeadingComments().Len() == 0 {
+		p.token(close, noSpace)
+		return
+	}
+
+	p.depth++
+	p.afterOpen = true
+	print(members)
+	// Comments before the closing brace stay inside the block
+	p.newline()
+	p.leadingComments(close)
+	p.depth--
+	p.newline()
+	p.emit(close, autoSpace)
+}
+
+// hasContent reports whether printing members writes anything, which is
+// not the case for empty declarations without comments
+func (p *printer) hasContent(members []ast.Node) bool {
+	for _, member := range members {
+		if empty, ok := member.(*ast.EmptyDeclNode); !ok || p.hasComments(empty.Semicolon) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasComments reports whether a token has leading or trailing comments
+func (p *printer) hasComments(n ast.Node) bool {
+	info := p.file.NodeInfo(n)
+	return info.LeadingComments().Len() > 0 || info.TrailingComments().Len() > 0
+}
+
+// inline prints a node within a line, separating its tokens with single
+// spaces where the grammar allows. Compact options and message values
+// break lines as needed.
+func (p *printer) inline(n ast.Node, space spacing) {
+	switch n := n.(type) {
+	case *ast.CompactOptionsNode:
+		p.compactOptions(n, space)
+	case *ast.MessageLiteralNode:
+		p.messageLiteral(n, space)
+	case ast.TerminalNode:
+		p.token(n, space)
+	case ast.CompositeNode:
+		for i, child := range n.Children() {
+			childSpace := spacingOf(n, child)
+			if i == 0 && space != autoSpace {
+				childSpace = space
+			}
+			p.inline(child, childSpace)
+		}
+	}
+}
+
+// spacingOf returns the spacing before a child that depends on its parent:
+// the input of an RPC follows its name directly, and the leading dot of a
+// fully-qualified name is separated like a name
+func spacingOf(parent, child ast.Node) spacing {
+	switch parent := parent.(type) {
+	case *ast.RPCNode:
+		if child == ast.Node(parent.Input) {
+			return noSpace
+		}
+	case *ast.CompoundIdentNode:
+		if parent.LeadingDot != nil && child == ast.Node(parent.LeadingDot) {
+			return nameSpace
+		}
+	}
+	return autoSpace
+}
+
+// compactOptions prints bracketed field options, on one line when there is
+// a single option and one option per line otherwise
+func (p *printer) compactOptions(n *ast.CompactOptionsNode, space spacing) {
+	multiline := len(n.Options) > 1
+	p.token(n.OpenBracket, space)
+	if multiline {
+		p.depth++
+		p.afterOpen = true
+	}
+	for _, child := range n.Children() {
+		switch child := child.(type) {
+		case *ast.OptionNode:
+			if multiline {
+				p.newline()
+			}
+			p.inline(child, autoSpace)
+		case *ast.RuneNode:
+			if child != n.OpenBracket && child != n.CloseBracket {
+				p.token(child, autoSpace)
+			}
+		}
+	}
+	if !multiline {
+		p.token(n.CloseBracket, autoSpace)
+		return
+	}
+	p.newline()
+	p.leadingComments(n.CloseBracket)
+	p.depth--
+	p.newline()
+	p.emit(n.CloseBracket, autoSpace)
+}
+
+// messageLiteral prints a message value in text format, one field per line
+func (p *printer) messageLiteral(n *ast.MessageLiteralNode, space spacing) {
+	var members []ast.Node
+	for _, child := range n.Children() {
+		if child != ast.Node(n.Open) && child != ast.Node(n.Close) {
+			members = append(members, child)
+		}
+	}
+	p.token(n.Open, space)
+	if len(members) == 0 && p.file.NodeInfo(n.Open).TrailingComments().Len() == 0 &&
+		p.file.NodeInfo(n.Close).LeadingComments().Len() == 0 {
+		p.token(n.Close, noSpace)
+		return
+	}
+
+	p.depth++
+	p.afterOpen = true
+	for _, member := range members {
+		// Separators between fields stay attached to the previous field
+		if _, ok := member.(*ast.MessageFieldNode); ok {
+			p.newline()
+		}
+		p.inline(member, autoSpace)
+	}
+	p.newline()
+	p.leadingComments(n.Close)
+	p.depth--
+	p.newline()
+	p.emit(n.Close, autoSpace)
+}
+
+// token prints a token with its comments
+func (p *printer) token(n ast.Node, space spacing) {
+	p.leadingComments(n)
+	p.emit(n, space)
+}
+
+// emit prints a token and its trailing comments, but not its leading
+// comments
+func (p *printer) emit(n ast.Node, space spacing) {
+	info := p.file.NodeInfo(n)
+	text := info.RawText()
+	p.write(text, p.spaceBefore(text, space))
+	p.prev = text
+	p.afterComment = false
+	p.midStatement = text != ";" && text != "{" && text != "}"
+	p.trailingComments(n)
+}
+
+// spaceBefore tells whether a token is separated from the previous one
+func (p *printer) spaceBefore(text string, space spacing) bool {
+	if p.afterComment {
+		return true
+	}
+	switch space {
+	case withSpace:
+		return true
+	case noSpace:
+		return false
+	}
+	switch p.prev {
+	case "(", "[", "<", ".", "/", "-":
+		return false
+	}
+	if space == nameSpace {
+		return true
+	}
+	switch text {
+	case ")", "]", ">", ";", ",", ".", ":", "/":
+		return false
+	case "}":
+		return p.prev != "{"
+	case "<":
+		return p.prev != "map"
+	}
+	return true
+}
+
+// leadingComments prints the comments before a token, each on its own line
+// unless it shares a line with other tokens in the source
+func (p *printer) leadingComments(n ast.Node) {
+	info := p.file.NodeInfo(n)
+	comments := info.LeadingComments()
+	for i := 0; i < comments.Len(); i++ {
+		p.comment(comments.Index(i))
+	}
+	if comments.Len() == 0 {
+		return
+	}
+	if lines := newlines(info.LeadingWhitespace()); lines > 0 {
+		p.breakLine()
+		if lines > 1 {
+			p.blank()
+		}
+	}
+}
+
+// trailingComments prints the comments after a token
+func (p *printer) trailingComments(n ast.Node) {
+	comments := p.file.NodeInfo(n).TrailingComments()
+	for i := 0; i < comments.Len(); i++ {
+		p.comment(comments.Index(i))
+	}
+}
+
+// comment prints a comment, keeping whether it started a new line and
+// single blank lines before it
+func (p *printer) comment(c ast.Comment) {
+	if lines := newlines(c.LeadingWhitespace()); lines > 0 {
+		p.breakLine()
+		if lines > 1 {
+			p.blank()
+		}
+	}
+	text := strings.TrimRightFunc(c.RawText(), unicode.IsSpace)
+	p.write(text, true)
+	p.afterComment = true
+	if strings.HasPrefix(text, "//") {
+		p.pendingBreak = true
+	}
+}
+
+// blankBefore reports whether a declaration is preceded by a blank line in
+// the source, before its comments if it has any
+func (p *printer) blankBefore(n ast.Node) bool {
+	info := p.file.TokenInfo(n.Start())
+	if comments := info.LeadingComments(); comments.Len() > 0 {
+		return newlines(comments.Index(0).LeadingWhitespace()) > 1
+	}
+	return newlines(info.LeadingWhitespace()) > 1
+}
+
+// write writes text on the current line, indenting it if the line is new
+func (p *printer) write(text string, space bool) {
+	if p.pendingBreak {
+		p.breakLine()
+	}
+	if p.atLineStart {
+		indent := p.depth
+		if p.continued {
+			indent++
+		}
+		p.b.WriteString(strings.Repeat("  ", indent))
+		p.atLineStart = false
+	} else if space {
+		p.b.WriteByte(' ')
+	}
+	p.b.WriteString(text)
+	p.afterOpen = false
+}
+
+// newline ends the current line before a new declaration or a closing brace
+func (p *printer) newline() {
+	p.breakLine()
+	p.midStatement = false
+	p.continued = false
+}
+
+// breakLine ends the current line, if anything was written on it. Breaking
+// a declaration continues it on a deeper indented line.
+func (p *printer) breakLine() {
+	if !p.atLineStart {
+		p.b.WriteByte('\n')
+		p.atLineStart = true
+		p.continued = p.continued || p.midStatement
+	}
+	p.pendingBreak = false
+}
+
+// blank ends the current line and adds a blank line, except at the start of
+// the file or of a block
+func (p *printer) blank() {
+	p.breakLine()
+	if p.b.Len() == 0 || p.afterOpen || strings.HasSuffix(p.b.String(), "\n\n") {
+		return
+	}
+	p.b.WriteByte('\n')
+}
+
+// newlines counts the line breaks in whitespace
+func newlines(whitespace string) int {
+	return strings.Count(whitespace, "\n")
+}
+
+// File formats a proto file on disk and reports whether formatting changes
+// it. The file is only rewritten when write is set.
+func File(path string, write bool) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	formatted, err := Source(path, content)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(formatted, content) {
+		return false, nil
+	}
+	if write {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, formatted, info.Mode().Perm()); err != nil {
+			return false, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return true, nil
+}
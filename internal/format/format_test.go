@@ -0,0 +1,295 @@
+package format
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "layout",
+			input: `syntax="proto3";
+option java_package="com.example";
+import "b.proto";
+package example.v1;
+import public "a.proto";
+message Book{
+	string title=1;
+
+
+	  repeated .example.v1.Author authors=2 [deprecated=true];
+map<string,int32>counts=3;
+  oneof kind{string isbn=4;}
+  reserved 5 to 9,11;
+  reserved "old";
+  message Empty{;}
+}
+enum State{STATE_UNSPECIFIED=0;STATE_NEGATIVE=-1;}
+service Library{rpc GetBook ( GetBookRequest )returns(stream Book);rpc Ping(Empty)returns(Empty){}}
+`,
+			expected: `syntax = "proto3";
+
+package example.v1;
+
+import public "a.proto";
+import "b.proto";
+
+option java_package = "com.example";
+
+message Book {
+  string title = 1;
+
+  repeated .example.v1.Author authors = 2 [deprecated = true];
+  map<string, int32> counts = 3;
+  oneof kind {
+    string isbn = 4;
+  }
+  reserved 5 to 9, 11;
+  reserved "old";
+  message Empty {}
+}
+
+enum State {
+  STATE_UNSPECIFIED = 0;
+  STATE_NEGATIVE = -1;
+}
+
+service Library {
+  rpc GetBook(GetBookRequest) returns (stream Book);
+  rpc Ping(Empty) returns (Empty) {}
+}
+`,
+		},
+		{
+			name: "options",
+			input: `syntax = "proto3";
+package example.v1;
+service Library {
+  rpc GetBook(GetBookRequest) returns (Book) {
+    option (google.api.http) = { get: "/v1/{name=books/*}" additional_bindings { get: "/v1/books/{id}" } };
+  }
+}
+message Book {
+  string name = 1 [json_name = "bookName", (validate.rules).string = {min_len: 1, max_len: 10}, deprecated = true];
+  repeated int32 ids = 2 [(example.v1.ids) = {values: [1, 2], any: {[type.googleapis.com/example.v1.Book] {}}}];
+}
+`,
+			expected: `syntax = "proto3";
+
+package example.v1;
+
+service Library {
+  rpc GetBook(GetBookRequest) returns (Book) {
+    option (google.api.http) = {
+      get: "/v1/{name=books/*}"
+      additional_bindings {
+        get: "/v1/books/{id}"
+      }
+    };
+  }
+}
+
+message Book {
+  string name = 1 [
+    json_name = "bookName",
+    (validate.rules).string = {
+      min_len: 1,
+      max_len: 10
+    },
+    deprecated = true
+  ];
+  repeated int32 ids = 2 [(example.v1.ids) = {
+    values: [1, 2],
+    any: {
+      [type.googleapis.com/example.v1.Book] {}
+    }
+  }];
+}
+`,
+		},
+		{
+			name: "comments",
+			input: `// License header
+
+syntax = "proto3";
+
+// The package
+package example.v1;
+
+// Detached comment
+
+
+// A book
+message Book { // Opening brace
+  /* Block */ string title = 1; // Trailing
+                                // continued
+
+  string author = 2
+    // Inside a declaration
+    [deprecated = true];
+  string isbn = 3 [/* Lead */ deprecated = true /* Trail */];
+  // Before the closing brace
+}
+;
+// End of file
+`,
+			expected: `// License header
+
+syntax = "proto3";
+
+// The package
+package example.v1;
+
+// Detached comment
+
+// A book
+message Book { // Opening brace
+  /* Block */ string title = 1; // Trailing
+  // continued
+
+  string author = 2
+    // Inside a declaration
+    [deprecated = true];
+  string isbn = 3 [ /* Lead */ deprecated = true /* Trail */ ];
+  // Before the closing brace
+}
+// End of file
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := Source("test.proto", []byte(tt.input))
+			if err != nil {
+				t.Fatalf("Source failed: %v", err)
+			}
+			if string(output) != tt.expected {
+				t.Errorf("Unexpected output:\n%s\nExpected:\n%s", output, tt.expected)
+			}
+
+			again, err := Source("test.proto", output)
+			if err != nil {
+				t.Fatalf("Failed to format the output: %v", err)
+			}
+			if !bytes.Equal(again, output) {
+				t.Errorf("Formatting is not idempotent:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestSource_SyntaxError(t *testing.T) {
+	_, err := Source("broken.proto", []byte("syntax = \"proto3\";\nmessage Book {\n"))
+	if err == nil || !strings.Contains(err.Error(), "broken.proto:") {
+		t.Errorf("Expected a positioned syntax error, got %v", err)
+	}
+}
+
+// TestSource_PreservesFiles formats the proto files of the repository and
+// checks that the declarations and comments are unchanged
+func TestSource_PreservesFiles(t *testing.T) {
+	var files []string
+	for _, dir := range []string{"../../test-project", "../tools/testdata"} {
+		err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() && strings.HasSuffix(path, ".proto") {
+				files = append(files, path)
+			}
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Failed to list %s: %v", dir, err)
+		}
+	}
+	if len(files) == 0 {
+		t.Fatal("Expected proto files to format")
+	}
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("Failed to read: %v", err)
+			}
+			output, err := Source(file, content)
+			if err != nil {
+				t.Fatalf("Source failed: %v", err)
+			}
+
+			before, beforeComments := parse(t, content)
+			after, afterComments := parse(t, output)
+			if !proto.Equal(before, after) {
+				t.Errorf("Formatting changed the declarations:\n%s", output)
+			}
+			if strings.Join(beforeComments, "\n") != strings.Join(afterComments, "\n") {
+				t.Errorf("Formatting changed the comments:\n%s", output)
+			}
+		})
+	}
+}
+
+// parse returns the descriptor of a file, without the source info and the
+// imports that formatting changes, and the text of its comments
+func parse(t *testing.T, content []byte) (*descriptorpb.FileDescriptorProto, []string) {
+	t.Helper()
+
+	handler := reporter.NewHandler(nil)
+	file, err := parser.Parse("test.proto", bytes.NewReader(content), handler)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	result, err := parser.ResultFromAST(file, false, handler)
+	if err != nil {
+		t.Fatalf("Failed to build descriptor: %v", err)
+	}
+	fd := proto.Clone(result.FileDescriptorProto()).(*descriptorpb.FileDescriptorProto)
+	fd.SourceCodeInfo = nil
+	fd.Dependency, fd.PublicDependency, fd.WeakDependency = nil, nil, nil
+
+	var comments []string
+	for item, ok := file.Items().First(); ok; item, ok = file.Items().Next(item) {
+		if _, comment := file.GetItem(item); comment.IsValid() {
+			comments = append(comments, strings.TrimSpace(comment.RawText()))
+		}
+	}
+	return fd, comments
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.proto")
+	if err := os.WriteFile(path, []byte("syntax=\"proto3\";\nmessage Book{}\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+
+	changed, err := File(path, false)
+	if err != nil || !changed {
+		t.Fatalf("Expected the file to need formatting, got %v, %v", changed, err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "syntax=\"proto3\";\nmessage Book{}\n" {
+		t.Fatalf("Expected the file to be unchanged without write, got:\n%s", content)
+	}
+
+	if changed, err := File(path, true); err != nil || !changed {
+		t.Fatalf("Expected the file to be formatted, got %v, %v", changed, err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "syntax = \"proto3\";\n\nmessage Book {}\n" {
+		t.Errorf("Unexpected formatted file:\n%s", content)
+	}
+
+	if changed, err := File(path, false); err != nil || changed {
+		t.Errorf("Expected the formatted file to be unchanged, got %v, %v", changed, err)
+	}
+}
@@ -0,0 +1,174 @@
+package gitrepo
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/bufbuild/protocompile/linker"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/compiler"
+	"github.com/yuemori/protobuf-mcp-server/internal/config"
+)
+
+// ExportProtos writes the proto files of the repository containing dir, as
+// they were at the given ref, into a new temporary directory. Only objects in
+// the local repository are read. It returns the directory corresponding to
+// dir inside the export and a cleanup function that removes the export.
+func ExportProtos(ctx context.Context, dir, ref string) (string, func(), error) {
+	// Refuse refs that git would parse as options
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return "", nil, fmt.Errorf("invalid git revision: %q", ref)
+	}
+
+	toplevel, err := runGit(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, fmt.Errorf("%s is not inside a git repository: %w", dir, err)
+	}
+	toplevel = strings.TrimSpace(toplevel)
+
+	if _, err := runGit(ctx, toplevel, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return "", nil, fmt.Errorf("unknown git revision: %s", ref)
+	}
+
+	prefix, err := relativeToToplevel(toplevel, dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	exportDir, err := os.MkdirTemp("", "protobuf-mcp-git-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(exportDir) }
+
+	if err := extractProtos(ctx, toplevel, ref, exportDir); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return filepath.Join(exportDir, prefix), cleanup, nil
+}
+
+// CompileProtos compiles a project as it was at the given git ref, using the
+// given configuration. The working directory is restored afterwards.
+func CompileProtos(ctx context.Context, projectRoot string, cfg *config.ProjectConfig, ref string) (linker.Files, error) {
+	root, cleanup, err := ExportProtos(ctx, projectRoot, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	// Compilation changes the working directory to the exported tree, which
+	// is removed afterwards
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	defer os.Chdir(cwd)
+
+	files, err := compiler.CompileProtos(ctx, root, cfg.ProtoFiles, cfg.ImportPaths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile proto files at %s: %w", ref, err)
+	}
+	return files, nil
+}
+
+// relativeToToplevel returns the path of dir relative to the repository's top
+// level directory, resolving symlinks on both sides
+func relativeToToplevel(toplevel, dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(toplevel); err == nil {
+		toplevel = resolved
+	}
+
+	prefix, err := filepath.Rel(toplevel, absDir)
+	if err != nil || !filepath.IsLocal(prefix) {
+		return "", fmt.Errorf("%s is outside of the repository at %s", dir, toplevel)
+	}
+	return prefix, nil
+}
+
+// extractProtos streams the tree at ref out of the repository as a tar archive
+// and writes every .proto file in it below dest
+func extractProtos(ctx context.Context, toplevel, ref, dest string) error {
+	cmd := exec.CommandContext(ctx, "git", "-C", toplevel, "archive", "--format=tar", ref)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to run git archive: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run git archive: %w", err)
+	}
+
+	extractErr := extractTar(stdout, dest)
+	// Drain the rest of the archive so that git can exit
+	io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("git archive %s failed: %w: %s", ref, err, strings.TrimSpace(stderr.String()))
+	}
+	return extractErr
+}
+
+// extractTar writes the regular .proto files of a tar archive below dest
+func extractTar(r io.Reader, dest string) error {
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read git archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || !strings.HasSuffix(header.Name, ".proto") || !filepath.IsLocal(header.Name) {
+			continue
+		}
+
+		path := filepath.Join(dest, filepath.FromSlash(header.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", header.Name, err)
+		}
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", header.Name, err)
+		}
+		_, err = io.Copy(file, reader)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", header.Name, err)
+		}
+	}
+}
+
+// runGit runs a git command in dir and returns its standard output
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%w: %s", err, message)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
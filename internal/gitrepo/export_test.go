@@ -0,0 +1,96 @@
+package gitrepo
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// initRepository creates a git repository with the given files committed,
+// skipping the test when git is not available
+func initRepository(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	for path, content := range files {
+		fullPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+	return dir
+}
+
+func TestExportProtos(t *testing.T) {
+	repo := initRepository(t, map[string]string{
+		"README.md":              "readme",
+		"project/api.proto":      `syntax = "proto3";`,
+		"project/sub/more.proto": `syntax = "proto3";`,
+	})
+
+	// Changes in the working tree are not part of the export
+	if err := os.WriteFile(filepath.Join(repo, "project", "api.proto"), []byte("changed"), 0o644); err != nil {
+		t.Fatalf("Failed to modify api.proto: %v", err)
+	}
+
+	root, cleanup, err := ExportProtos(context.Background(), filepath.Join(repo, "project"), "HEAD")
+	if err != nil {
+		t.Fatalf("ExportProtos failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "api.proto"))
+	if err != nil {
+		t.Fatalf("Failed to read exported api.proto: %v", err)
+	}
+	if string(data) != `syntax = "proto3";` {
+		t.Errorf("Expected committed content, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(root, "sub", "more.proto")); err != nil {
+		t.Errorf("Expected nested proto file to be exported: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "..", "README.md")); !os.IsNotExist(err) {
+		t.Errorf("Expected non-proto files to be skipped, got %v", err)
+	}
+
+	cleanup()
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Errorf("Expected cleanup to remove the export, got %v", err)
+	}
+}
+
+func TestExportProtos_InvalidRef(t *testing.T) {
+	repo := initRepository(t, map[string]string{"api.proto": `syntax = "proto3";`})
+
+	for _, ref := range []string{"", "--output=x", "no-such-branch"} {
+		if _, _, err := ExportProtos(context.Background(), repo, ref); err == nil {
+			t.Errorf("Expected an error for ref %q", ref)
+		}
+	}
+}
+
+func TestExportProtos_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	if _, _, err := ExportProtos(context.Background(), t.TempDir(), "HEAD"); err == nil {
+		t.Errorf("Expected an error outside of a git repository")
+	}
+}
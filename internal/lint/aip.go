@@ -0,0 +1,369 @@
+package lint
+
+import (
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+)
+
+// Full names of the types and options the AIP rules refer to
+const (
+	resourceOptionName = "google.api.resource"
+	emptyType          = "google.protobuf.Empty"
+	fieldMaskType      = "google.protobuf.FieldMask"
+	operationType      = "google.longrunning.Operation"
+)
+
+// Standard methods, see https://google.aip.dev/130
+const (
+	methodGet    = "Get"
+	methodList   = "List"
+	methodCreate = "Create"
+	methodUpdate = "Update"
+	methodDelete = "Delete"
+)
+
+// standardVerbs are the HTTP verbs of the standard methods
+var standardVerbs = map[string]string{
+	methodGet:    "GET",
+	methodList:   "GET",
+	methodCreate: "POST",
+	methodUpdate: "PATCH",
+	methodDelete: "DELETE",
+}
+
+// aipRules check resource-oriented design as described by the Google API
+// Improvement Proposals
+var aipRules = []Rule{
+	{
+		ID:          "AIP_STANDARD_METHOD_REQUEST",
+		Group:       GroupAIP,
+		Description: "Get and Delete requests have a string name field; Create and Update requests have a field holding the resource (AIP-131 to 135)",
+		check:       checkStandardMethodRequest,
+	},
+	{
+		ID:          "AIP_STANDARD_METHOD_RESPONSE",
+		Group:       GroupAIP,
+		Description: "Get, Create and Update return the resource, List returns List<Resources>Response with a repeated field of resources, Delete returns google.protobuf.Empty or the resource (AIP-131 to 135)",
+		check:       checkStandardMethodResponse,
+	},
+	{
+		ID:          "AIP_PAGINATION",
+		Group:       GroupAIP,
+		Description: "List requests have int32 page_size and string page_token, responses have string next_page_token (AIP-158)",
+		check:       checkPagination,
+	},
+	{
+		ID:          "AIP_UPDATE_FIELD_MASK",
+		Group:       GroupAIP,
+		Description: "Update requests have a google.protobuf.FieldMask update_mask field (AIP-134)",
+		check:       checkUpdateFieldMask,
+	},
+	{
+		ID:          "AIP_HTTP_BINDING",
+		Group:       GroupAIP,
+		Description: "RPCs declare a google.api.http binding, except client streaming ones (AIP-127)",
+		check:       checkHTTPBinding,
+	},
+	{
+		ID:          "AIP_HTTP_VERB",
+		Group:       GroupAIP,
+		Description: "Standard methods use GET, GET, POST, PATCH and DELETE; custom methods use POST or GET (AIP-127, AIP-136)",
+		check:       checkHTTPVerb,
+	},
+	{
+		ID:          "AIP_HTTP_PATH",
+		Group:       GroupAIP,
+		Description: "HTTP paths bind {name} for Get and Delete, {<resource>.name} for Update, end with the collection for List and Create, and with a :verb for custom methods (AIP-127, AIP-136)",
+		check:       checkHTTPPath,
+	},
+	{
+		ID:          "AIP_HTTP_BODY",
+		Group:       GroupAIP,
+		Description: "Get, List and Delete have no HTTP body; Create and Update use the resource field as body (AIP-131 to 135)",
+		check:       checkHTTPBody,
+	},
+	{
+		ID:          "AIP_RESOURCE_NAME",
+		Group:       GroupAIP,
+		Description: "Resources, i.e. messages with google.api.resource or returned by Get, have a string name field (AIP-122)",
+		check:       checkResourceName,
+	},
+}
+
+// standardMethod recognizes a standard method by its name, returning the kind
+// of method and the resource, which is plural for List. Streaming methods
+// are never standard methods.
+func standardMethod(method protoreflect.MethodDescriptor) (kind, resource string) {
+	if method.IsStreamingClient() || method.IsStreamingServer() {
+		return "", ""
+	}
+	name := string(method.Name())
+	for _, kind := range []string{methodGet, methodList, methodCreate, methodUpdate, methodDelete} {
+		if resource := strings.TrimPrefix(name, kind); resource != name && pascalCase.MatchString(resource) {
+			return kind, resource
+		}
+	}
+	return "", ""
+}
+
+// field returns the field of a message with the given name, or nil
+func field(message protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	return message.Fields().ByName(protoreflect.Name(name))
+}
+
+// isScalar reports whether a field is a singular field of the given kind
+func isScalar(field protoreflect.FieldDescriptor, kind protoreflect.Kind) bool {
+	return field != nil && !field.IsList() && !field.IsMap() && field.Kind() == kind
+}
+
+// isMessage reports whether a field is a singular field of the given message
+func isMessage(field protoreflect.FieldDescriptor, name protoreflect.FullName) bool {
+	return field != nil && !field.IsList() && field.Message() != nil && field.Message().FullName() == name
+}
+
+// resourceField returns the name of the field holding the resource in
+// Create and Update requests, e.g. "user_profile" for UserProfile
+func resourceField(resource string) string {
+	return toLowerSnakeCase(resource)
+}
+
+// hasOption reports whether a descriptor sets the option with the given full
+// name
+func hasOption(desc protoreflect.Descriptor, name protoreflect.FullName) bool {
+	options := desc.Options()
+	if options == nil {
+		return false
+	}
+	found := false
+	options.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		found = field.IsExtension() && field.FullName() == name
+		return !found
+	})
+	return found
+}
+
+// checkStandardMethodRequest reports standard method requests missing the
+// resource name or the resource
+func checkStandardMethodRequest(r *reporter, desc protoreflect.Descriptor) {
+	method, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return
+	}
+	kind, resource := standardMethod(method)
+	request := method.Input()
+	switch kind {
+	case methodGet, methodDelete:
+		if !isScalar(field(request, "name"), protoreflect.StringKind) {
+			r.report(method, "request %s of %s should have a string name field identifying the %s", request.Name(), method.Name(), resource)
+		}
+	case methodCreate, methodUpdate:
+		if f := field(request, resourceField(resource)); f == nil || f.IsList() || f.Message() == nil {
+			r.report(method, "request %s of %s should have a field %s holding the %s", request.Name(), method.Name(), resourceField(resource), resource)
+		}
+	}
+}
+
+// checkStandardMethodResponse reports standard methods returning something
+// else than the resource or the standard List response
+func checkStandardMethodResponse(r *reporter, desc protoreflect.Descriptor) {
+	method, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return
+	}
+	kind, resource := standardMethod(method)
+	response := method.Output()
+	switch kind {
+	case methodGet, methodCreate, methodUpdate, methodDelete:
+		if string(response.Name()) == resource || (kind != methodGet && response.FullName() == operationType) {
+			return
+		}
+		if kind == methodDelete {
+			if response.FullName() != emptyType {
+				r.report(method, "%s should return google.protobuf.Empty or the %s, not %s", method.Name(), resource, response.FullName())
+			}
+			return
+		}
+		r.report(method, "%s should return the %s resource, not %s", method.Name(), resource, response.FullName())
+	case methodList:
+		expected := "List" + resource + "Response"
+		if string(response.Name()) != expected {
+			r.report(method, "%s should return %s, not %s", method.Name(), expected, response.FullName())
+			return
+		}
+		if f := field(response, toLowerSnakeCase(resource)); f == nil || !f.IsList() || f.Message() == nil {
+			r.report(method, "response %s of %s should have a repeated field %s holding the resources", response.Name(), method.Name(), toLowerSnakeCase(resource))
+		}
+	}
+}
+
+// checkPagination reports List methods without the standard pagination
+// fields
+func checkPagination(r *reporter, desc protoreflect.Descriptor) {
+	method, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return
+	}
+	if kind, _ := standardMethod(method); kind != methodList {
+		return
+	}
+
+	var missing []string
+	if !isScalar(field(method.Input(), "page_size"), protoreflect.Int32Kind) {
+		missing = append(missing, "int32 page_size in "+string(method.Input().Name()))
+	}
+	if !isScalar(field(method.Input(), "page_token"), protoreflect.StringKind) {
+		missing = append(missing, "string page_token in "+string(method.Input().Name()))
+	}
+	if !isScalar(field(method.Output(), "next_page_token"), protoreflect.StringKind) {
+		missing = append(missing, "string next_page_token in "+string(method.Output().Name()))
+	}
+	if len(missing) > 0 {
+		r.report(method, "%s should be paginated: missing %s", method.Name(), strings.Join(missing, ", "))
+	}
+}
+
+// checkUpdateFieldMask reports Update requests without an update mask
+func checkUpdateFieldMask(r *reporter, desc protoreflect.Descriptor) {
+	method, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return
+	}
+	if kind, _ := standardMethod(method); kind != methodUpdate {
+		return
+	}
+	if !isMessage(field(method.Input(), "update_mask"), fieldMaskType) {
+		r.report(method, "request %s of %s should have a google.protobuf.FieldMask update_mask field", method.Input().Name(), method.Name())
+	}
+}
+
+// checkHTTPBinding reports RPCs that cannot be called over HTTP
+func checkHTTPBinding(r *reporter, desc protoreflect.Descriptor) {
+	method, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok || method.IsStreamingClient() {
+		return
+	}
+	if len(protoutil.HTTPBindings(method)) == 0 {
+		r.report(method, "rpc %s should declare an HTTP binding with option (google.api.http)", method.Name())
+	}
+}
+
+// checkHTTPVerb reports HTTP bindings whose verb does not match the method
+func checkHTTPVerb(r *reporter, desc protoreflect.Descriptor) {
+	method, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return
+	}
+	kind, _ := standardMethod(method)
+	for _, binding := range protoutil.HTTPBindings(method) {
+		if expected, ok := standardVerbs[kind]; ok {
+			if binding.Method != expected {
+				r.report(method, "%s is a standard %s method and should use HTTP %s, not %s", method.Name(), kind, expected, binding.Method)
+			}
+		} else if binding.Method != "POST" && binding.Method != "GET" {
+			r.report(method, "custom method %s should use HTTP POST, or GET if it only reads data, not %s", method.Name(), binding.Method)
+		}
+	}
+}
+
+// checkHTTPPath reports HTTP path templates that do not match the method
+func checkHTTPPath(r *reporter, desc protoreflect.Descriptor) {
+	method, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return
+	}
+	kind, resource := standardMethod(method)
+	for _, binding := range protoutil.HTTPBindings(method) {
+		variables := protoutil.PathVariables(binding.Path)
+		last := binding.Path[strings.LastIndex(binding.Path, "/")+1:]
+
+		switch kind {
+		case methodGet, methodDelete:
+			if !slices.Contains(variables, "name") {
+				r.report(method, "HTTP path %s of %s should bind the resource name with a {name=...} variable", binding.Path, method.Name())
+			}
+		case methodUpdate:
+			if expected := resourceField(resource) + ".name"; !slices.Contains(variables, expected) {
+				r.report(method, "HTTP path %s of %s should bind {%s}", binding.Path, method.Name(), expected)
+			}
+		case methodList:
+			if expected := collectionID(resource); last != expected {
+				r.report(method, "HTTP path %s of %s should end with the collection %s", binding.Path, method.Name(), expected)
+			}
+		case methodCreate:
+			if last == "" || strings.ContainsAny(last, "{}:") {
+				r.report(method, "HTTP path %s of %s should end with the collection of %s resources", binding.Path, method.Name(), resource)
+			}
+		default:
+			verbs := customVerbs(string(method.Name()))
+			if !slices.ContainsFunc(verbs, func(verb string) bool { return strings.HasSuffix(binding.Path, ":"+verb) }) {
+				r.report(method, "HTTP path %s of custom method %s should end with a custom verb such as :%s", binding.Path, method.Name(), verbs[0])
+			}
+		}
+	}
+}
+
+// checkHTTPBody reports HTTP bodies that do not match the method
+func checkHTTPBody(r *reporter, desc protoreflect.Descriptor) {
+	method, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return
+	}
+	kind, resource := standardMethod(method)
+	for _, binding := range protoutil.HTTPBindings(method) {
+		switch kind {
+		case methodGet, methodList, methodDelete:
+			if binding.Body != "" {
+				r.report(method, "%s should not have an HTTP body, found body: %q", method.Name(), binding.Body)
+			}
+		case methodCreate, methodUpdate:
+			if expected := resourceField(resource); binding.Body != expected {
+				r.report(method, "HTTP body of %s should be the resource field %q, not %q", method.Name(), expected, binding.Body)
+			}
+		}
+	}
+}
+
+// checkResourceName reports resources without a name field. Messages with
+// the google.api.resource option are checked where they are declared; other
+// messages returned by Get methods are checked at the method.
+func checkResourceName(r *reporter, desc protoreflect.Descriptor) {
+	switch d := desc.(type) {
+	case protoreflect.MessageDescriptor:
+		if hasOption(d, resourceOptionName) && !isScalar(field(d, "name"), protoreflect.StringKind) {
+			r.report(d, "resource %s should have a string name field", d.Name())
+		}
+	case protoreflect.MethodDescriptor:
+		kind, resource := standardMethod(d)
+		resourceMessage := d.Output()
+		if kind != methodGet || string(resourceMessage.Name()) != resource || hasOption(resourceMessage, resourceOptionName) {
+			return
+		}
+		if !isScalar(field(resourceMessage, "name"), protoreflect.StringKind) {
+			r.report(d, "resource %s returned by %s should have a string name field", resourceMessage.FullName(), d.Name())
+		}
+	}
+}
+
+// customVerbs returns the custom verbs a method may use in its HTTP path:
+// the leading words of its name, e.g. "batch" and "batchGet" for BatchGetBooks
+func customVerbs(name string) []string {
+	parts := words(name)
+	verbs := make([]string, 0, len(parts))
+	for i := range parts {
+		verbs = append(verbs, collectionID(strings.Join(parts[:i+1], "")))
+	}
+	return verbs
+}
+
+// collectionID returns the collection identifier of a plural resource name
+// as used in paths, e.g. "userEvents" for UserEvents
+func collectionID(plural string) string {
+	if plural == "" {
+		return ""
+	}
+	return strings.ToLower(plural[:1]) + plural[1:]
+}
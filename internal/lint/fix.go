@@ -0,0 +1,202 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/textedit"
+)
+
+// Field numbers of descriptor protos, used as source paths. Every kind of
+// declaration has its name as field 1.
+const (
+	nameTag       = 1
+	extendeeTag   = 2
+	typeNameTag   = 6
+	inputTypeTag  = 2
+	outputTypeTag = 3
+)
+
+// reference is a type name written in source, e.g. the type of a field
+type reference struct {
+	file   protoreflect.FileDescriptor
+	loc    protoreflect.SourceLocation
+	target protoreflect.FullName
+}
+
+// symbolIndex holds the declarations and type references of the linted
+// files, so that renames can be fixed everywhere or not at all
+type symbolIndex struct {
+	names      map[protoreflect.FullName]bool
+	references []reference
+	// unlocated holds the elements referenced where no edit can be made,
+	// such as map value types and enum default values
+	unlocated map[protoreflect.FullName]bool
+}
+
+// newSymbolIndex indexes the declarations and references of files
+func newSymbolIndex(files []protoreflect.FileDescriptor) *symbolIndex {
+	index := &symbolIndex{
+		names:     make(map[protoreflect.FullName]bool),
+		unlocated: make(map[protoreflect.FullName]bool),
+	}
+	for _, file := range files {
+		forEachDescriptor(file, func(desc protoreflect.Descriptor) {
+			index.names[desc.FullName()] = true
+			switch d := desc.(type) {
+			case protoreflect.FieldDescriptor:
+				index.addField(d)
+			case protoreflect.MethodDescriptor:
+				index.add(d, inputTypeTag, d.Input().FullName())
+				index.add(d, outputTypeTag, d.Output().FullName())
+			}
+		})
+	}
+	return index
+}
+
+// addField indexes the types referenced by a field
+func (x *symbolIndex) addField(field protoreflect.FieldDescriptor) {
+	if field.IsMap() {
+		if value := field.MapValue(); value.Message() != nil {
+			x.unlocated[value.Message().FullName()] = true
+		} else if value.Enum() != nil {
+			x.unlocated[value.Enum().FullName()] = true
+		}
+		return
+	}
+	if field.Message() != nil {
+		x.add(field, typeNameTag, field.Message().FullName())
+	} else if field.Enum() != nil {
+		x.add(field, typeNameTag, field.Enum().FullName())
+	}
+	if enumValue := field.DefaultEnumValue(); field.HasDefault() && enumValue != nil {
+		x.unlocated[enumValue.FullName()] = true
+	}
+	if field.IsExtension() {
+		x.add(field, extendeeTag, field.ContainingMessage().FullName())
+	}
+}
+
+// add indexes a reference written at a part of a declaration
+func (x *symbolIndex) add(desc protoreflect.Descriptor, tag int32, target protoreflect.FullName) {
+	loc, ok := partLocation(desc, tag)
+	if !ok || loc.StartLine != loc.EndLine {
+		x.unlocated[target] = true
+		return
+	}
+	x.references = append(x.references, reference{file: desc.ParentFile(), loc: loc, target: target})
+}
+
+// partLocation returns the source location of a part of a declaration, such
+// as its name, identified by its field number in the descriptor proto
+func partLocation(desc protoreflect.Descriptor, tag int32) (protoreflect.SourceLocation, bool) {
+	locations := desc.ParentFile().SourceLocations()
+	loc := locations.ByDescriptor(desc)
+	if loc.Path == nil {
+		return loc, false
+	}
+	path := append(append(protoreflect.SourcePath{}, loc.Path...), tag)
+	part := locations.ByPath(path)
+	return part, part.Path != nil
+}
+
+// within reports whether name is the renamed element or nested in it
+func within(name, renamed protoreflect.FullName) bool {
+	return name == renamed || strings.HasPrefix(string(name), string(renamed)+".")
+}
+
+// Rename returns the edits renaming a declaration of files and every
+// reference to it, or to the types nested in it, from files. Fails if the
+// new name is taken or some reference cannot be edited, such as a map value
+// type or an enum default value.
+func Rename(files []protoreflect.FileDescriptor, desc protoreflect.Descriptor, newName string) ([]textedit.Edit, error) {
+	return newSymbolIndex(files).rename(desc, newName)
+}
+
+// renameEdits returns the edits of a fix renaming a declaration, or nil if
+// the rename cannot be made safely
+func (r *reporter) renameEdits(desc protoreflect.Descriptor, newName string) []textedit.Edit {
+	edits, err := r.index.rename(desc, newName)
+	if err != nil {
+		return nil
+	}
+	return edits
+}
+
+// rename returns the edits renaming a declaration and the references to it
+// or to the types nested in it
+func (x *symbolIndex) rename(desc protoreflect.Descriptor, newName string) ([]textedit.Edit, error) {
+	oldName := string(desc.Name())
+	switch renamed := desc.FullName().Parent().Append(protoreflect.Name(newName)); {
+	case newName == "" || newName == oldName:
+		return nil, fmt.Errorf("%s is already named %q", desc.FullName(), oldName)
+	case x.names[renamed]:
+		return nil, fmt.Errorf("%s is already declared", renamed)
+	}
+	for target := range x.unlocated {
+		if within(target, desc.FullName()) {
+			return nil, fmt.Errorf("%s is referenced where no edit can be made, such as a map value type or an enum default value", target)
+		}
+	}
+
+	loc, ok := partLocation(desc, nameTag)
+	if !ok || loc.StartLine != loc.EndLine {
+		return nil, fmt.Errorf("no source location for the name of %s", desc.FullName())
+	}
+	edits := []textedit.Edit{spanEdit(desc.ParentFile(), loc.StartLine, loc.StartColumn, loc.EndColumn, oldName, newName)}
+
+	for _, ref := range x.references {
+		if !within(ref.target, desc.FullName()) {
+			continue
+		}
+		// The reference ends with the renamed name followed by the names
+		// of the nested types, unless it is written relative to a scope
+		// inside the renamed type, in which case it needs no edit
+		suffix := len(ref.target) - len(desc.FullName())
+		if ref.loc.EndColumn-ref.loc.StartColumn < len(oldName)+suffix {
+			continue
+		}
+		end := ref.loc.EndColumn - suffix
+		edits = append(edits, spanEdit(ref.file, ref.loc.StartLine, end-len(oldName), end, oldName, newName))
+	}
+	return edits, nil
+}
+
+// insertBeforeEdits returns the edit inserting a line before a declaration
+// and its leading comments, with the same indentation. Only line comments can
+// be located, so there is no edit for a declaration with a leading block
+// comment.
+func insertBeforeEdits(desc protoreflect.Descriptor, line string) []textedit.Edit {
+	loc := desc.ParentFile().SourceLocations().ByDescriptor(desc)
+	if loc.Path == nil {
+		return nil
+	}
+	comments := loc.LeadingComments
+	if comments == "" {
+		text := line + "\n" + strings.Repeat(" ", loc.StartColumn)
+		return []textedit.Edit{spanEdit(desc.ParentFile(), loc.StartLine, loc.StartColumn, loc.StartColumn, "", text)}
+	}
+	if !strings.HasSuffix(comments, "\n") {
+		return nil
+	}
+	// Each line comment is one line of the leading comments, ending with a
+	// newline, and they end on the line before the declaration
+	text := strings.Repeat(" ", loc.StartColumn) + line + "\n"
+	return []textedit.Edit{spanEdit(desc.ParentFile(), loc.StartLine-strings.Count(comments, "\n"), 0, 0, "", text)}
+}
+
+// spanEdit builds an edit of a 0-based range within one line
+func spanEdit(file protoreflect.FileDescriptor, line, startColumn, endColumn int, oldText, newText string) textedit.Edit {
+	return textedit.Edit{
+		File:        file.Path(),
+		StartLine:   line + 1,
+		StartColumn: startColumn + 1,
+		EndLine:     line + 1,
+		EndColumn:   endColumn + 1,
+		OldText:     oldText,
+		NewText:     newText,
+	}
+}
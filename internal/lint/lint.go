@@ -0,0 +1,342 @@
+// Package lint checks proto files against naming, documentation and layout
+// conventions.
+package lint
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/config"
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+	"github.com/yuemori/protobuf-mcp-server/internal/textedit"
+)
+
+// Rule groups. Every rule belongs to one group; DEFAULT selects the groups
+// that run when no rules are configured and ALL selects every rule. The AIP
+// group checks resource-oriented design and is opt-in.
+const (
+	GroupStyle    = "STYLE"
+	GroupComments = "COMMENTS"
+	GroupAIP      = "AIP"
+	GroupDefault  = "DEFAULT"
+	GroupAll      = "ALL"
+)
+
+// Field numbers of FileDescriptorProto, used as source paths
+const (
+	packageTag    = 2
+	dependencyTag = 3
+	syntaxTag     = 12
+)
+
+// defaultGroups are the groups selected by DEFAULT
+var defaultGroups = []string{GroupStyle, GroupComments}
+
+// allRules lists every rule
+var allRules = slices.Concat(baseRules, aipRules)
+
+// Rule is a lint rule. Its check is called for every file and every
+// declaration in it, and reports findings through the reporter.
+type Rule struct {
+	ID          string
+	Group       string
+	Description string
+	check       func(r *reporter, desc protoreflect.Descriptor)
+}
+
+// Finding describes one violation of a lint rule. Edits, when present, fix
+// the violation; renames include the references in every linted file.
+type Finding struct {
+	Rule     string          `json:"rule"`
+	Message  string          `json:"message"`
+	File     string          `json:"file"`
+	Element  string          `json:"element,omitempty"`
+	Location *protoutil.Span `json:"location,omitempty"`
+	Edits    []textedit.Edit `json:"edits,omitempty"`
+}
+
+// Edits returns the edits of all findings
+func Edits(findings []Finding) []textedit.Edit {
+	var edits []textedit.Edit
+	for _, finding := range findings {
+		edits = append(edits, finding.Edits...)
+	}
+	return edits
+}
+
+// Rules returns every lint rule, ordered by group and ID
+func Rules() []Rule {
+	rules := append([]Rule(nil), allRules...)
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Group != rules[j].Group {
+			return rules[i].Group < rules[j].Group
+		}
+		return rules[i].ID < rules[j].ID
+	})
+	return rules
+}
+
+// Linter runs a selection of rules
+type Linter struct {
+	rules      []Rule
+	ignore     []string
+	ignoreOnly map[string][]string
+}
+
+// New creates a linter from the lint section of the project configuration.
+// Rule and group names are case-insensitive; unknown names are an error.
+func New(cfg config.LintConfig) (*Linter, error) {
+	names := cfg.Rules
+	if len(names) == 0 {
+		names = []string{GroupDefault}
+	}
+	selected, err := selectRules(names)
+	if err != nil {
+		return nil, err
+	}
+	excluded, err := selectRules(cfg.Except)
+	if err != nil {
+		return nil, err
+	}
+
+	linter := &Linter{
+		ignore:     cfg.Ignore,
+		ignoreOnly: make(map[string][]string),
+	}
+	for _, rule := range allRules {
+		if selected[rule.ID] && !excluded[rule.ID] {
+			linter.rules = append(linter.rules, rule)
+		}
+	}
+	for name, files := range cfg.IgnoreOnly {
+		ids, err := selectRules([]string{name})
+		if err != nil {
+			return nil, err
+		}
+		for id := range ids {
+			linter.ignoreOnly[id] = append(linter.ignoreOnly[id], files...)
+		}
+	}
+	return linter, nil
+}
+
+// selectRules resolves rule IDs and group names to a set of rule IDs
+func selectRules(names []string) (map[string]bool, error) {
+	selected := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, rule := range allRules {
+			if name == rule.ID || name == rule.Group || name == GroupAll ||
+				(name == GroupDefault && slices.Contains(defaultGroups, rule.Group)) {
+				selected[rule.ID] = true
+				found = true
+			}
+		}
+		if !found && name != GroupAll && name != GroupDefault {
+			return nil, fmt.Errorf("unknown lint rule or group %q", name)
+		}
+	}
+	return selected, nil
+}
+
+// Run lints the given files and returns the findings ordered by file and
+// position. Only files for which include returns true are linted; a nil
+// include lints every file. Fixes that rename a declaration also edit its
+// references in all the given files, excluded and ignored ones included.
+// When fixes overlap, only the first is kept; the other findings are fixed by
+// running again.
+func (l *Linter) Run(files []protoreflect.FileDescriptor, include func(path string) bool) []Finding {
+	index := newSymbolIndex(files)
+	var findings []Finding
+	for _, file := range files {
+		if include != nil && !include(file.Path()) {
+			continue
+		}
+		if MatchesAny(l.ignore, file.Path()) {
+			continue
+		}
+		for _, rule := range l.rules {
+			if MatchesAny(l.ignoreOnly[rule.ID], file.Path()) {
+				continue
+			}
+			r := &reporter{rule: rule.ID, index: index}
+			forEachDescriptor(file, func(desc protoreflect.Descriptor) {
+				rule.check(r, desc)
+			})
+			findings = append(findings, r.findings...)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Location != nil && b.Location != nil {
+			if a.Location.StartLine != b.Location.StartLine {
+				return a.Location.StartLine < b.Location.StartLine
+			}
+			if a.Location.StartColumn != b.Location.StartColumn {
+				return a.Location.StartColumn < b.Location.StartColumn
+			}
+		}
+		return a.Rule < b.Rule
+	})
+	dropConflictingEdits(findings)
+	return findings
+}
+
+// dropConflictingEdits removes the edits of findings whose edits overlap the
+// edits of an earlier finding, so that all remaining edits apply together
+func dropConflictingEdits(findings []Finding) {
+	var kept []textedit.Edit
+	for i := range findings {
+		conflict := false
+		for _, edit := range findings[i].Edits {
+			for _, other := range kept {
+				if overlaps(edit, other) {
+					conflict = true
+				}
+			}
+		}
+		if conflict {
+			findings[i].Edits = nil
+			continue
+		}
+		kept = append(kept, findings[i].Edits...)
+	}
+}
+
+// overlaps reports whether two edits touch the same text. Insertions at the
+// same position do not overlap.
+func overlaps(a, b textedit.Edit) bool {
+	before := func(line1, column1, line2, column2 int) bool {
+		return line1 < line2 || (line1 == line2 && column1 < column2)
+	}
+	return a.File == b.File &&
+		before(a.StartLine, a.StartColumn, b.EndLine, b.EndColumn) &&
+		before(b.StartLine, b.StartColumn, a.EndLine, a.EndColumn)
+}
+
+// reporter collects the findings of one rule
+type reporter struct {
+	rule     string
+	index    *symbolIndex
+	findings []Finding
+}
+
+// report records a finding located at a descriptor
+func (r *reporter) report(desc protoreflect.Descriptor, format string, args ...interface{}) {
+	r.fix(desc, nil, format, args...)
+}
+
+// fix records a finding located at a descriptor, with edits fixing it
+func (r *reporter) fix(desc protoreflect.Descriptor, edits []textedit.Edit, format string, args ...interface{}) {
+	finding := Finding{
+		Rule:     r.rule,
+		Message:  fmt.Sprintf(format, args...),
+		File:     desc.ParentFile().Path(),
+		Location: locationOf(desc),
+		Edits:    edits,
+	}
+	if _, ok := desc.(protoreflect.FileDescriptor); !ok {
+		finding.Element = string(desc.FullName())
+	}
+	r.findings = append(r.findings, finding)
+}
+
+// MatchesAny reports whether a file matches one of the patterns. A pattern
+// is a file path, a directory containing the file, or a glob pattern; a
+// trailing "/**" matches everything below a directory.
+func MatchesAny(patterns []string, file string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(strings.TrimSuffix(path.Clean(pattern), "/**"), "/")
+		if file == pattern || strings.HasPrefix(file, pattern+"/") {
+			return true
+		}
+		if matched, _ := path.Match(pattern, file); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// locationOf returns the source span of a descriptor, or nil if its file has
+// no source info for it. Files are located at their package statement, or
+// their syntax statement when they have no package.
+func locationOf(desc protoreflect.Descriptor) *protoutil.Span {
+	file := desc.ParentFile()
+	var loc protoreflect.SourceLocation
+	if _, ok := desc.(protoreflect.FileDescriptor); ok {
+		loc = file.SourceLocations().ByPath(protoreflect.SourcePath{packageTag})
+		if loc.Path == nil {
+			loc = file.SourceLocations().ByPath(protoreflect.SourcePath{syntaxTag})
+		}
+	} else {
+		loc = file.SourceLocations().ByDescriptor(desc)
+	}
+	return protoutil.SpanOfLocation(file, loc)
+}
+
+// forEachDescriptor calls fn for a file and every declaration in it:
+// messages, fields, oneofs, enums, enum values, services, methods and
+// extensions. Map entries are skipped, as their names are generated.
+func forEachDescriptor(file protoreflect.FileDescriptor, fn func(protoreflect.Descriptor)) {
+	fn(file)
+
+	visitEnums := func(enums protoreflect.EnumDescriptors) {
+		for i := 0; i < enums.Len(); i++ {
+			enum := enums.Get(i)
+			fn(enum)
+			for j := 0; j < enum.Values().Len(); j++ {
+				fn(enum.Values().Get(j))
+			}
+		}
+	}
+	visitExtensions := func(extensions protoreflect.ExtensionDescriptors) {
+		for i := 0; i < extensions.Len(); i++ {
+			fn(extensions.Get(i))
+		}
+	}
+	var visitMessages func(messages protoreflect.MessageDescriptors)
+	visitMessages = func(messages protoreflect.MessageDescriptors) {
+		for i := 0; i < messages.Len(); i++ {
+			message := messages.Get(i)
+			if message.IsMapEntry() {
+				continue
+			}
+			fn(message)
+			for j := 0; j < message.Fields().Len(); j++ {
+				fn(message.Fields().Get(j))
+			}
+			for j := 0; j < message.Oneofs().Len(); j++ {
+				if oneof := message.Oneofs().Get(j); !oneof.IsSynthetic() {
+					fn(oneof)
+				}
+			}
+			visitEnums(message.Enums())
+			visitExtensions(message.Extensions())
+			visitMessages(message.Messages())
+		}
+	}
+
+	visitMessages(file.Messages())
+	visitEnums(file.Enums())
+	visitExtensions(file.Extensions())
+	for i := 0; i < file.Services().Len(); i++ {
+		service := file.Services().Get(i)
+		fn(service)
+		for j := 0; j < service.Methods().Len(); j++ {
+			fn(service.Methods().Get(j))
+		}
+	}
+}
@@ -0,0 +1,696 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/protocompile/linker"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/config"
+	"github.com/yuemori/protobuf-mcp-server/internal/testutil"
+	"github.com/yuemori/protobuf-mcp-server/internal/textedit"
+)
+
+// rules returns "RULE element" for each finding
+func rules(findings []Finding) []string {
+	result := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		result = append(result, finding.Rule+" "+finding.Element)
+	}
+	return result
+}
+
+// runLinter lints sources with the given configuration
+func runLinter(t *testing.T, cfg config.LintConfig, sources map[string]string) []Finding {
+	t.Helper()
+
+	linter, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return linter.Run(testutil.CompileDescriptors(t, sources), nil)
+}
+
+const styleSource = `syntax = "proto3";
+package library;
+
+message book_info {
+  string Title = 1;
+  oneof Source {
+    string url = 2;
+  }
+}
+
+enum state {
+  Draft = 0;
+  STATE_PUBLISHED = 1;
+}
+
+service library_service {
+  rpc get_book(book_info) returns (book_info);
+  rpc ListBooks(ListBooksRequest) returns (library_serviceListBooksResponse);
+}
+
+message ListBooksRequest {}
+message library_serviceListBooksResponse {}
+`
+
+func TestRun_Style(t *testing.T) {
+	findings := runLinter(t, config.LintConfig{Rules: []string{"STYLE"}}, map[string]string{"api.proto": styleSource})
+
+	got := rules(findings)
+	// Findings are ordered by position
+	want := []string{
+		"FILE_GO_PACKAGE ",
+		"PACKAGE_VERSION_SUFFIX ",
+		"MESSAGE_PASCAL_CASE library.book_info",
+		"FIELD_LOWER_SNAKE_CASE library.book_info.Title",
+		"ONEOF_LOWER_SNAKE_CASE library.book_info.Source",
+		"ENUM_PASCAL_CASE library.state",
+		"ENUM_VALUE_UPPER_SNAKE_CASE library.Draft",
+		"ENUM_ZERO_VALUE_SUFFIX library.Draft",
+		"SERVICE_PASCAL_CASE library.library_service",
+		"RPC_PASCAL_CASE library.library_service.get_book",
+		"RPC_REQUEST_STANDARD_NAME library.library_service.get_book",
+		"RPC_RESPONSE_STANDARD_NAME library.library_service.get_book",
+		"MESSAGE_PASCAL_CASE library.library_serviceListBooksResponse",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Unexpected findings:\ngot  %q\nwant %q", got, want)
+	}
+
+	messages := map[string]string{}
+	for _, finding := range findings {
+		messages[finding.Rule+" "+finding.Element] = finding.Message
+	}
+	for key, message := range map[string]string{
+		"MESSAGE_PASCAL_CASE library.book_info":          "message name book_info should be PascalCase, e.g. BookInfo",
+		"FIELD_LOWER_SNAKE_CASE library.book_info.Title": "field name Title should be lower_snake_case, e.g. title",
+		"ENUM_ZERO_VALUE_SUFFIX library.Draft":           "zero value Draft of enum state should end with _UNSPECIFIED, e.g. STATE_UNSPECIFIED",
+		"PACKAGE_VERSION_SUFFIX ":                        "package library should end with a version such as library.v1",
+	} {
+		if messages[key] != message {
+			t.Errorf("Expected %s to report %q, got %q", key, message, messages[key])
+		}
+	}
+
+	if location := findings[2].Location; location == nil || location.StartLine != 4 || location.StartColumn != 1 {
+		t.Errorf("Expected the message finding at 4:1, got %+v", location)
+	}
+	if location := findings[0].Location; location == nil || location.StartLine != 2 {
+		t.Errorf("Expected file findings at the package statement, got %+v", location)
+	}
+}
+
+func TestRun_Comments(t *testing.T) {
+	findings := runLinter(t, config.LintConfig{Rules: []string{"comments"}}, map[string]string{
+		"api.proto": `syntax = "proto3";
+package library.v1;
+
+// A book
+message Book {
+  // The title
+  string title = 1;
+  string isbn = 2; // trailing comments do not count
+  map<string, string> labels = 3;
+}
+
+// Library management
+service Library {
+  rpc GetBook(Book) returns (Book);
+}
+`,
+	})
+
+	got := rules(findings)
+	want := []string{
+		"COMMENT_FIELD library.v1.Book.isbn",
+		"COMMENT_FIELD library.v1.Book.labels",
+		"COMMENT_RPC library.v1.Library.GetBook",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected findings:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestRun_Configuration(t *testing.T) {
+	sources := map[string]string{
+		"library/v1/api.proto": `syntax = "proto3";
+package library.v1;
+
+option go_package = "example.com/library/v1;libraryv1";
+
+message book {
+  string Title = 1;
+}
+`,
+		"legacy/old.proto": `syntax = "proto3";
+package legacy;
+
+option go_package = "example.com/legacy";
+
+message Old {
+  string Name = 1;
+}
+`,
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config.LintConfig
+		expected []string
+	}{
+		{
+			name: "except",
+			cfg:  config.LintConfig{Except: []string{"COMMENTS", "PACKAGE_VERSION_SUFFIX"}},
+			expected: []string{
+				"FIELD_LOWER_SNAKE_CASE legacy.Old.Name",
+				"MESSAGE_PASCAL_CASE library.v1.book",
+				"FIELD_LOWER_SNAKE_CASE library.v1.book.Title",
+			},
+		},
+		{
+			name: "ignore directory",
+			cfg:  config.LintConfig{Rules: []string{"STYLE"}, Ignore: []string{"legacy"}},
+			expected: []string{
+				"MESSAGE_PASCAL_CASE library.v1.book",
+				"FIELD_LOWER_SNAKE_CASE library.v1.book.Title",
+			},
+		},
+		{
+			name: "ignore only",
+			cfg: config.LintConfig{
+				Rules: []string{"STYLE"},
+				IgnoreOnly: map[string][]string{
+					"FIELD_LOWER_SNAKE_CASE": {"**/*.proto", "*/*.proto"},
+					"PACKAGE_VERSION_SUFFIX": {"legacy/**"},
+				},
+			},
+			expected: []string{
+				"MESSAGE_PASCAL_CASE library.v1.book",
+				"FIELD_LOWER_SNAKE_CASE library.v1.book.Title",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rules(runLinter(t, tt.cfg, sources))
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Unexpected findings:\ngot  %q\nwant %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNew_UnknownRule(t *testing.T) {
+	if _, err := New(config.LintConfig{Rules: []string{"NO_SUCH_RULE"}}); err == nil {
+		t.Errorf("Expected an error for an unknown rule")
+	}
+	if _, err := New(config.LintConfig{IgnoreOnly: map[string][]string{"NOPE": {"a.proto"}}}); err == nil {
+		t.Errorf("Expected an error for an unknown rule in ignore_only")
+	}
+}
+
+func TestCaseConversions(t *testing.T) {
+	tests := []struct {
+		name, pascal, lowerSnake, upperSnake string
+	}{
+		{"book_info", "BookInfo", "book_info", "BOOK_INFO"},
+		{"HTTPServer", "HttpServer", "http_server", "HTTP_SERVER"},
+		{"getBookV2", "GetBookV2", "get_book_v2", "GET_BOOK_V2"},
+		{"Draft", "Draft", "draft", "DRAFT"},
+	}
+	for _, tt := range tests {
+		if got := toPascalCase(tt.name); got != tt.pascal {
+			t.Errorf("toPascalCase(%q) = %q, want %q", tt.name, got, tt.pascal)
+		}
+		if got := toLowerSnakeCase(tt.name); got != tt.lowerSnake {
+			t.Errorf("toLowerSnakeCase(%q) = %q, want %q", tt.name, got, tt.lowerSnake)
+		}
+		if got := toUpperSnakeCase(tt.name); got != tt.upperSnake {
+			t.Errorf("toUpperSnakeCase(%q) = %q, want %q", tt.name, got, tt.upperSnake)
+		}
+	}
+}
+
+// withGoogleAPI adds the google.api annotations of the test project, and a
+// minimal google.api.resource option, to proto sources
+func withGoogleAPI(t *testing.T, sources map[string]string) map[string]string {
+	t.Helper()
+
+	for _, path := range []string{"google/api/annotations.proto", "google/api/http.proto"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "test-project", path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		sources[path] = string(data)
+	}
+	sources["google/api/resource.proto"] = `syntax = "proto3";
+package google.api;
+
+import "google/protobuf/descriptor.proto";
+
+message ResourceDescriptor {
+  string type = 1;
+  repeated string pattern = 2;
+}
+
+extend google.protobuf.MessageOptions {
+  ResourceDescriptor resource = 1053;
+}
+`
+	return sources
+}
+
+func TestRun_AIP(t *testing.T) {
+	sources := withGoogleAPI(t, map[string]string{
+		"library/v1/library.proto": `syntax = "proto3";
+package library.v1;
+
+import "google/api/annotations.proto";
+import "google/api/resource.proto";
+import "google/protobuf/empty.proto";
+import "google/protobuf/field_mask.proto";
+
+service Library {
+  rpc GetBook(GetBookRequest) returns (Book) {
+    option (google.api.http) = { get: "/v1/{name=shelves/*/books/*}" };
+  }
+  rpc ListBooks(ListBooksRequest) returns (ListBooksResponse) {
+    option (google.api.http) = { get: "/v1/{parent=shelves/*}/books" };
+  }
+  rpc CreateBook(CreateBookRequest) returns (Book) {
+    option (google.api.http) = { post: "/v1/{parent=shelves/*}/books" body: "book" };
+  }
+  rpc UpdateBook(UpdateBookRequest) returns (Book) {
+    option (google.api.http) = { patch: "/v1/{book.name=shelves/*/books/*}" body: "book" };
+  }
+  rpc DeleteBook(DeleteBookRequest) returns (google.protobuf.Empty) {
+    option (google.api.http) = { delete: "/v1/{name=shelves/*/books/*}" };
+  }
+  rpc ArchiveBook(ArchiveBookRequest) returns (Book) {
+    option (google.api.http) = { post: "/v1/{name=shelves/*/books/*}:archive" body: "*" };
+  }
+  rpc BatchGetBooks(BatchGetBooksRequest) returns (BatchGetBooksResponse) {
+    option (google.api.http) = { get: "/v1/{parent=shelves/*}/books:batchGet" };
+  }
+
+  rpc GetShelf(GetShelfRequest) returns (Shelf) {
+    option (google.api.http) = { post: "/v1/shelves/{shelf_id}" body: "*" };
+  }
+  rpc ListShelves(ListShelvesRequest) returns (ShelfList) {
+    option (google.api.http) = { get: "/v1/shelf" };
+  }
+  rpc UpdateShelf(UpdateShelfRequest) returns (Shelf) {
+    option (google.api.http) = { put: "/v1/shelves/{shelf_id}" body: "*" };
+  }
+  rpc MoveShelf(MoveShelfRequest) returns (Shelf) {
+    option (google.api.http) = { delete: "/v1/{name=shelves/*}/move" };
+  }
+  rpc DeleteShelf(DeleteShelfRequest) returns (Shelf);
+}
+
+message Book {
+  option (google.api.resource) = { type: "library.example.com/Book" pattern: "shelves/{shelf}/books/{book}" };
+  string name = 1;
+}
+message GetBookRequest { string name = 1; }
+message ListBooksRequest {
+  string parent = 1;
+  int32 page_size = 2;
+  string page_token = 3;
+}
+message ListBooksResponse {
+  repeated Book books = 1;
+  string next_page_token = 2;
+}
+message CreateBookRequest {
+  string parent = 1;
+  Book book = 2;
+}
+message UpdateBookRequest {
+  Book book = 1;
+  google.protobuf.FieldMask update_mask = 2;
+}
+message DeleteBookRequest { string name = 1; }
+message ArchiveBookRequest { string name = 1; }
+message BatchGetBooksRequest {
+  string parent = 1;
+  repeated string names = 2;
+}
+message BatchGetBooksResponse { repeated Book books = 1; }
+
+message Shelf {
+  option (google.api.resource) = { type: "library.example.com/Shelf" pattern: "shelves/{shelf}" };
+  string shelf_id = 1;
+}
+message Drawer { string drawer_id = 1; }
+message GetShelfRequest { string shelf_id = 1; }
+message ListShelvesRequest { int64 page_size = 1; }
+message ShelfList { repeated Shelf shelves = 1; }
+message UpdateShelfRequest { Shelf shelf = 1; }
+message MoveShelfRequest { string name = 1; }
+message DeleteShelfRequest { string name = 1; }
+`,
+	})
+
+	findings := runLinter(t, config.LintConfig{Rules: []string{"AIP"}}, sources)
+
+	var got []string
+	for _, finding := range findings {
+		got = append(got, finding.Rule+" "+finding.Message)
+	}
+	// The Book methods follow the AIPs, the Shelf methods do not
+	want := []string{
+		"AIP_HTTP_BODY GetShelf should not have an HTTP body, found body: \"*\"",
+		"AIP_HTTP_PATH HTTP path /v1/shelves/{shelf_id} of GetShelf should bind the resource name with a {name=...} variable",
+		"AIP_HTTP_VERB GetShelf is a standard Get method and should use HTTP GET, not POST",
+		"AIP_STANDARD_METHOD_REQUEST request GetShelfRequest of GetShelf should have a string name field identifying the Shelf",
+		"AIP_HTTP_PATH HTTP path /v1/shelf of ListShelves should end with the collection shelves",
+		"AIP_PAGINATION ListShelves should be paginated: missing int32 page_size in ListShelvesRequest, string page_token in ListShelvesRequest, string next_page_token in ShelfList",
+		"AIP_STANDARD_METHOD_RESPONSE ListShelves should return ListShelvesResponse, not library.v1.ShelfList",
+		"AIP_HTTP_BODY HTTP body of UpdateShelf should be the resource field \"shelf\", not \"*\"",
+		"AIP_HTTP_PATH HTTP path /v1/shelves/{shelf_id} of UpdateShelf should bind {shelf.name}",
+		"AIP_HTTP_VERB UpdateShelf is a standard Update method and should use HTTP PATCH, not PUT",
+		"AIP_UPDATE_FIELD_MASK request UpdateShelfRequest of UpdateShelf should have a google.protobuf.FieldMask update_mask field",
+		"AIP_HTTP_PATH HTTP path /v1/{name=shelves/*}/move of custom method MoveShelf should end with a custom verb such as :move",
+		"AIP_HTTP_VERB custom method MoveShelf should use HTTP POST, or GET if it only reads data, not DELETE",
+		"AIP_HTTP_BINDING rpc DeleteShelf should declare an HTTP binding with option (google.api.http)",
+		"AIP_RESOURCE_NAME resource Shelf should have a string name field",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected findings:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestRun_AIPNotDefault(t *testing.T) {
+	sources := withGoogleAPI(t, map[string]string{
+		"library/v1/library.proto": `syntax = "proto3";
+package library.v1;
+
+option go_package = "example.com/library/v1;libraryv1";
+
+// Library
+service Library {
+  // Gets a shelf
+  rpc GetShelf(GetShelfRequest) returns (GetShelfResponse);
+}
+
+// Request
+message GetShelfRequest {}
+
+// Response
+message GetShelfResponse {}
+`,
+	})
+
+	linter, err := New(config.LintConfig{Ignore: []string{"google"}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if findings := linter.Run(testutil.CompileDescriptors(t, sources), nil); len(findings) != 0 {
+		t.Errorf("Expected AIP rules to be opt-in, got %+v", findings)
+	}
+}
+
+func TestRun_Fixes(t *testing.T) {
+	sources := map[string]string{
+		"library/v1/api.proto": `syntax = "proto3";
+package library.v1;
+
+option go_package = "example.com/library/v1;libraryv1";
+
+import "library/v1/types.proto";
+import "google/protobuf/empty.proto";
+
+// Library management
+service Library {
+  // Gets a book
+  rpc GetBook(GetBookRequest) returns (book_info);
+  // Deletes a book
+  rpc DeleteBook(DeleteBookRequest) returns (google.protobuf.Empty);
+}
+
+// Get request
+message GetBookRequest {
+  // The book
+  string Book_Name = 1;
+}
+
+// Delete request
+message DeleteBookRequest {
+  // The book
+  string name = 1;
+  // Why
+  .library.v1.book_info.reason_code reason = 2;
+}
+`,
+		"library/v1/types.proto": `syntax = "proto3";
+package library.v1;
+
+option go_package = "example.com/library/v1;libraryv1";
+
+// A book
+message book_info {
+  // Reason codes
+  enum reason_code {
+    // Unknown
+    Unknown = 0;
+  }
+  // The state
+  State state = 1;
+  string title = 2;
+  // A reason
+  book_info.reason_code reason = 3;
+}
+
+// States
+enum State {
+  // Draft
+  STATE_DRAFT = 0;
+}
+`,
+	}
+
+	cfg := config.LintConfig{Except: []string{"RPC_REQUEST_STANDARD_NAME", "RPC_RESPONSE_STANDARD_NAME"}}
+	findings := runLinter(t, cfg, sources)
+	if len(findings) == 0 {
+		t.Fatal("Expected findings")
+	}
+
+	// Conflicting fixes are left for the next run, so fix until clean
+	for pass := 0; len(findings) > 0; pass++ {
+		if pass == 3 {
+			t.Fatalf("Expected no findings after fixing, got %q", rules(findings))
+		}
+		edits := Edits(findings)
+		if len(edits) == 0 {
+			t.Fatalf("Expected fixes for %q", rules(findings))
+		}
+		byFile := make(map[string][]textedit.Edit)
+		for _, edit := range edits {
+			byFile[edit.File] = append(byFile[edit.File], edit)
+		}
+		for file, edits := range byFile {
+			fixed, err := textedit.Apply([]byte(sources[file]), edits)
+			if err != nil {
+				t.Fatalf("Failed to apply edits to %s: %v", file, err)
+			}
+			sources[file] = string(fixed)
+		}
+		findings = runLinter(t, cfg, sources)
+	}
+
+	expected := `syntax = "proto3";
+package library.v1;
+
+option go_package = "example.com/library/v1;libraryv1";
+
+import "google/protobuf/empty.proto";
+import "library/v1/types.proto";
+
+// Library management
+service Library {
+  // Gets a book
+  rpc GetBook(GetBookRequest) returns (BookInfo);
+  // Deletes a book
+  rpc DeleteBook(DeleteBookRequest) returns (google.protobuf.Empty);
+}
+
+// Get request
+message GetBookRequest {
+  // The book
+  string book_name = 1;
+}
+
+// Delete request
+message DeleteBookRequest {
+  // The book
+  string name = 1;
+  // Why
+  .library.v1.BookInfo.ReasonCode reason = 2;
+}
+`
+	if sources["library/v1/api.proto"] != expected {
+		t.Errorf("Unexpected fixed api.proto:\n%s", sources["library/v1/api.proto"])
+	}
+	if !strings.Contains(sources["library/v1/types.proto"], `  // TODO: Describe title.
+  string title = 2;`) {
+		t.Errorf("Expected a comment placeholder in types.proto:\n%s", sources["library/v1/types.proto"])
+	}
+}
+
+func TestRun_FixesSkipped(t *testing.T) {
+	findings := runLinter(t, config.LintConfig{Rules: []string{"MESSAGE_PASCAL_CASE", "FIELD_LOWER_SNAKE_CASE", "ENUM_ZERO_VALUE_SUFFIX"}}, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test.v1;
+
+message book {
+  // The new name is taken
+  string Title = 1;
+  string title = 2;
+}
+
+message Shelf {
+  // Map values cannot be renamed
+  map<string, book> books = 1;
+}
+`,
+		"legacy.proto": `syntax = "proto2";
+package test.v1;
+
+// A zero value would become the default of this closed enum
+enum Color {
+  RED = 1;
+}
+`,
+	})
+
+	if len(findings) != 3 {
+		t.Fatalf("Expected 3 findings, got %q", rules(findings))
+	}
+	for _, finding := range findings {
+		if len(finding.Edits) != 0 {
+			t.Errorf("Expected no fix for %+v", finding)
+		}
+	}
+}
+
+func TestInsertBeforeEdits(t *testing.T) {
+	source := `syntax = "proto3";
+package test.v1;
+
+enum State {
+  // Detached
+
+  // The first value,
+  // on two lines
+  STATE_FIRST = 0;
+  /* A block comment */
+  STATE_SECOND = 1;
+  STATE_THIRD = 2;
+}
+`
+	files := testutil.CompileDescriptors(t, map[string]string{"api.proto": source})
+	values := files[0].Enums().Get(0).Values()
+
+	var edits []textedit.Edit
+	edits = append(edits, insertBeforeEdits(values.Get(0), "// Before first")...)
+	edits = append(edits, insertBeforeEdits(values.Get(2), "// Before third")...)
+	if got := insertBeforeEdits(values.Get(1), "// Before second"); got != nil {
+		t.Errorf("Expected no edit after a block comment, got %+v", got)
+	}
+
+	fixed, err := textedit.Apply([]byte(source), edits)
+	if err != nil {
+		t.Fatalf("Failed to apply edits: %v", err)
+	}
+	expected := `syntax = "proto3";
+package test.v1;
+
+enum State {
+  // Detached
+
+  // Before first
+  // The first value,
+  // on two lines
+  STATE_FIRST = 0;
+  /* A block comment */
+  STATE_SECOND = 1;
+  // Before third
+  STATE_THIRD = 2;
+}
+`
+	if string(fixed) != expected {
+		t.Errorf("Unexpected result:\n%s", fixed)
+	}
+}
+
+func TestRename(t *testing.T) {
+	sources := map[string]string{
+		"types.proto": `syntax = "proto3";
+package test.v1;
+
+message Book {
+  message Page {}
+  Page first = 1;
+}
+`,
+		"api.proto": `syntax = "proto3";
+package test.v1;
+
+import "types.proto";
+
+message Shelf {
+  repeated Book books = 1;
+  .test.v1.Book.Page cover = 2;
+  map<string, Shelf> children = 3;
+}
+`,
+	}
+	files := testutil.CompileDescriptors(t, sources)
+	find := func(name protoreflect.FullName) protoreflect.Descriptor {
+		for _, file := range files {
+			if desc := file.(linker.File).FindDescriptorByName(name); desc != nil {
+				return desc
+			}
+		}
+		t.Fatalf("%s not found", name)
+		return nil
+	}
+
+	edits, err := Rename(files, find("test.v1.Book"), "Volume")
+	if err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	byFile := make(map[string][]textedit.Edit)
+	for _, edit := range edits {
+		byFile[edit.File] = append(byFile[edit.File], edit)
+	}
+	api, err := textedit.Apply([]byte(sources["api.proto"]), byFile["api.proto"])
+	if err != nil {
+		t.Fatalf("Failed to apply edits: %v", err)
+	}
+	if !strings.Contains(string(api), "repeated Volume books = 1;") || !strings.Contains(string(api), ".test.v1.Volume.Page cover = 2;") {
+		t.Errorf("Unexpected renamed api.proto:\n%s", api)
+	}
+	types, err := textedit.Apply([]byte(sources["types.proto"]), byFile["types.proto"])
+	if err != nil {
+		t.Fatalf("Failed to apply edits: %v", err)
+	}
+	if !strings.Contains(string(types), "message Volume {") || !strings.Contains(string(types), "  Page first = 1;") {
+		t.Errorf("Unexpected renamed types.proto:\n%s", types)
+	}
+
+	if _, err := Rename(files, find("test.v1.Book"), "Shelf"); err == nil || !strings.Contains(err.Error(), "already declared") {
+		t.Errorf("Expected a taken name to fail, got %v", err)
+	}
+	if _, err := Rename(files, find("test.v1.Shelf"), "Rack"); err == nil || !strings.Contains(err.Error(), "map value") {
+		t.Errorf("Expected a map value reference to fail, got %v", err)
+	}
+}
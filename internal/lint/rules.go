@@ -0,0 +1,377 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+	"github.com/yuemori/protobuf-mcp-server/internal/textedit"
+)
+
+var (
+	pascalCase     = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	lowerSnakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+	upperSnakeCase = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+	// versionSuffix matches package versions such as v1, v2beta1, v1p1alpha
+	// and v1test
+	versionSuffix = regexp.MustCompile(`^v[1-9][0-9]*((p[1-9][0-9]*)?(alpha|beta)[0-9]*)?(test[a-z0-9]*)?$`)
+)
+
+// baseRules lists the style and comment rules in the order they are
+// documented
+var baseRules = []Rule{
+	{
+		ID:          "PACKAGE_VERSION_SUFFIX",
+		Group:       GroupStyle,
+		Description: "Packages end with a version component such as v1 or v1beta1",
+		check:       checkPackageVersionSuffix,
+	},
+	{
+		ID:          "FILE_GO_PACKAGE",
+		Group:       GroupStyle,
+		Description: "Files set the go_package option",
+		check:       checkGoPackage,
+	},
+	{
+		ID:          "FILE_IMPORTS_SORTED",
+		Group:       GroupStyle,
+		Description: "Imports are sorted by path",
+		check:       checkImportsSorted,
+	},
+	{
+		ID:          "MESSAGE_PASCAL_CASE",
+		Group:       GroupStyle,
+		Description: "Message names are PascalCase",
+		check:       checkPascalCase[protoreflect.MessageDescriptor]("message"),
+	},
+	{
+		ID:          "FIELD_LOWER_SNAKE_CASE",
+		Group:       GroupStyle,
+		Description: "Field and extension names are lower_snake_case",
+		check:       checkLowerSnakeCase[protoreflect.FieldDescriptor]("field"),
+	},
+	{
+		ID:          "ONEOF_LOWER_SNAKE_CASE",
+		Group:       GroupStyle,
+		Description: "Oneof names are lower_snake_case",
+		check:       checkLowerSnakeCase[protoreflect.OneofDescriptor]("oneof"),
+	},
+	{
+		ID:          "ENUM_PASCAL_CASE",
+		Group:       GroupStyle,
+		Description: "Enum names are PascalCase",
+		check:       checkPascalCase[protoreflect.EnumDescriptor]("enum"),
+	},
+	{
+		ID:          "ENUM_VALUE_UPPER_SNAKE_CASE",
+		Group:       GroupStyle,
+		Description: "Enum value names are UPPER_SNAKE_CASE",
+		check:       checkEnumValueUpperSnakeCase,
+	},
+	{
+		ID:          "ENUM_ZERO_VALUE_SUFFIX",
+		Group:       GroupStyle,
+		Description: "The zero value of an enum ends with _UNSPECIFIED",
+		check:       checkEnumZeroValueSuffix,
+	},
+	{
+		ID:          "SERVICE_PASCAL_CASE",
+		Group:       GroupStyle,
+		Description: "Service names are PascalCase",
+		check:       checkPascalCase[protoreflect.ServiceDescriptor]("service"),
+	},
+	{
+		ID:          "RPC_PASCAL_CASE",
+		Group:       GroupStyle,
+		Description: "RPC names are PascalCase",
+		check:       checkPascalCase[protoreflect.MethodDescriptor]("rpc"),
+	},
+	{
+		ID:          "RPC_REQUEST_STANDARD_NAME",
+		Group:       GroupStyle,
+		Description: "RPC requests are named <Method>Request or <Service><Method>Request",
+		check:       checkRPCStandardName("request", "Request", protoreflect.MethodDescriptor.Input),
+	},
+	{
+		ID:          "RPC_RESPONSE_STANDARD_NAME",
+		Group:       GroupStyle,
+		Description: "RPC responses are named <Method>Response or <Service><Method>Response",
+		check:       checkRPCStandardName("response", "Response", protoreflect.MethodDescriptor.Output),
+	},
+	{
+		ID:          "COMMENT_SERVICE",
+		Group:       GroupComments,
+		Description: "Services have a leading comment",
+		check:       checkComment[protoreflect.ServiceDescriptor]("service"),
+	},
+	{
+		ID:          "COMMENT_RPC",
+		Group:       GroupComments,
+		Description: "RPCs have a leading comment",
+		check:       checkComment[protoreflect.MethodDescriptor]("rpc"),
+	},
+	{
+		ID:          "COMMENT_MESSAGE",
+		Group:       GroupComments,
+		Description: "Messages have a leading comment",
+		check:       checkComment[protoreflect.MessageDescriptor]("message"),
+	},
+	{
+		ID:          "COMMENT_FIELD",
+		Group:       GroupComments,
+		Description: "Fields and extensions have a leading comment",
+		check:       checkComment[protoreflect.FieldDescriptor]("field"),
+	},
+	{
+		ID:          "COMMENT_ENUM",
+		Group:       GroupComments,
+		Description: "Enums have a leading comment",
+		check:       checkComment[protoreflect.EnumDescriptor]("enum"),
+	},
+	{
+		ID:          "COMMENT_ENUM_VALUE",
+		Group:       GroupComments,
+		Description: "Enum values have a leading comment",
+		check:       checkComment[protoreflect.EnumValueDescriptor]("enum value"),
+	},
+}
+
+// checkPackageVersionSuffix reports packages without a version component
+func checkPackageVersionSuffix(r *reporter, desc protoreflect.Descriptor) {
+	file, ok := desc.(protoreflect.FileDescriptor)
+	if !ok {
+		return
+	}
+	pkg := file.Package()
+	if pkg == "" {
+		r.report(file, "file %s has no package; declare a versioned package such as \"foo.v1\"", file.Path())
+		return
+	}
+	if !versionSuffix.MatchString(string(pkg.Name())) {
+		r.report(file, "package %s should end with a version such as %s.v1", pkg, pkg)
+	}
+}
+
+// checkGoPackage reports files without the go_package option
+func checkGoPackage(r *reporter, desc protoreflect.Descriptor) {
+	file, ok := desc.(protoreflect.FileDescriptor)
+	if !ok {
+		return
+	}
+	options, _ := file.Options().(*descriptorpb.FileOptions)
+	if options.GetGoPackage() == "" {
+		r.report(file, "file %s does not set option go_package", file.Path())
+	}
+}
+
+// checkPascalCase returns a check of the names of one kind of declaration
+func checkPascalCase[T protoreflect.Descriptor](kind string) func(*reporter, protoreflect.Descriptor) {
+	return func(r *reporter, desc protoreflect.Descriptor) {
+		if _, ok := desc.(T); !ok {
+			return
+		}
+		if name := string(desc.Name()); !pascalCase.MatchString(name) {
+			fixed := toPascalCase(name)
+			r.fix(desc, r.renameEdits(desc, fixed), "%s name %s should be PascalCase, e.g. %s", kind, name, fixed)
+		}
+	}
+}
+
+// checkLowerSnakeCase returns a check of the names of one kind of declaration
+func checkLowerSnakeCase[T protoreflect.Descriptor](kind string) func(*reporter, protoreflect.Descriptor) {
+	return func(r *reporter, desc protoreflect.Descriptor) {
+		if _, ok := desc.(T); !ok {
+			return
+		}
+		if name := string(desc.Name()); !lowerSnakeCase.MatchString(name) {
+			fixed := toLowerSnakeCase(name)
+			r.fix(desc, r.renameEdits(desc, fixed), "%s name %s should be lower_snake_case, e.g. %s", kind, name, fixed)
+		}
+	}
+}
+
+// checkEnumValueUpperSnakeCase reports enum values not in UPPER_SNAKE_CASE
+func checkEnumValueUpperSnakeCase(r *reporter, desc protoreflect.Descriptor) {
+	value, ok := desc.(protoreflect.EnumValueDescriptor)
+	if !ok {
+		return
+	}
+	if name := string(value.Name()); !upperSnakeCase.MatchString(name) {
+		fixed := toUpperSnakeCase(name)
+		r.fix(value, r.renameEdits(value, fixed), "enum value name %s should be UPPER_SNAKE_CASE, e.g. %s", name, fixed)
+	}
+}
+
+// checkEnumZeroValueSuffix reports enums whose zero value does not end with
+// _UNSPECIFIED, such as a meaningful value that would be the default. The
+// fix renames the zero value, or adds one before the first value.
+func checkEnumZeroValueSuffix(r *reporter, desc protoreflect.Descriptor) {
+	enum, ok := desc.(protoreflect.EnumDescriptor)
+	if !ok {
+		return
+	}
+	expected := toUpperSnakeCase(string(enum.Name())) + "_UNSPECIFIED"
+	zero := enum.Values().ByNumber(0)
+	if zero == nil {
+		// A new first value would become the default of closed enums
+		var edits []textedit.Edit
+		if !enum.IsClosed() && !r.index.names[enum.FullName().Parent().Append(protoreflect.Name(expected))] {
+			edits = insertBeforeEdits(enum.Values().Get(0), expected+" = 0;")
+		}
+		r.fix(enum, edits, "enum %s has no zero value; add %s = 0", enum.Name(), expected)
+		return
+	}
+	if !strings.HasSuffix(string(zero.Name()), "_UNSPECIFIED") {
+		r.fix(zero, r.renameEdits(zero, expected), "zero value %s of enum %s should end with _UNSPECIFIED, e.g. %s", zero.Name(), enum.Name(), expected)
+	}
+}
+
+// checkImportsSorted reports files whose imports are not sorted by path. The
+// fix rewrites the imports when they are on consecutive lines without
+// comments between them.
+func checkImportsSorted(r *reporter, desc protoreflect.Descriptor) {
+	file, ok := desc.(protoreflect.FileDescriptor)
+	if !ok {
+		return
+	}
+	imports := file.Imports()
+	for i := 1; i < imports.Len(); i++ {
+		if imports.Get(i).Path() < imports.Get(i-1).Path() {
+			r.findings = append(r.findings, Finding{
+				Rule:     r.rule,
+				Message:  fmt.Sprintf("imports should be sorted: %q should come before %q", imports.Get(i).Path(), imports.Get(i-1).Path()),
+				File:     file.Path(),
+				Location: protoutil.SpanOfLocation(file, file.SourceLocations().ByPath(protoreflect.SourcePath{dependencyTag, int32(i)})),
+				Edits:    sortImportsEdits(file),
+			})
+			return
+		}
+	}
+}
+
+// sortImportsEdits returns the edit rewriting the imports of a file in
+// order, or nil if they are not on consecutive lines or have comments
+func sortImportsEdits(file protoreflect.FileDescriptor) []textedit.Edit {
+	imports := file.Imports()
+	statements := make([]string, imports.Len())
+	var first, last protoreflect.SourceLocation
+	for i := 0; i < imports.Len(); i++ {
+		loc := file.SourceLocations().ByPath(protoreflect.SourcePath{dependencyTag, int32(i)})
+		if loc.Path == nil || loc.StartLine != loc.EndLine || loc.LeadingComments != "" || loc.TrailingComments != "" ||
+			(i > 0 && loc.StartLine != last.StartLine+1) || (i > 0 && loc.StartColumn != first.StartColumn) {
+			return nil
+		}
+		if i == 0 {
+			first = loc
+		}
+		last = loc
+
+		imp := imports.Get(i)
+		switch {
+		case imp.IsPublic:
+			statements[i] = fmt.Sprintf("import public %q;", imp.Path())
+		case imp.IsWeak:
+			statements[i] = fmt.Sprintf("import weak %q;", imp.Path())
+		default:
+			statements[i] = fmt.Sprintf("import %q;", imp.Path())
+		}
+	}
+
+	// The current text is rebuilt the same way, so that the edit is refused
+	// if the statements were written differently, e.g. with single quotes
+	oldText := strings.Join(statements, "\n"+strings.Repeat(" ", first.StartColumn))
+	order := make([]int, imports.Len())
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return imports.Get(order[i]).Path() < imports.Get(order[j]).Path() })
+	sorted := make([]string, len(order))
+	for i, index := range order {
+		sorted[i] = statements[index]
+	}
+	return []textedit.Edit{{
+		File:        file.Path(),
+		StartLine:   first.StartLine + 1,
+		StartColumn: first.StartColumn + 1,
+		EndLine:     last.EndLine + 1,
+		EndColumn:   last.EndColumn + 1,
+		OldText:     oldText,
+		NewText:     strings.Join(sorted, "\n"+strings.Repeat(" ", first.StartColumn)),
+	}}
+}
+
+// checkRPCStandardName returns a check that the request or response of an
+// RPC is named after the RPC
+func checkRPCStandardName(kind, suffix string, messageOf func(protoreflect.MethodDescriptor) protoreflect.MessageDescriptor) func(*reporter, protoreflect.Descriptor) {
+	return func(r *reporter, desc protoreflect.Descriptor) {
+		method, ok := desc.(protoreflect.MethodDescriptor)
+		if !ok {
+			return
+		}
+		name := string(messageOf(method).Name())
+		standard := string(method.Name()) + suffix
+		if name == standard || name == string(method.Parent().Name())+standard {
+			return
+		}
+		r.report(method, "%s of rpc %s should be named %s, not %s", kind, method.Name(), standard, messageOf(method).FullName())
+	}
+}
+
+// checkComment returns a check that one kind of declaration has a leading
+// comment
+func checkComment[T protoreflect.Descriptor](kind string) func(*reporter, protoreflect.Descriptor) {
+	return func(r *reporter, desc protoreflect.Descriptor) {
+		if _, ok := desc.(T); !ok {
+			return
+		}
+		loc := desc.ParentFile().SourceLocations().ByDescriptor(desc)
+		if loc.Path != nil && strings.TrimSpace(loc.LeadingComments) == "" {
+			r.fix(desc, insertBeforeEdits(desc, "// TODO: Describe "+string(desc.Name())+"."), "%s %s should have a comment", kind, desc.Name())
+		}
+	}
+}
+
+// words splits a name into words at underscores and case changes, keeping
+// acronyms together: "HTTPServer_v2" gives "HTTP", "Server", "v2"
+func words(name string) []string {
+	var result []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' }) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			previous, current := runes[i-1], runes[i]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsUpper(current) && (unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextLower)) {
+				result = append(result, string(runes[start:i]))
+				start = i
+			}
+		}
+		result = append(result, string(runes[start:]))
+	}
+	return result
+}
+
+// toPascalCase converts a name to PascalCase
+func toPascalCase(name string) string {
+	var b strings.Builder
+	for _, word := range words(name) {
+		b.WriteString(strings.ToUpper(word[:1]) + strings.ToLower(word[1:]))
+	}
+	return b.String()
+}
+
+// toLowerSnakeCase converts a name to lower_snake_case
+func toLowerSnakeCase(name string) string {
+	return strings.ToLower(strings.Join(words(name), "_"))
+}
+
+// toUpperSnakeCase converts a name to UPPER_SNAKE_CASE
+func toUpperSnakeCase(name string) string {
+	return strings.ToUpper(strings.Join(words(name), "_"))
+}
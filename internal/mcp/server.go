@@ -50,12 +50,50 @@ func NewMCPServer() *MCPServer {
 	activateTool := tools.NewActivateProjectTool(projectManager)
 	listServicesTool := tools.NewListServicesTool(projectManager)
 	getSchemaTool := tools.NewGetSchemaTool(projectManager)
+	listFilesTool := tools.NewListFilesTool(projectManager)
+	getFileTool := tools.NewGetFileTool(projectManager)
+	listPackagesTool := tools.NewListPackagesTool(projectManager)
+	resolveTypeTool := tools.NewResolveTypeTool(projectManager)
+	describeMethodTool := tools.NewDescribeMethodTool(projectManager)
+	findReferencesTool := tools.NewFindReferencesTool(projectManager)
+	getDependencyGraphTool := tools.NewGetDependencyGraphTool(projectManager)
+	renderDiagramTool := tools.NewRenderDiagramTool(projectManager)
+	definitionAtTool := tools.NewDefinitionAtTool(projectManager)
+	searchSchemaTool := tools.NewSearchSchemaTool(projectManager)
+	querySchemaTool := tools.NewQuerySchemaTool(projectManager)
+	getProtoSourceTool := tools.NewGetProtoSourceTool(projectManager)
+	checkBreakingTool := tools.NewCheckBreakingTool(projectManager)
+	diffSchemaTool := tools.NewDiffSchemaTool(projectManager)
+	lintTool := tools.NewLintTool(projectManager)
+	formatProtoTool := tools.NewFormatProtoTool(projectManager)
+	planFieldAdditionTool := tools.NewPlanFieldAdditionTool(projectManager)
+	planFieldRemovalTool := tools.NewPlanFieldRemovalTool(projectManager)
+	renameSymbolTool := tools.NewRenameSymbolTool(projectManager)
 	onboarding := tools.NewOnboardingTool(projectManager)
 
 	// Register tools with the server
 	s.AddTool(activateTool.GetTool(), activateTool.Handle)
 	s.AddTool(listServicesTool.GetTool(), listServicesTool.Handle)
 	s.AddTool(getSchemaTool.GetTool(), getSchemaTool.Handle)
+	s.AddTool(listFilesTool.GetTool(), listFilesTool.Handle)
+	s.AddTool(getFileTool.GetTool(), getFileTool.Handle)
+	s.AddTool(listPackagesTool.GetTool(), listPackagesTool.Handle)
+	s.AddTool(resolveTypeTool.GetTool(), resolveTypeTool.Handle)
+	s.AddTool(describeMethodTool.GetTool(), describeMethodTool.Handle)
+	s.AddTool(findReferencesTool.GetTool(), findReferencesTool.Handle)
+	s.AddTool(getDependencyGraphTool.GetTool(), getDependencyGraphTool.Handle)
+	s.AddTool(renderDiagramTool.GetTool(), renderDiagramTool.Handle)
+	s.AddTool(definitionAtTool.GetTool(), definitionAtTool.Handle)
+	s.AddTool(searchSchemaTool.GetTool(), searchSchemaTool.Handle)
+	s.AddTool(querySchemaTool.GetTool(), querySchemaTool.Handle)
+	s.AddTool(getProtoSourceTool.GetTool(), getProtoSourceTool.Handle)
+	s.AddTool(checkBreakingTool.GetTool(), checkBreakingTool.Handle)
+	s.AddTool(diffSchemaTool.GetTool(), diffSchemaTool.Handle)
+	s.AddTool(lintTool.GetTool(), lintTool.Handle)
+	s.AddTool(formatProtoTool.GetTool(), formatProtoTool.Handle)
+	s.AddTool(planFieldAdditionTool.GetTool(), planFieldAdditionTool.Handle)
+	s.AddTool(planFieldRemovalTool.GetTool(), planFieldRemovalTool.Handle)
+	s.AddTool(renameSymbolTool.GetTool(), renameSymbolTool.Handle)
 	s.AddTool(onboarding.GetTool(), onboarding.Handle)
 
 	return &MCPServer{
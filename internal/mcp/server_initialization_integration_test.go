@@ -77,9 +77,28 @@ func TestServerInitialization_Integration(t *testing.T) {
 
 		// Verify we have the expected tools
 		expectedTools := map[string]bool{
-			"activate_project": false,
-			"list_services":    false,
-			"get_schema":       false,
+			"activate_project":     false,
+			"list_services":        false,
+			"get_schema":           false,
+			"list_files":           false,
+			"get_file":             false,
+			"list_packages":        false,
+			"resolve_type":         false,
+			"describe_method":      false,
+			"find_references":      false,
+			"get_dependency_graph": false,
+			"render_diagram":       false,
+			"definition_at":        false,
+			"search_schema":        false,
+			"query_schema":         false,
+			"get_proto_source":     false,
+			"check_breaking":       false,
+			"diff_schema":          false,
+			"lint":                 false,
+			"format_proto":         false,
+			"plan_field_addition":  false,
+			"plan_field_removal":   false,
+			"rename_symbol":        false,
 		}
 
 		for _, tool := range toolsResult.Tools {
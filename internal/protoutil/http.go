@@ -0,0 +1,119 @@
+// Package protoutil provides helpers for inspecting compiled protobuf
+// descriptors that are shared between tools, the linter and the CLI.
+package protoutil
+
+import (
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// HTTPOptionName is the full name of the google.api.http method option
+const HTTPOptionName = "google.api.http"
+
+// HTTPBinding represents a single HTTP binding of an RPC method declared with
+// the google.api.http option
+type HTTPBinding struct {
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	Body         string `json:"body,omitempty"`
+	ResponseBody string `json:"response_body,omitempty"`
+}
+
+// httpPatternFields are the HttpRule fields holding a standard HTTP verb
+var httpPatternFields = []protoreflect.Name{"get", "put", "post", "delete", "patch"}
+
+// HTTPBindings returns the HTTP bindings of a method, the primary binding
+// first followed by any additional bindings. Returns nil if the method has no
+// google.api.http option.
+func HTTPBindings(method protoreflect.MethodDescriptor) []HTTPBinding {
+	options := method.Options()
+	if options == nil {
+		return nil
+	}
+
+	var rule protoreflect.Message
+	options.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if field.IsExtension() && field.FullName() == HTTPOptionName && field.Message() != nil {
+			rule = value.Message()
+			return false
+		}
+		return true
+	})
+	if rule == nil {
+		return nil
+	}
+
+	bindings := []HTTPBinding{}
+	if binding, ok := httpBindingOf(rule); ok {
+		bindings = append(bindings, binding)
+	}
+	if field := rule.Descriptor().Fields().ByName("additional_bindings"); field != nil && field.IsList() {
+		list := rule.Get(field).List()
+		for i := 0; i < list.Len(); i++ {
+			if binding, ok := httpBindingOf(list.Get(i).Message()); ok {
+				bindings = append(bindings, binding)
+			}
+		}
+	}
+	return bindings
+}
+
+// httpBindingOf converts a google.api.HttpRule message to an HTTPBinding
+func httpBindingOf(rule protoreflect.Message) (HTTPBinding, bool) {
+	fields := rule.Descriptor().Fields()
+	binding := HTTPBinding{
+		Body:         stringField(rule, fields.ByName("body")),
+		ResponseBody: stringField(rule, fields.ByName("response_body")),
+	}
+
+	for _, name := range httpPatternFields {
+		if field := fields.ByName(name); field != nil && rule.Has(field) {
+			binding.Method = strings.ToUpper(string(name))
+			binding.Path = rule.Get(field).String()
+			return binding, true
+		}
+	}
+
+	if field := fields.ByName("custom"); field != nil && rule.Has(field) && field.Message() != nil {
+		custom := rule.Get(field).Message()
+		binding.Method = stringField(custom, custom.Descriptor().Fields().ByName("kind"))
+		binding.Path = stringField(custom, custom.Descriptor().Fields().ByName("path"))
+		return binding, true
+	}
+
+	return binding, false
+}
+
+// stringField returns the value of a string field, or "" if field is nil
+func stringField(message protoreflect.Message, field protoreflect.FieldDescriptor) string {
+	if field == nil || field.Kind() != protoreflect.StringKind {
+		return ""
+	}
+	return message.Get(field).String()
+}
+
+// pathVariablePattern matches variables in a path template such as
+// {user_id} or {name=projects/*/users/*}
+var pathVariablePattern = regexp.MustCompile(`\{([^}=]+)(=[^}]*)?\}`)
+
+// PathVariables returns the field paths bound by the variables of an HTTP path
+// template, e.g. "/v1/{name=users/*}/books/{book_id}" yields [name book_id]
+func PathVariables(path string) []string {
+	var variables []string
+	for _, match := range pathVariablePattern.FindAllStringSubmatch(path, -1) {
+		variables = append(variables, strings.TrimSpace(match[1]))
+	}
+	return variables
+}
+
+// ReplacePathVariables returns an HTTP path template with the field path of
+// each variable replaced by fn, keeping segment patterns, e.g. renaming
+// user_id in "/v1/{user_id}" or "/v1/{user_id=users/*}"
+func ReplacePathVariables(path string, fn func(variable string) string) string {
+	return pathVariablePattern.ReplaceAllStringFunc(path, func(match string) string {
+		parts := pathVariablePattern.FindStringSubmatch(match)
+		return "{" + fn(strings.TrimSpace(parts[1])) + parts[2] + "}"
+	})
+}
@@ -0,0 +1,111 @@
+package protoutil
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/testutil"
+)
+
+// compileSource compiles a single proto source, with source info, that may
+// import the google/api annotations from the repository's test project
+func compileSource(t *testing.T, source string) protoreflect.FileDescriptor {
+	t.Helper()
+
+	sources := map[string]string{"test.proto": source}
+	for _, name := range []string{"google/api/annotations.proto", "google/api/http.proto"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "test-project", name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		sources[name] = string(data)
+	}
+
+	return testutil.Compile(t, sources).FindFileByPath("test.proto")
+}
+
+func TestHTTPBindings(t *testing.T) {
+	file := compileSource(t, `syntax = "proto3";
+package test;
+
+import "google/api/annotations.proto";
+
+message Request { string name = 1; }
+
+service Books {
+  rpc GetBook(Request) returns (Request) {
+    option (google.api.http) = {
+      get: "/v1/{name=shelves/*/books/*}"
+      additional_bindings {
+        post: "/v1/{name=shelves/*/books/*}:get"
+        body: "*"
+        response_body: "name"
+      }
+    };
+  }
+  rpc Custom(Request) returns (Request) {
+    option (google.api.http) = {
+      custom: { kind: "HEAD" path: "/v1/books" }
+    };
+  }
+  rpc NoBinding(Request) returns (Request);
+}
+`)
+
+	methods := file.Services().Get(0).Methods()
+
+	expected := []HTTPBinding{
+		{Method: "GET", Path: "/v1/{name=shelves/*/books/*}"},
+		{Method: "POST", Path: "/v1/{name=shelves/*/books/*}:get", Body: "*", ResponseBody: "name"},
+	}
+	if got := HTTPBindings(methods.ByName("GetBook")); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, got)
+	}
+
+	expected = []HTTPBinding{{Method: "HEAD", Path: "/v1/books"}}
+	if got := HTTPBindings(methods.ByName("Custom")); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, got)
+	}
+
+	if got := HTTPBindings(methods.ByName("NoBinding")); got != nil {
+		t.Fatalf("Expected no bindings, got %+v", got)
+	}
+}
+
+func TestPathVariables(t *testing.T) {
+	tests := map[string][]string{
+		"/v1/users/{user_id}":                    {"user_id"},
+		"/v1/{name=shelves/*/books/*}":           {"name"},
+		"/v1/{parent=shelves/*}/books/{book.id}": {"parent", "book.id"},
+		"/v1/books":                              nil,
+	}
+
+	for path, expected := range tests {
+		if got := PathVariables(path); !reflect.DeepEqual(got, expected) {
+			t.Errorf("PathVariables(%q) = %v, expected %v", path, got, expected)
+		}
+	}
+}
+
+func TestReplacePathVariables(t *testing.T) {
+	rename := func(variable string) string {
+		return strings.ReplaceAll(variable, "book", "volume")
+	}
+	tests := map[string]string{
+		"/v1/users/{user_id}":                    "/v1/users/{user_id}",
+		"/v1/{book=shelves/*/books/*}":           "/v1/{volume=shelves/*/books/*}",
+		"/v1/{parent=shelves/*}/books/{book.id}": "/v1/{parent=shelves/*}/books/{volume.id}",
+		"/v1/books":                              "/v1/books",
+	}
+
+	for path, expected := range tests {
+		if got := ReplacePathVariables(path, rename); got != expected {
+			t.Errorf("ReplacePathVariables(%q) = %q, expected %q", path, got, expected)
+		}
+	}
+}
@@ -0,0 +1,939 @@
+package protoutil
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Largest field and enum numbers, printed as "max" in ranges
+const (
+	maxFieldNumber = 536870911
+	maxEnumNumber  = math.MaxInt32
+)
+
+// Printer renders compiled descriptors back into .proto source text.
+//
+// The output is canonical rather than a copy of the original source: files
+// keep the source order of their top-level declarations, but the members of
+// messages are laid out as options, reserved statements, fields (with
+// oneofs at the position of their first field), extension ranges, nested
+// enums, nested messages and extend blocks. Type names are written relative
+// to the file's package. Comments are taken from the retained source info.
+type Printer struct {
+	// OmitComments drops comments from the output
+	OmitComments bool
+}
+
+// Print renders a file, message, enum, service, field or extension as
+// .proto text. Declarations other than files are printed on their own, with
+// type names relative to the package of the file that declares them.
+func (p Printer) Print(desc protoreflect.Descriptor) string {
+	w := &protoWriter{printer: p, pkg: desc.ParentFile().Package()}
+	if parent, ok := desc.Parent().(protoreflect.MessageDescriptor); ok {
+		w.scope = parent
+	}
+	switch d := desc.(type) {
+	case protoreflect.FileDescriptor:
+		w.file(d)
+	case protoreflect.MessageDescriptor:
+		w.message(d)
+	case protoreflect.EnumDescriptor:
+		w.enum(d)
+	case protoreflect.ServiceDescriptor:
+		w.service(d)
+	case protoreflect.FieldDescriptor:
+		if d.IsExtension() {
+			w.extendBlock([]protoreflect.ExtensionDescriptor{d})
+		} else {
+			w.declaration(d, w.fieldDeclaration(d))
+		}
+	}
+	return w.b.String()
+}
+
+// FieldDeclaration renders a single field as a declaration line without
+// indentation or comments, e.g. `repeated string tags = 4 [deprecated = true];`
+func (p Printer) FieldDeclaration(field protoreflect.FieldDescriptor) string {
+	w := &protoWriter{printer: p, pkg: field.ParentFile().Package()}
+	if parent, ok := field.Parent().(protoreflect.MessageDescriptor); ok {
+		w.scope = parent
+	}
+	return w.fieldDeclaration(field)
+}
+
+// RelativeTypeName writes the name of a message or enum the way a field
+// declared in scope would reference it: as short as possible while still
+// resolving to the same type
+func RelativeTypeName(scope protoreflect.MessageDescriptor, name protoreflect.FullName) string {
+	w := &protoWriter{pkg: scope.ParentFile().Package(), scope: scope}
+	return w.typeName(name)
+}
+
+// protoWriter accumulates printed lines at the current indentation depth
+type protoWriter struct {
+	printer Printer
+	b       strings.Builder
+	depth   int
+	pkg     protoreflect.FullName
+	// scope is the innermost message being printed, used to shorten type names
+	scope protoreflect.MessageDescriptor
+}
+
+// line writes an indented line; an empty format writes a blank line
+func (w *protoWriter) line(format string, args ...interface{}) {
+	if format == "" {
+		w.b.WriteString("\n")
+		return
+	}
+	w.b.WriteString(strings.Repeat("  ", w.depth))
+	fmt.Fprintf(&w.b, format, args...)
+	w.b.WriteString("\n")
+}
+
+// location returns the source location of a descriptor, if retained
+func location(desc protoreflect.Descriptor) (protoreflect.SourceLocation, bool) {
+	loc := desc.ParentFile().SourceLocations().ByDescriptor(desc)
+	return loc, loc.Path != nil
+}
+
+// hasLeadingComments checks if a declaration has comments printed before it
+func (w *protoWriter) hasLeadingComments(desc protoreflect.Descriptor) bool {
+	if w.printer.OmitComments {
+		return false
+	}
+	loc, ok := location(desc)
+	return ok && (loc.LeadingComments != "" || len(loc.LeadingDetachedComments) > 0)
+}
+
+// leadingComments writes the detached and leading comments of a location
+func (w *protoWriter) leadingComments(loc protoreflect.SourceLocation) {
+	if w.printer.OmitComments {
+		return
+	}
+	for _, comment := range loc.LeadingDetachedComments {
+		w.comment(comment)
+		w.line("")
+	}
+	w.comment(loc.LeadingComments)
+}
+
+// trailingComment returns a single-line trailing comment to append to a
+// declaration line. Multi-line trailing comments are returned as extra
+// lines instead.
+func (w *protoWriter) trailingComment(desc protoreflect.Descriptor) (inline string, lines string) {
+	if w.printer.OmitComments {
+		return "", ""
+	}
+	loc, ok := location(desc)
+	if !ok || loc.TrailingComments == "" {
+		return "", ""
+	}
+	text := strings.TrimSuffix(loc.TrailingComments, "\n")
+	if !strings.Contains(text, "\n") {
+		return " //" + text, ""
+	}
+	return "", text
+}
+
+// comment writes comment text as // lines
+func (w *protoWriter) comment(text string) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			w.line("//")
+			continue
+		}
+		w.line("//%s", strings.TrimRight(line, " \t"))
+	}
+}
+
+// declaration writes the comments of a declaration followed by its line
+func (w *protoWriter) declaration(desc protoreflect.Descriptor, text string) {
+	if loc, ok := location(desc); ok {
+		w.leadingComments(loc)
+	}
+	inline, lines := w.trailingComment(desc)
+	w.line("%s%s", text, inline)
+	w.comment(lines)
+}
+
+// openBlock writes the comments of a declaration and its opening line
+func (w *protoWriter) openBlock(desc protoreflect.Descriptor, header string) {
+	w.declaration(desc, header+" {")
+	w.depth++
+}
+
+// closeBlock ends a block opened with openBlock
+func (w *protoWriter) closeBlock() {
+	w.depth--
+	w.line("}")
+}
+
+// file writes a whole file
+func (w *protoWriter) file(file protoreflect.FileDescriptor) {
+	syntaxLoc := file.SourceLocations().ByPath(protoreflect.SourcePath{12})
+	if syntaxLoc.Path == nil {
+		// Files declaring an edition locate the edition statement instead
+		syntaxLoc = file.SourceLocations().ByPath(protoreflect.SourcePath{14})
+	}
+	w.leadingComments(syntaxLoc)
+	if file.Syntax() == protoreflect.Editions {
+		edition := strings.TrimPrefix(protodesc.ToFileDescriptorProto(file).GetEdition().String(), "EDITION_")
+		w.line("edition = %q;", edition)
+	} else {
+		w.line("syntax = %q;", file.Syntax().String())
+	}
+
+	if file.Package() != "" {
+		w.line("")
+		w.line("package %s;", file.Package())
+	}
+
+	if file.Imports().Len() > 0 {
+		w.line("")
+		for i := 0; i < file.Imports().Len(); i++ {
+			imp := file.Imports().Get(i)
+			switch {
+			case imp.IsPublic:
+				w.line("import public %q;", imp.Path())
+			case imp.IsWeak:
+				w.line("import weak %q;", imp.Path())
+			default:
+				w.line("import %q;", imp.Path())
+			}
+		}
+	}
+
+	if options := w.optionStatements(file); len(options) > 0 {
+		w.line("")
+		for _, option := range options {
+			w.optionStatement(option)
+		}
+	}
+
+	// Top-level declarations keep their source order
+	type topLevel struct {
+		desc  protoreflect.Descriptor
+		group []protoreflect.ExtensionDescriptor
+	}
+	var decls []topLevel
+	for i := 0; i < file.Messages().Len(); i++ {
+		decls = append(decls, topLevel{desc: file.Messages().Get(i)})
+	}
+	for i := 0; i < file.Enums().Len(); i++ {
+		decls = append(decls, topLevel{desc: file.Enums().Get(i)})
+	}
+	for i := 0; i < file.Services().Len(); i++ {
+		decls = append(decls, topLevel{desc: file.Services().Get(i)})
+	}
+	for _, group := range extendGroups(file.Extensions()) {
+		decls = append(decls, topLevel{desc: group[0], group: group})
+	}
+	sort.SliceStable(decls, func(i, j int) bool {
+		return sourceBefore(decls[i].desc, decls[j].desc)
+	})
+
+	for _, decl := range decls {
+		w.line("")
+		switch d := decl.desc.(type) {
+		case protoreflect.MessageDescriptor:
+			w.message(d)
+		case protoreflect.EnumDescriptor:
+			w.enum(d)
+		case protoreflect.ServiceDescriptor:
+			w.service(d)
+		default:
+			w.extendBlock(decl.group)
+		}
+	}
+}
+
+// sourceBefore orders declarations by source position; declarations without
+// source info sort after those with it
+func sourceBefore(a, b protoreflect.Descriptor) bool {
+	locA, okA := location(a)
+	locB, okB := location(b)
+	if !okA || !okB {
+		return okA && !okB
+	}
+	if locA.StartLine != locB.StartLine {
+		return locA.StartLine < locB.StartLine
+	}
+	return locA.StartColumn < locB.StartColumn
+}
+
+// extendGroups groups consecutive extensions of the same message, which
+// share an extend block
+func extendGroups(extensions protoreflect.ExtensionDescriptors) [][]protoreflect.ExtensionDescriptor {
+	var groups [][]protoreflect.ExtensionDescriptor
+	for i := 0; i < extensions.Len(); i++ {
+		extension := extensions.Get(i)
+		if n := len(groups); n > 0 && groups[n-1][0].ContainingMessage().FullName() == extension.ContainingMessage().FullName() {
+			groups[n-1] = append(groups[n-1], extension)
+			continue
+		}
+		groups = append(groups, []protoreflect.ExtensionDescriptor{extension})
+	}
+	return groups
+}
+
+// message writes a message declaration
+func (w *protoWriter) message(message protoreflect.MessageDescriptor) {
+	options := w.optionStatements(message)
+	empty := len(options) == 0 && message.Fields().Len() == 0 && message.ReservedRanges().Len() == 0 &&
+		message.ReservedNames().Len() == 0 && message.ExtensionRanges().Len() == 0 &&
+		message.Enums().Len() == 0 && message.Messages().Len() == 0 && message.Extensions().Len() == 0
+	if empty {
+		w.declaration(message, fmt.Sprintf("message %s {}", message.Name()))
+		return
+	}
+
+	w.openBlock(message, "message "+string(message.Name()))
+	section := newSections(w)
+	outer := w.scope
+	w.scope = message
+	defer func() { w.scope = outer }()
+
+	if len(options) > 0 {
+		section.start()
+		for _, option := range options {
+			w.optionStatement(option)
+		}
+	}
+
+	if ranges := fieldRanges(message.ReservedRanges()); ranges != "" || message.ReservedNames().Len() > 0 {
+		section.start()
+		if ranges != "" {
+			w.line("reserved %s;", ranges)
+		}
+		if names := reservedNames(message.ReservedNames()); names != "" {
+			w.line("reserved %s;", names)
+		}
+	}
+
+	if message.Fields().Len() > 0 {
+		section.start()
+		printedOneofs := make(map[protoreflect.FullName]bool)
+		for i := 0; i < message.Fields().Len(); i++ {
+			field := message.Fields().Get(i)
+			if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+				if !printedOneofs[oneof.FullName()] {
+					printedOneofs[oneof.FullName()] = true
+					w.oneof(oneof, i > 0 && w.hasLeadingComments(oneof))
+				}
+				continue
+			}
+			if i > 0 && w.hasLeadingComments(field) {
+				w.line("")
+			}
+			w.declaration(field, w.fieldDeclaration(field))
+		}
+	}
+
+	if message.ExtensionRanges().Len() > 0 {
+		section.start()
+		w.line("extensions %s;", fieldRanges(message.ExtensionRanges()))
+	}
+
+	for i := 0; i < message.Enums().Len(); i++ {
+		section.start()
+		w.enum(message.Enums().Get(i))
+	}
+
+	for i := 0; i < message.Messages().Len(); i++ {
+		if nested := message.Messages().Get(i); !nested.IsMapEntry() {
+			section.start()
+			w.message(nested)
+		}
+	}
+
+	for _, group := range extendGroups(message.Extensions()) {
+		section.start()
+		w.extendBlock(group)
+	}
+
+	w.closeBlock()
+}
+
+// sections separates the sections of a block with blank lines
+type sections struct {
+	w       *protoWriter
+	started bool
+}
+
+func newSections(w *protoWriter) *sections {
+	return &sections{w: w}
+}
+
+// start begins a new section, preceded by a blank line unless it is the first
+func (s *sections) start() {
+	if s.started {
+		s.w.line("")
+	}
+	s.started = true
+}
+
+// oneof writes a oneof with its fields
+func (w *protoWriter) oneof(oneof protoreflect.OneofDescriptor, separate bool) {
+	if separate {
+		w.line("")
+	}
+	w.openBlock(oneof, "oneof "+string(oneof.Name()))
+	for _, option := range w.optionStatements(oneof) {
+		w.optionStatement(option)
+	}
+	for i := 0; i < oneof.Fields().Len(); i++ {
+		field := oneof.Fields().Get(i)
+		w.declaration(field, w.fieldDeclaration(field))
+	}
+	w.closeBlock()
+}
+
+// fieldDeclaration renders a field or extension declaration line
+func (w *protoWriter) fieldDeclaration(field protoreflect.FieldDescriptor) string {
+	var b strings.Builder
+	if label := fieldLabel(field); label != "" {
+		b.WriteString(label + " ")
+	}
+	fmt.Fprintf(&b, "%s %s = %d", w.fieldType(field), field.Name(), field.Number())
+
+	var options []string
+	if field.HasDefault() {
+		options = append(options, "default = "+w.defaultValue(field))
+	}
+	if !field.IsExtension() && field.HasJSONName() && field.JSONName() != DefaultJSONName(string(field.Name())) {
+		options = append(options, fmt.Sprintf("json_name = %s", quoteString(field.JSONName())))
+	}
+	for _, option := range w.optionStatements(field) {
+		options = append(options, fmt.Sprintf("%s = %s", option.name, w.optionValue(option, false)))
+	}
+	if len(options) > 0 {
+		fmt.Fprintf(&b, " [%s]", strings.Join(options, ", "))
+	}
+
+	b.WriteString(";")
+	return b.String()
+}
+
+// fieldLabel returns the label written before a field's type, if any
+func fieldLabel(field protoreflect.FieldDescriptor) string {
+	switch {
+	case field.IsMap():
+		return ""
+	case field.IsList():
+		return "repeated"
+	case field.ContainingOneof() != nil && !field.ContainingOneof().IsSynthetic():
+		return ""
+	case field.ParentFile().Syntax() == protoreflect.Editions:
+		return ""
+	case field.Cardinality() == protoreflect.Required:
+		return "required"
+	case field.HasOptionalKeyword():
+		return "optional"
+	default:
+		return ""
+	}
+}
+
+// fieldType returns the type of a field as written in source
+func (w *protoWriter) fieldType(field protoreflect.FieldDescriptor) string {
+	if field.IsMap() {
+		return fmt.Sprintf("map<%s, %s>", w.fieldType(field.MapKey()), w.fieldType(field.MapValue()))
+	}
+	switch {
+	case field.Message() != nil:
+		return w.typeName(field.Message().FullName())
+	case field.Enum() != nil:
+		return w.typeName(field.Enum().FullName())
+	default:
+		return field.Kind().String()
+	}
+}
+
+// typeName writes a type name as short as it can be while still resolving
+// to the same type from the current scope. A candidate relative name is only
+// used when no message between the current scope and the one it is
+// relative to declares something with the same first name component, which
+// would shadow it during resolution.
+func (w *protoWriter) typeName(name protoreflect.FullName) string {
+	var scopes []protoreflect.MessageDescriptor
+	for scope := w.scope; scope != nil; {
+		scopes = append(scopes, scope)
+		parent, ok := scope.Parent().(protoreflect.MessageDescriptor)
+		if !ok {
+			break
+		}
+		scope = parent
+	}
+
+	for i, scope := range scopes {
+		if relative, ok := strings.CutPrefix(string(name), string(scope.FullName())+"."); ok && !shadowed(relative, scopes[:i]) {
+			return relative
+		}
+	}
+	if w.pkg != "" {
+		if relative, ok := strings.CutPrefix(string(name), string(w.pkg)+"."); ok && !shadowed(relative, scopes) {
+			return relative
+		}
+	}
+	if shadowed(string(name), scopes) {
+		return "." + string(name)
+	}
+	return string(name)
+}
+
+// shadowed checks if any of the given messages declares a member named like
+// the first component of a relative type name
+func shadowed(relative string, scopes []protoreflect.MessageDescriptor) bool {
+	first, _, _ := strings.Cut(relative, ".")
+	name := protoreflect.Name(first)
+	for _, scope := range scopes {
+		if scope.Messages().ByName(name) != nil || scope.Enums().ByName(name) != nil ||
+			scope.Fields().ByName(name) != nil || scope.Oneofs().ByName(name) != nil ||
+			scope.Extensions().ByName(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultValue renders the explicit default of a field
+func (w *protoWriter) defaultValue(field protoreflect.FieldDescriptor) string {
+	if field.Kind() == protoreflect.EnumKind {
+		return string(field.DefaultEnumValue().Name())
+	}
+	return scalarLiteral(field, field.Default())
+}
+
+// DefaultJSONName computes the JSON name protoc derives from a field name,
+// used when the field sets no json_name option
+func DefaultJSONName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper && 'a' <= r && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+			upper = false
+		default:
+			b.WriteRune(r)
+			upper = false
+		}
+	}
+	return b.String()
+}
+
+// enum writes an enum declaration
+func (w *protoWriter) enum(enum protoreflect.EnumDescriptor) {
+	w.openBlock(enum, "enum "+string(enum.Name()))
+	section := newSections(w)
+
+	if options := w.optionStatements(enum); len(options) > 0 {
+		section.start()
+		for _, option := range options {
+			w.optionStatement(option)
+		}
+	}
+
+	if ranges := enumRanges(enum.ReservedRanges()); ranges != "" || enum.ReservedNames().Len() > 0 {
+		section.start()
+		if ranges != "" {
+			w.line("reserved %s;", ranges)
+		}
+		if names := reservedNames(enum.ReservedNames()); names != "" {
+			w.line("reserved %s;", names)
+		}
+	}
+
+	if enum.Values().Len() > 0 {
+		section.start()
+		for i := 0; i < enum.Values().Len(); i++ {
+			value := enum.Values().Get(i)
+			if i > 0 && w.hasLeadingComments(value) {
+				w.line("")
+			}
+			text := fmt.Sprintf("%s = %d", value.Name(), value.Number())
+			var options []string
+			for _, option := range w.optionStatements(value) {
+				options = append(options, fmt.Sprintf("%s = %s", option.name, w.optionValue(option, false)))
+			}
+			if len(options) > 0 {
+				text += fmt.Sprintf(" [%s]", strings.Join(options, ", "))
+			}
+			w.declaration(value, text+";")
+		}
+	}
+
+	w.closeBlock()
+}
+
+// service writes a service declaration
+func (w *protoWriter) service(service protoreflect.ServiceDescriptor) {
+	options := w.optionStatements(service)
+	if len(options) == 0 && service.Methods().Len() == 0 {
+		w.declaration(service, fmt.Sprintf("service %s {}", service.Name()))
+		return
+	}
+
+	w.openBlock(service, "service "+string(service.Name()))
+	for _, option := range options {
+		w.optionStatement(option)
+	}
+	if len(options) > 0 && service.Methods().Len() > 0 {
+		w.line("")
+	}
+
+	previousBlock := false
+	for i := 0; i < service.Methods().Len(); i++ {
+		method := service.Methods().Get(i)
+		methodOptions := w.optionStatements(method)
+		if i > 0 && (previousBlock || len(methodOptions) > 0 || w.hasLeadingComments(method)) {
+			w.line("")
+		}
+		previousBlock = len(methodOptions) > 0
+
+		input, output := w.typeName(method.Input().FullName()), w.typeName(method.Output().FullName())
+		if method.IsStreamingClient() {
+			input = "stream " + input
+		}
+		if method.IsStreamingServer() {
+			output = "stream " + output
+		}
+		signature := fmt.Sprintf("rpc %s(%s) returns (%s)", method.Name(), input, output)
+
+		if len(methodOptions) == 0 {
+			w.declaration(method, signature+";")
+			continue
+		}
+		w.openBlock(method, signature)
+		for _, option := range methodOptions {
+			w.optionStatement(option)
+		}
+		w.closeBlock()
+	}
+	w.closeBlock()
+}
+
+// extendBlock writes extensions of the same message as an extend block
+func (w *protoWriter) extendBlock(extensions []protoreflect.ExtensionDescriptor) {
+	w.line("extend %s {", w.typeName(extensions[0].ContainingMessage().FullName()))
+	w.depth++
+	for i, extension := range extensions {
+		if i > 0 && w.hasLeadingComments(extension) {
+			w.line("")
+		}
+		w.declaration(extension, w.fieldDeclaration(extension))
+	}
+	w.closeBlock()
+}
+
+// option is a single option value to print. Repeated options are split into
+// one option per element.
+type option struct {
+	name  string
+	field protoreflect.FieldDescriptor
+	value protoreflect.Value
+}
+
+// optionStatements returns the options set on a declaration, regular options
+// by field number followed by custom options by name
+func (w *protoWriter) optionStatements(desc protoreflect.Descriptor) []option {
+	options := desc.Options()
+	if options == nil {
+		return nil
+	}
+
+	var fields []protoreflect.FieldDescriptor
+	values := make(map[protoreflect.FieldDescriptor]protoreflect.Value)
+	options.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		// map_entry is implied by map fields and cannot be written in source
+		if field.FullName() == "google.protobuf.MessageOptions.map_entry" {
+			return true
+		}
+		fields = append(fields, field)
+		values[field] = value
+		return true
+	})
+	sortOptionFields(fields)
+
+	var result []option
+	for _, field := range fields {
+		name := string(field.Name())
+		if field.IsExtension() {
+			name = "(" + w.typeName(field.FullName()) + ")"
+		}
+		value := values[field]
+		if field.IsList() {
+			for i := 0; i < value.List().Len(); i++ {
+				result = append(result, option{name: name, field: field, value: value.List().Get(i)})
+			}
+			continue
+		}
+		result = append(result, option{name: name, field: field, value: value})
+	}
+	return result
+}
+
+// sortOptionFields orders regular fields by number, then extensions by name
+func sortOptionFields(fields []protoreflect.FieldDescriptor) {
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].IsExtension() != fields[j].IsExtension() {
+			return !fields[i].IsExtension()
+		}
+		if fields[i].IsExtension() {
+			return fields[i].FullName() < fields[j].FullName()
+		}
+		return fields[i].Number() < fields[j].Number()
+	})
+}
+
+// optionStatement writes an option statement; message values span multiple lines
+func (w *protoWriter) optionStatement(opt option) {
+	w.line("option %s = %s;", opt.name, w.optionValue(opt, true))
+}
+
+// optionValue renders the value of an option
+func (w *protoWriter) optionValue(opt option, multiline bool) string {
+	if opt.field.Message() == nil {
+		return scalarLiteral(opt.field, opt.value)
+	}
+	if !multiline {
+		return "{ " + strings.Join(w.textFields(opt.value.Message(), nil), " ") + " }"
+	}
+
+	lines := w.textFields(opt.value.Message(), []string{})
+	if len(lines) == 0 {
+		return "{}"
+	}
+	indent := strings.Repeat("  ", w.depth)
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, line := range lines {
+		b.WriteString(indent + "  " + line + "\n")
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// textFields renders the set fields of a message in protobuf text format,
+// one entry per element. When lines is non-nil nested messages are expanded
+// over multiple indented lines; otherwise every entry is a single line.
+func (w *protoWriter) textFields(message protoreflect.Message, lines []string) []string {
+	multiline := lines != nil
+
+	var fields []protoreflect.FieldDescriptor
+	message.Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, field)
+		return true
+	})
+	sortOptionFields(fields)
+
+	var entries []string
+	emit := func(name string, field protoreflect.FieldDescriptor, value protoreflect.Value) {
+		if field.Message() == nil {
+			entries = append(entries, fmt.Sprintf("%s: %s", name, scalarLiteral(field, value)))
+			return
+		}
+		if !multiline {
+			entries = append(entries, fmt.Sprintf("%s { %s }", name, strings.Join(w.textFields(value.Message(), nil), " ")))
+			return
+		}
+		nested := w.textFields(value.Message(), []string{})
+		if len(nested) == 0 {
+			entries = append(entries, name+" {}")
+			return
+		}
+		entries = append(entries, name+" {")
+		for _, line := range nested {
+			entries = append(entries, "  "+line)
+		}
+		entries = append(entries, "}")
+	}
+
+	for _, field := range fields {
+		name := field.TextName()
+		if field.IsExtension() {
+			name = "[" + string(field.FullName()) + "]"
+		}
+		value := message.Get(field)
+		switch {
+		case field.IsList():
+			for i := 0; i < value.List().Len(); i++ {
+				emit(name, field, value.List().Get(i))
+			}
+		case field.IsMap():
+			keyField, valueField := field.MapKey(), field.MapValue()
+			entryKeys := make([]protoreflect.MapKey, 0, value.Map().Len())
+			value.Map().Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+				entryKeys = append(entryKeys, key)
+				return true
+			})
+			sort.Slice(entryKeys, func(i, j int) bool {
+				return entryKeys[i].String() < entryKeys[j].String()
+			})
+			for _, key := range entryKeys {
+				entry := fmt.Sprintf("key: %s", scalarLiteral(keyField, key.Value()))
+				item := value.Map().Get(key)
+				if valueField.Message() != nil {
+					entry += fmt.Sprintf(" value { %s }", strings.Join(w.textFields(item.Message(), nil), " "))
+				} else {
+					entry += fmt.Sprintf(" value: %s", scalarLiteral(valueField, item))
+				}
+				entries = append(entries, fmt.Sprintf("%s { %s }", name, entry))
+			}
+		default:
+			emit(name, field, value)
+		}
+	}
+
+	return append(lines, entries...)
+}
+
+// scalarLiteral renders a scalar or enum value as a .proto literal
+func scalarLiteral(field protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch field.Kind() {
+	case protoreflect.EnumKind:
+		if enumValue := field.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return strconv.Itoa(int(value.Enum()))
+	case protoreflect.StringKind:
+		return quoteString(value.String())
+	case protoreflect.BytesKind:
+		return quoteBytes(value.Bytes())
+	case protoreflect.BoolKind:
+		return strconv.FormatBool(value.Bool())
+	case protoreflect.FloatKind:
+		return formatFloat(value.Float(), 32)
+	case protoreflect.DoubleKind:
+		return formatFloat(value.Float(), 64)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.FormatInt(value.Int(), 10)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatUint(value.Uint(), 10)
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+// formatFloat renders a floating point literal, including inf and nan
+func formatFloat(f float64, bits int) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "nan"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, bits)
+	}
+}
+
+// quoteString quotes a string literal, keeping printable UTF-8 as is
+func quoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			fmt.Fprintf(&b, "\\%03o", s[i])
+		} else {
+			writeEscaped(&b, r, s[i:i+size])
+		}
+		i += size
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// quoteBytes quotes a bytes literal, escaping every non-printable ASCII byte
+func quoteBytes(data []byte) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range data {
+		if c >= 0x80 {
+			fmt.Fprintf(&b, "\\%03o", c)
+			continue
+		}
+		writeEscaped(&b, rune(c), string(c))
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// writeEscaped writes a character of a quoted literal
+func writeEscaped(b *strings.Builder, r rune, raw string) {
+	switch r {
+	case '"':
+		b.WriteString(`\"`)
+	case '\\':
+		b.WriteString(`\\`)
+	case '\n':
+		b.WriteString(`\n`)
+	case '\r':
+		b.WriteString(`\r`)
+	case '\t':
+		b.WriteString(`\t`)
+	default:
+		if r < 0x20 || r == 0x7f {
+			fmt.Fprintf(b, "\\%03o", r)
+			return
+		}
+		b.WriteString(raw)
+	}
+}
+
+// fieldRanges renders field number ranges (end exclusive) as a reserved or
+// extensions list, e.g. "9, 20 to 25, 100 to max"
+func fieldRanges(ranges protoreflect.FieldRanges) string {
+	parts := make([]string, 0, ranges.Len())
+	for i := 0; i < ranges.Len(); i++ {
+		r := ranges.Get(i)
+		parts = append(parts, formatRange(int64(r[0]), int64(r[1])-1, maxFieldNumber))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// enumRanges renders enum number ranges (end inclusive) as a reserved list
+func enumRanges(ranges protoreflect.EnumRanges) string {
+	parts := make([]string, 0, ranges.Len())
+	for i := 0; i < ranges.Len(); i++ {
+		r := ranges.Get(i)
+		parts = append(parts, formatRange(int64(r[0]), int64(r[1]), maxEnumNumber))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatRange renders an inclusive range, writing "max" for the largest number
+func formatRange(start, end, maxNumber int64) string {
+	if start == end {
+		return strconv.FormatInt(start, 10)
+	}
+	endText := strconv.FormatInt(end, 10)
+	if end == maxNumber {
+		endText = "max"
+	}
+	return fmt.Sprintf("%d to %s", start, endText)
+}
+
+// reservedNames renders reserved names as a quoted list
+func reservedNames(names protoreflect.Names) string {
+	parts := make([]string, 0, names.Len())
+	for i := 0; i < names.Len(); i++ {
+		parts = append(parts, strconv.Quote(string(names.Get(i))))
+	}
+	return strings.Join(parts, ", ")
+}
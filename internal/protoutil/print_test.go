@@ -0,0 +1,170 @@
+package protoutil
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const printTestProto = `// Package comment
+syntax = "proto2";
+
+package test.v1;
+
+import "google/api/annotations.proto";
+import "google/protobuf/descriptor.proto";
+
+option java_package = "com.example.test";
+option optimize_for = SPEED;
+
+// A shelf of books
+message Shelf {
+  option deprecated = true;
+
+  reserved 9, 20 to 25, 1000 to max;
+  reserved "nickname";
+
+  // The shelf name
+  required string name = 1;
+  repeated Book books = 2 [deprecated = true];
+  optional string theme = 3 [default = "plain \"old\"", json_name = "shelfTheme"]; // Shelf theme
+  map<string, Book> by_id = 4;
+  oneof location {
+    string room = 5;
+    int32 floor = 6;
+  }
+
+  extensions 100 to 199;
+
+  enum Kind {
+    option allow_alias = true;
+
+    KIND_UNSPECIFIED = 0;
+    KIND_WOOD = 1;
+    KIND_TIMBER = 1 [deprecated = true];
+  }
+
+  message Book {
+    optional Kind kind = 1 [default = KIND_WOOD];
+    optional double weight = 2 [default = inf];
+  }
+
+  extend Shelf {
+    optional string label = 100;
+  }
+}
+
+enum Color {
+  reserved 5, 10 to max;
+  reserved "COLOR_PINK";
+
+  COLOR_UNSPECIFIED = 0;
+}
+
+extend google.protobuf.MessageOptions {
+  optional string table = 50000;
+}
+
+message Empty {}
+
+// Library service
+service Library {
+  rpc GetShelf(Shelf) returns (Shelf) {
+    option (google.api.http) = {
+      get: "/v1/{name=shelves/*}"
+      additional_bindings {
+        get: "/v1/shelves/{name}"
+      }
+    };
+  }
+
+  // Streams shelves
+  rpc WatchShelves(stream Empty) returns (stream Shelf);
+}
+`
+
+func TestPrinter_Print_File(t *testing.T) {
+	file := compileSource(t, printTestProto)
+
+	printed := Printer{}.Print(file)
+	if printed != printTestProto {
+		t.Errorf("Expected printed file to match the canonical source.\nGot:\n%s\nExpected:\n%s", printed, printTestProto)
+	}
+
+	// The printed source compiles to the same descriptor
+	reprinted := compileSource(t, printed)
+	if marshalDescriptor(t, file) != marshalDescriptor(t, reprinted) {
+		t.Errorf("Expected printed source to compile to the same descriptor")
+	}
+}
+
+func TestPrinter_Print_Declarations(t *testing.T) {
+	file := compileSource(t, printTestProto)
+
+	service := Printer{OmitComments: true}.Print(file.Services().Get(0))
+	expectedService := `service Library {
+  rpc GetShelf(Shelf) returns (Shelf) {
+    option (google.api.http) = {
+      get: "/v1/{name=shelves/*}"
+      additional_bindings {
+        get: "/v1/shelves/{name}"
+      }
+    };
+  }
+
+  rpc WatchShelves(stream Empty) returns (stream Shelf);
+}
+`
+	if service != expectedService {
+		t.Errorf("Expected service:\n%s\ngot:\n%s", expectedService, service)
+	}
+
+	book := file.Messages().ByName("Shelf").Messages().ByName("Book")
+	expectedBook := `message Book {
+  optional Kind kind = 1 [default = KIND_WOOD];
+  optional double weight = 2 [default = inf];
+}
+`
+	if got := (Printer{}).Print(book); got != expectedBook {
+		t.Errorf("Expected nested message:\n%s\ngot:\n%s", expectedBook, got)
+	}
+
+	theme := file.Messages().ByName("Shelf").Fields().ByName("theme")
+	if got := (Printer{OmitComments: true}).Print(theme); got != "optional string theme = 3 [default = \"plain \\\"old\\\"\", json_name = \"shelfTheme\"];\n" {
+		t.Errorf("Unexpected field declaration: %s", got)
+	}
+}
+
+func TestPrinter_Print_Proto3(t *testing.T) {
+	source := `syntax = "proto3";
+
+package test.v1;
+
+message User {
+  string id = 1;
+  optional string nickname = 2;
+  repeated int64 scores = 3 [packed = false];
+}
+`
+	file := compileSource(t, source)
+	if printed := (Printer{}).Print(file); printed != source {
+		t.Errorf("Expected:\n%s\ngot:\n%s", source, printed)
+	}
+}
+
+// marshalDescriptor serializes a file without source info, for comparing
+// compiled results. Options are compared by their encoding since custom
+// options of separately compiled files use distinct extension types.
+func marshalDescriptor(t *testing.T, file protoreflect.FileDescriptor) string {
+	t.Helper()
+
+	fd := protodesc.ToFileDescriptorProto(file)
+	fd.SourceCodeInfo = nil
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(fd)
+	if err != nil {
+		t.Fatalf("Failed to marshal descriptor: %v", err)
+	}
+	return string(data)
+}
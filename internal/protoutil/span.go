@@ -0,0 +1,38 @@
+package protoutil
+
+import "google.golang.org/protobuf/reflect/protoreflect"
+
+// Span is the location of an element in a proto source file. Lines and
+// columns are 1-based; the end is exclusive.
+type Span struct {
+	File        string `json:"file"`
+	StartLine   int    `json:"start_line"`
+	StartColumn int    `json:"start_column"`
+	EndLine     int    `json:"end_line"`
+	EndColumn   int    `json:"end_column"`
+}
+
+// SpanOf returns the source span of a descriptor, or nil if its file was
+// compiled without source info for it
+func SpanOf(desc protoreflect.Descriptor) *Span {
+	file := desc.ParentFile()
+	if file == nil {
+		return nil
+	}
+	return SpanOfLocation(file, file.SourceLocations().ByDescriptor(desc))
+}
+
+// SpanOfLocation converts a 0-based source location of a file into a span,
+// or returns nil if the location has no path
+func SpanOfLocation(file protoreflect.FileDescriptor, loc protoreflect.SourceLocation) *Span {
+	if loc.Path == nil {
+		return nil
+	}
+	return &Span{
+		File:        file.Path(),
+		StartLine:   loc.StartLine + 1,
+		StartColumn: loc.StartColumn + 1,
+		EndLine:     loc.EndLine + 1,
+		EndColumn:   loc.EndColumn + 1,
+	}
+}
@@ -0,0 +1,28 @@
+package protoutil
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestSpanOf(t *testing.T) {
+	file := compileSource(t, `syntax = "proto3";
+package test.v1;
+
+message Book {
+	string title = 1;
+}
+`)
+
+	field := file.Messages().ByName("Book").Fields().ByName("title")
+	expected := &Span{File: "test.proto", StartLine: 5, StartColumn: 9, EndLine: 5, EndColumn: 26}
+	if span := SpanOf(field); !reflect.DeepEqual(span, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, span)
+	}
+
+	if span := SpanOfLocation(file, protoreflect.SourceLocation{}); span != nil {
+		t.Errorf("Expected nil for a location without a path, got %+v", span)
+	}
+}
@@ -0,0 +1,98 @@
+// Package snapshot saves compiled proto files as a FileDescriptorSet and
+// loads them back, so that a project can be compared with a saved baseline.
+package snapshot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bufbuild/protocompile/linker"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// DefaultPath is where snapshots are saved, relative to the project root,
+// unless configured otherwise
+const DefaultPath = ".protobuf-mcp-baseline.binpb"
+
+// Baseline returns the baseline to compare a project with when neither a
+// git ref nor a baseline is given: the configured one, or DefaultPath when a
+// snapshot has been saved there. It returns an empty string when there is
+// neither, in which case callers compare with HEAD.
+func Baseline(projectRoot, configured string) string {
+	if configured != "" {
+		return configured
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, DefaultPath)); err == nil {
+		return DefaultPath
+	}
+	return ""
+}
+
+// Build returns a FileDescriptorSet of the compiled files, including their
+// source info. Imported files that are not part of the compiled files are
+// left out.
+func Build(files linker.Files) *descriptorpb.FileDescriptorSet {
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range files {
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(file))
+	}
+	return set
+}
+
+// Write saves the compiled files to path as a binary FileDescriptorSet
+func Write(path string, files linker.Files) error {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(Build(files))
+	if err != nil {
+		return fmt.Errorf("failed to marshal descriptor set: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Read loads a binary FileDescriptorSet and returns its files. Imports that
+// are not part of the set, such as well-known types, are replaced by
+// placeholders, which keeps their full names for comparisons. Custom options
+// defined by files of the set are resolved.
+func Read(path string) ([]protoreflect.FileDescriptor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s as a FileDescriptorSet: %w", path, err)
+	}
+	registry, err := protodesc.FileOptions{AllowUnresolvable: true}.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set %s: %w", path, err)
+	}
+
+	// Options are first parsed without knowing the extensions declared in
+	// the set, so parse again now that they are known
+	set.Reset()
+	if err := (proto.UnmarshalOptions{Resolver: dynamicpb.NewTypes(registry)}).Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s as a FileDescriptorSet: %w", path, err)
+	}
+	registry, err = protodesc.FileOptions{AllowUnresolvable: true}.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set %s: %w", path, err)
+	}
+
+	files := make([]protoreflect.FileDescriptor, 0, len(set.File))
+	for _, fileProto := range set.File {
+		file, err := registry.FindFileByPath(fileProto.GetName())
+		if err != nil {
+			return nil, fmt.Errorf("invalid descriptor set %s: %w", path, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
@@ -0,0 +1,149 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/testutil"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	files := testutil.Compile(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test;
+
+import "google/protobuf/timestamp.proto";
+import "types.proto";
+
+message Book {
+  string name = 1;
+  google.protobuf.Timestamp created = 2;
+  Author author = 3;
+}
+`,
+		"types.proto": `syntax = "proto3";
+package test;
+
+message Author { string name = 1; }
+`,
+	})
+
+	path := filepath.Join(t.TempDir(), DefaultPath)
+	if err := Write(path, files); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	loaded, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	// Only the compiled files are saved, not their imports
+	if len(loaded) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(loaded))
+	}
+
+	var book protoreflect.MessageDescriptor
+	for _, file := range loaded {
+		if message := file.Messages().ByName("Book"); message != nil {
+			book = message
+		}
+	}
+	if book == nil {
+		t.Fatalf("Expected message Book in the snapshot")
+	}
+
+	if got := book.Fields().ByName("created").Message().FullName(); got != "google.protobuf.Timestamp" {
+		t.Errorf("Expected the unresolved import to keep its name, got %s", got)
+	}
+	if got := book.Fields().ByName("author").Message().Fields().Len(); got != 1 {
+		t.Errorf("Expected Author to be resolved from the snapshot, got %d fields", got)
+	}
+
+	loc := book.ParentFile().SourceLocations().ByDescriptor(book)
+	if loc.Path == nil || loc.StartLine != 6 {
+		t.Errorf("Expected source info to be kept, got %+v", loc)
+	}
+}
+
+func TestRead_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.binpb")
+	if err := os.WriteFile(path, []byte("not a descriptor set"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := Read(path); err == nil {
+		t.Errorf("Expected an error for an invalid descriptor set")
+	}
+
+	if _, err := Read(filepath.Join(t.TempDir(), "missing.binpb")); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+}
+
+func TestRead_CustomOptions(t *testing.T) {
+	files := testutil.Compile(t, map[string]string{
+		"options.proto": `syntax = "proto3";
+package test;
+
+import "google/protobuf/descriptor.proto";
+
+extend google.protobuf.MessageOptions {
+  string table = 50000;
+}
+`,
+		"api.proto": `syntax = "proto3";
+package test;
+
+import "options.proto";
+
+message Book {
+  option (test.table) = "books";
+}
+`,
+	})
+
+	path := filepath.Join(t.TempDir(), DefaultPath)
+	if err := Write(path, files); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	loaded, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	var options []string
+	for _, file := range loaded {
+		if book := file.Messages().ByName("Book"); book != nil {
+			book.Options().ProtoReflect().Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+				options = append(options, string(field.FullName())+"="+value.String())
+				return true
+			})
+		}
+	}
+	if len(options) != 1 || options[0] != "test.table=books" {
+		t.Errorf("Expected the custom option to be resolved, got %v", options)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	dir := t.TempDir()
+	if baseline := Baseline(dir, ""); baseline != "" {
+		t.Errorf("Expected no baseline without a snapshot, got %q", baseline)
+	}
+	if baseline := Baseline(dir, "build/baseline.binpb"); baseline != "build/baseline.binpb" {
+		t.Errorf("Expected the configured baseline, got %q", baseline)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, DefaultPath), nil, 0o644); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	if baseline := Baseline(dir, ""); baseline != DefaultPath {
+		t.Errorf("Expected the saved snapshot %s, got %q", DefaultPath, baseline)
+	}
+	if baseline := Baseline(dir, "build/baseline.binpb"); baseline != "build/baseline.binpb" {
+		t.Errorf("Expected the configured baseline to take precedence, got %q", baseline)
+	}
+}
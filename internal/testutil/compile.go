@@ -0,0 +1,48 @@
+// Package testutil provides helpers shared by the tests of several packages.
+package testutil
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/bufbuild/protocompile/linker"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Compile compiles proto sources keyed by path, with source info. Sources
+// may import the well-known types. Files are compiled in path order and the
+// test fails if any of them does not compile.
+func Compile(t testing.TB, sources map[string]string) linker.Files {
+	t.Helper()
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(sources),
+		}),
+		SourceInfoMode: protocompile.SourceInfoStandard,
+	}
+	paths := make([]string, 0, len(sources))
+	for path := range sources {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	files, err := compiler.Compile(context.Background(), paths...)
+	if err != nil {
+		t.Fatalf("Failed to compile: %v", err)
+	}
+	return files
+}
+
+// CompileDescriptors is like Compile but returns the files as descriptors
+func CompileDescriptors(t testing.TB, sources map[string]string) []protoreflect.FileDescriptor {
+	t.Helper()
+
+	files := Compile(t, sources)
+	result := make([]protoreflect.FileDescriptor, 0, len(files))
+	for _, file := range files {
+		result = append(result, file)
+	}
+	return result
+}
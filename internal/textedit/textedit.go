@@ -0,0 +1,159 @@
+// Package textedit applies text edits computed from proto source info to
+// source files.
+package textedit
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"unicode/utf8"
+)
+
+// tabWidth is the tab stop width used by the proto compiler when it counts
+// columns
+const tabWidth = 8
+
+// Edit replaces a range of a proto source file with new text. Lines and
+// columns are 1-based and counted like in compiler source info: columns
+// count characters, with tabs advancing to the next multiple of 8. The end
+// is exclusive and an empty range inserts text. OldText is the text the
+// range is expected to hold; an edit is refused if the file changed.
+type Edit struct {
+	File        string `json:"file"`
+	StartLine   int    `json:"start_line"`
+	StartColumn int    `json:"start_column"`
+	EndLine     int    `json:"end_line"`
+	EndColumn   int    `json:"end_column"`
+	OldText     string `json:"old_text,omitempty"`
+	NewText     string `json:"new_text"`
+}
+
+// Apply applies edits to the content of one file. Edits must not overlap;
+// insertions at the same position are applied in the given order.
+func Apply(content []byte, edits []Edit) ([]byte, error) {
+	type span struct {
+		start, end int
+		edit       Edit
+	}
+
+	spans := make([]span, 0, len(edits))
+	for _, edit := range edits {
+		start, err := Offset(content, edit.StartLine, edit.StartColumn)
+		if err != nil {
+			return nil, err
+		}
+		end, err := Offset(content, edit.EndLine, edit.EndColumn)
+		if err != nil {
+			return nil, err
+		}
+		if end < start {
+			return nil, fmt.Errorf("%s:%d:%d: edit ends before it starts", edit.File, edit.StartLine, edit.StartColumn)
+		}
+		if old := string(content[start:end]); old != edit.OldText {
+			return nil, fmt.Errorf("%s:%d:%d: expected %q, found %q; the file changed since it was compiled", edit.File, edit.StartLine, edit.StartColumn, edit.OldText, old)
+		}
+		spans = append(spans, span{start: start, end: end, edit: edit})
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end < spans[j].end
+	})
+	for i := 1; i < len(spans); i++ {
+		if spans[i].start < spans[i-1].end {
+			edit := spans[i].edit
+			return nil, fmt.Errorf("%s:%d:%d: overlapping edits", edit.File, edit.StartLine, edit.StartColumn)
+		}
+	}
+
+	var result bytes.Buffer
+	previous := 0
+	for _, s := range spans {
+		result.Write(content[previous:s.start])
+		result.WriteString(s.edit.NewText)
+		previous = s.end
+	}
+	result.Write(content[previous:])
+	return result.Bytes(), nil
+}
+
+// Offset converts a 1-based line and column to a byte offset in content. The
+// column may point just past the end of the line.
+func Offset(content []byte, line, column int) (int, error) {
+	if line < 1 || column < 1 {
+		return 0, fmt.Errorf("invalid position %d:%d", line, column)
+	}
+
+	offset := 0
+	for current := 1; current < line; current++ {
+		next := bytes.IndexByte(content[offset:], '\n')
+		if next < 0 {
+			return 0, fmt.Errorf("line %d is past the end of the file", line)
+		}
+		offset += next + 1
+	}
+
+	col := 1
+	for col < column {
+		if offset >= len(content) || content[offset] == '\n' {
+			return 0, fmt.Errorf("column %d is past the end of line %d", column, line)
+		}
+		r, size := utf8.DecodeRune(content[offset:])
+		if r == '\t' {
+			col += tabWidth - (col-1)%tabWidth
+		} else {
+			col++
+		}
+		offset += size
+	}
+	if col != column {
+		return 0, fmt.Errorf("column %d of line %d is inside a tab", column, line)
+	}
+	return offset, nil
+}
+
+// ApplyFiles applies edits to files on disk. resolve maps the file of an
+// edit to its path on disk. Every file is edited in memory first, so nothing
+// is written unless all edits apply. Returns the edited files, in the order
+// they first appear in edits.
+func ApplyFiles(edits []Edit, resolve func(file string) (string, error)) ([]string, error) {
+	var files []string
+	byFile := make(map[string][]Edit)
+	for _, edit := range edits {
+		if _, ok := byFile[edit.File]; !ok {
+			files = append(files, edit.File)
+		}
+		byFile[edit.File] = append(byFile[edit.File], edit)
+	}
+
+	paths := make(map[string]string, len(files))
+	contents := make(map[string][]byte, len(files))
+	for _, file := range files {
+		path, err := resolve(file)
+		if err != nil {
+			return nil, err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if contents[file], err = Apply(content, byFile[file]); err != nil {
+			return nil, err
+		}
+		paths[file] = path
+	}
+
+	for _, file := range files {
+		info, err := os.Stat(paths[file])
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		if err := os.WriteFile(paths[file], contents[file], info.Mode().Perm()); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,126 @@
+package textedit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	content := []byte("message foo_bar {\n\tstring Title = 1;\n}\n")
+
+	result, err := Apply(content, []Edit{
+		{StartLine: 2, StartColumn: 16, EndLine: 2, EndColumn: 21, OldText: "Title", NewText: "title"},
+		{StartLine: 1, StartColumn: 9, EndLine: 1, EndColumn: 16, OldText: "foo_bar", NewText: "FooBar"},
+		{StartLine: 1, StartColumn: 1, EndLine: 1, EndColumn: 1, NewText: "// A message\n"},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := "// A message\nmessage FooBar {\n\tstring title = 1;\n}\n"
+	if string(result) != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	content := []byte("message Foo {}\n")
+
+	tests := []struct {
+		name  string
+		edits []Edit
+	}{
+		{
+			name:  "changed text",
+			edits: []Edit{{StartLine: 1, StartColumn: 9, EndLine: 1, EndColumn: 12, OldText: "Bar", NewText: "Baz"}},
+		},
+		{
+			name: "overlapping",
+			edits: []Edit{
+				{StartLine: 1, StartColumn: 1, EndLine: 1, EndColumn: 12, OldText: "message Foo", NewText: ""},
+				{StartLine: 1, StartColumn: 9, EndLine: 1, EndColumn: 12, OldText: "Foo", NewText: "Bar"},
+			},
+		},
+		{
+			name:  "past the end",
+			edits: []Edit{{StartLine: 3, StartColumn: 1, EndLine: 3, EndColumn: 1, NewText: "x"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Apply(content, tt.edits); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func TestOffset(t *testing.T) {
+	content := []byte("ab\n\tx é y\n")
+
+	tests := []struct {
+		line, column, expected int
+	}{
+		{1, 1, 0},
+		{1, 3, 2},
+		{2, 1, 3},
+		{2, 9, 4},  // after the tab
+		{2, 11, 6}, // after "x "
+		{2, 13, 9}, // after "é ", which is two bytes
+	}
+	for _, tt := range tests {
+		offset, err := Offset(content, tt.line, tt.column)
+		if err != nil {
+			t.Errorf("Offset(%d, %d) failed: %v", tt.line, tt.column, err)
+			continue
+		}
+		if offset != tt.expected {
+			t.Errorf("Offset(%d, %d) = %d, want %d", tt.line, tt.column, offset, tt.expected)
+		}
+	}
+
+	if _, err := Offset(content, 2, 4); err == nil {
+		t.Errorf("Expected an error for a column inside a tab")
+	}
+}
+
+func TestApplyFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.proto")
+	b := filepath.Join(dir, "b.proto")
+	if err := os.WriteFile(a, []byte("message a {}\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(b, []byte("message b {}\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	resolve := func(file string) (string, error) { return filepath.Join(dir, file), nil }
+
+	// A failing edit in b.proto leaves a.proto untouched
+	_, err := ApplyFiles([]Edit{
+		{File: "a.proto", StartLine: 1, StartColumn: 9, EndLine: 1, EndColumn: 10, OldText: "a", NewText: "A"},
+		{File: "b.proto", StartLine: 1, StartColumn: 9, EndLine: 1, EndColumn: 10, OldText: "x", NewText: "B"},
+	}, resolve)
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if data, _ := os.ReadFile(a); string(data) != "message a {}\n" {
+		t.Errorf("Expected a.proto to be unchanged, got %q", data)
+	}
+
+	files, err := ApplyFiles([]Edit{
+		{File: "b.proto", StartLine: 1, StartColumn: 9, EndLine: 1, EndColumn: 10, OldText: "b", NewText: "B"},
+		{File: "a.proto", StartLine: 1, StartColumn: 9, EndLine: 1, EndColumn: 10, OldText: "a", NewText: "A"},
+	}, resolve)
+	if err != nil {
+		t.Fatalf("ApplyFiles failed: %v", err)
+	}
+	if len(files) != 2 || files[0] != "b.proto" || files[1] != "a.proto" {
+		t.Errorf("Expected [b.proto a.proto], got %v", files)
+	}
+	if data, _ := os.ReadFile(a); string(data) != "message A {}\n" {
+		t.Errorf("Expected a.proto to be edited, got %q", data)
+	}
+}
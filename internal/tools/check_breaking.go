@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bufbuild/protocompile/linker"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/breaking"
+	"github.com/yuemori/protobuf-mcp-server/internal/gitrepo"
+	"github.com/yuemori/protobuf-mcp-server/internal/snapshot"
+)
+
+// CheckBreakingTool implements the check_breaking MCP tool using mcp-go
+type CheckBreakingTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewCheckBreakingTool creates a new CheckBreakingTool instance
+func NewCheckBreakingTool(projectManager ProjectManagerInterface) *CheckBreakingTool {
+	return &CheckBreakingTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *CheckBreakingTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"check_breaking",
+		mcp.WithDescription("Compare the proto files of the activated project with a previous version and report breaking changes such as deleted fields, changed field numbers or types, deleted or renamed RPCs, changed streaming, deleted enum values and package moves, with source locations. The previous version is either a git ref, read from the local repository, or a FileDescriptorSet baseline saved by 'protobuf-mcp snapshot'"),
+		mcp.WithString("against",
+			mcp.Description("Git ref to compare against (branch, tag or commit); defaults to HEAD unless a baseline is configured or saved"),
+		),
+		mcp.WithString("baseline",
+			mcp.Description("Path of a FileDescriptorSet baseline to compare against, relative to the project root; defaults to breaking.baseline of .protobuf-mcp.yml, or '"+snapshot.DefaultPath+"' when it exists, if no git ref is given"),
+		),
+		mcp.WithString("categories",
+			mcp.Description("Comma-separated rule categories to report, from strictest to most lenient: FILE, PACKAGE, WIRE_JSON, WIRE (defaults to breaking.categories of .protobuf-mcp.yml, or FILE)"),
+		),
+		mcp.WithBoolean("wire_only",
+			mcp.Description("Only report changes that break the binary encoding; same as categories=WIRE"),
+		),
+	)
+}
+
+// CheckBreakingResponse represents the response from check_breaking tool
+type CheckBreakingResponse struct {
+	Success    bool                 `json:"success"`
+	Message    string               `json:"message"`
+	Against    string               `json:"against,omitempty"`
+	Baseline   string               `json:"baseline,omitempty"`
+	Categories []string             `json:"categories,omitempty"`
+	Breaking   bool                 `json:"breaking"`
+	Violations []breaking.Violation `json:"violations,omitempty"`
+	Count      int                  `json:"count"`
+}
+
+// Handle handles the tool execution
+func (t *CheckBreakingTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	against := req.GetString("against", "")
+	baseline := req.GetString("baseline", "")
+	if against != "" && baseline != "" {
+		return mcp.NewToolResultError("against and baseline cannot be used together"), nil
+	}
+	categoriesParam := req.GetString("categories", "")
+	if req.GetBool("wire_only", false) {
+		categoriesParam = breaking.CategoryWire
+	}
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &CheckBreakingResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	categoryNames := project.Config.Breaking.Categories
+	if categoriesParam != "" {
+		categoryNames = strings.Split(categoriesParam, ",")
+	}
+	categories, err := breaking.ParseCategories(categoryNames)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if against == "" && baseline == "" {
+		baseline = snapshot.Baseline(project.ProjectRoot, project.Config.Breaking.Baseline)
+		if baseline == "" {
+			against = "HEAD"
+		}
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &CheckBreakingResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	var previous []protoreflect.FileDescriptor
+	target := against
+	if baseline != "" {
+		target = baseline
+		baselinePath := baseline
+		if !filepath.IsAbs(baselinePath) {
+			baselinePath = filepath.Join(project.ProjectRoot, baselinePath)
+		}
+		previous, err = snapshot.Read(baselinePath)
+	} else {
+		var previousFiles linker.Files
+		previousFiles, err = gitrepo.CompileProtos(ctx, project.ProjectRoot, project.Config, against)
+		previous = breaking.FileDescriptors(previousFiles)
+	}
+	if err != nil {
+		response := &CheckBreakingResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to check against %s: %v", target, err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	violations := breaking.Filter(breaking.Check(previous, breaking.FileDescriptors(files)), categories)
+
+	message := fmt.Sprintf("No breaking changes against %s", target)
+	if len(violations) > 0 {
+		message = fmt.Sprintf("Found %d breaking changes against %s", len(violations), target)
+	}
+
+	response := &CheckBreakingResponse{
+		Success:    true,
+		Message:    message,
+		Against:    against,
+		Baseline:   baseline,
+		Categories: categories,
+		Breaking:   len(violations) > 0,
+		Violations: violations,
+		Count:      len(violations),
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
@@ -0,0 +1,362 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/snapshot"
+)
+
+// commitAll commits every file of dir into a new git repository, skipping the
+// test when git is not available
+func commitAll(t *testing.T, dir string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+}
+
+func TestCheckBreakingTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewCheckBreakingTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "check_breaking" {
+		t.Fatalf("Expected tool name 'check_breaking', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestCheckBreakingTool_Handle(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"proto/api.proto": `syntax = "proto3";
+package test.v1;
+
+message Book {
+  string name = 1;
+  string title = 2;
+}
+
+service Library {
+  rpc GetBook(Book) returns (Book);
+}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+	commitAll(t, project.ProjectRoot)
+
+	// Delete a field and make the RPC server streaming in the working tree
+	err = os.WriteFile(filepath.Join(project.ProjectRoot, "proto", "api.proto"), []byte(`syntax = "proto3";
+package test.v1;
+
+message Book {
+  string name = 1;
+  reserved 2;
+}
+
+service Library {
+  rpc GetBook(Book) returns (stream Book);
+}
+`), 0o644)
+	if err != nil {
+		t.Fatalf("Failed to modify proto file: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewCheckBreakingTool(mockProjectManager)
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		expected  []string
+	}{
+		{
+			name:      "all changes",
+			arguments: map[string]interface{}{},
+			expected:  []string{"FIELD_NO_DELETE", "RPC_SAME_SERVER_STREAMING"},
+		},
+		{
+			name:      "wire only",
+			arguments: map[string]interface{}{"against": "HEAD", "wire_only": true},
+			expected:  []string{"RPC_SAME_SERVER_STREAMING"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name:      "check_breaking",
+					Arguments: tt.arguments,
+				},
+			}
+
+			result, err := tool.Handle(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Handle failed: %v", err)
+			}
+
+			var response CheckBreakingResponse
+			if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+				if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+			} else {
+				t.Fatalf("Expected text content in response")
+			}
+
+			if !response.Success {
+				t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+			}
+			if !response.Breaking || response.Count != len(tt.expected) {
+				t.Fatalf("Expected %d violations, got %+v", len(tt.expected), response.Violations)
+			}
+			for i, rule := range tt.expected {
+				if response.Violations[i].Rule != rule {
+					t.Errorf("Expected violation %d to be %s, got %+v", i, rule, response.Violations[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckBreakingTool_Baseline(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test.v1;
+
+message Book {
+  string name = 1;
+  string title = 2;
+}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	files, err := project.CompileProtos(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to compile project: %v", err)
+	}
+	// The default path is used when nothing else is given, even outside git
+	for _, path := range []string{"baseline.binpb", snapshot.DefaultPath} {
+		if err := snapshot.Write(filepath.Join(project.ProjectRoot, path), files); err != nil {
+			t.Fatalf("Failed to write snapshot: %v", err)
+		}
+	}
+
+	// Rename a field, which only breaks JSON clients
+	err = os.WriteFile(filepath.Join(project.ProjectRoot, "api.proto"), []byte(`syntax = "proto3";
+package test.v1;
+
+message Book {
+  string name = 1;
+  string book_title = 2;
+}
+`), 0o644)
+	if err != nil {
+		t.Fatalf("Failed to modify proto file: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewCheckBreakingTool(mockProjectManager)
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		expected  int
+	}{
+		{
+			name:      "default categories",
+			arguments: map[string]interface{}{"baseline": "baseline.binpb"},
+			expected:  1,
+		},
+		{
+			name:      "wire json",
+			arguments: map[string]interface{}{"baseline": "baseline.binpb", "categories": "WIRE_JSON"},
+			expected:  1,
+		},
+		{
+			name:      "wire",
+			arguments: map[string]interface{}{"baseline": "baseline.binpb", "categories": "WIRE"},
+			expected:  0,
+		},
+		{
+			name:      "saved snapshot",
+			arguments: map[string]interface{}{},
+			expected:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name:      "check_breaking",
+					Arguments: tt.arguments,
+				},
+			}
+
+			result, err := tool.Handle(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Handle failed: %v", err)
+			}
+
+			var response CheckBreakingResponse
+			if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+				if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+			} else {
+				t.Fatalf("Expected text content in response")
+			}
+
+			if !response.Success {
+				t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+			}
+			if response.Against != "" || response.Baseline == "" {
+				t.Errorf("Expected a baseline comparison, got against=%q baseline=%q", response.Against, response.Baseline)
+			}
+			if response.Count != tt.expected {
+				t.Fatalf("Expected %d violations, got %+v", tt.expected, response.Violations)
+			}
+			if tt.expected == 0 {
+				return
+			}
+
+			violation := response.Violations[0]
+			if violation.Rule != "FIELD_SAME_NAME" || violation.Category != "WIRE_JSON" {
+				t.Errorf("Expected FIELD_SAME_NAME in WIRE_JSON, got %+v", violation)
+			}
+			if violation.Location == nil || violation.Location.StartLine != 6 {
+				t.Errorf("Expected location at line 6, got %+v", violation.Location)
+			}
+		})
+	}
+}
+
+func TestCheckBreakingTool_InvalidParameters(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewCheckBreakingTool(mockProjectManager)
+
+	for _, arguments := range []map[string]interface{}{
+		{"against": "HEAD", "baseline": "baseline.binpb"},
+		{"categories": "SOURCE"},
+	} {
+		req := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "check_breaking",
+				Arguments: arguments,
+			},
+		}
+
+		result, err := tool.Handle(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("Expected a tool error for %v", arguments)
+		}
+	}
+}
+
+func TestCheckBreakingTool_UnknownRef(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test;
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+	commitAll(t, project.ProjectRoot)
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewCheckBreakingTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "check_breaking",
+			Arguments: map[string]interface{}{"against": "no-such-branch"},
+		},
+	}
+
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	var response CheckBreakingResponse
+	if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+		if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+	} else {
+		t.Fatalf("Expected text content in response")
+	}
+
+	if response.Success {
+		t.Fatalf("Expected success=false for an unknown ref")
+	}
+}
+
+func TestCheckBreakingTool_NoProject(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewCheckBreakingTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "check_breaking",
+			Arguments: map[string]interface{}{},
+		},
+	}
+
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	var response CheckBreakingResponse
+	if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+		if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+	} else {
+		t.Fatalf("Expected text content in response")
+	}
+
+	if response.Success {
+		t.Fatalf("Expected success=false when no project is activated")
+	}
+}
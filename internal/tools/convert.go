@@ -0,0 +1,367 @@
+package tools
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// convertOptions controls how descriptors are converted to DTOs
+type convertOptions struct {
+	// IncludeDetachedComments adds leading detached comments to descriptions
+	IncludeDetachedComments bool
+}
+
+// newFileInfo converts a protobuf file to FileInfo
+func newFileInfo(file protoreflect.FileDescriptor) FileInfo {
+	imports := make([]ImportInfo, 0, file.Imports().Len())
+	for i := 0; i < file.Imports().Len(); i++ {
+		imp := file.Imports().Get(i)
+		imports = append(imports, ImportInfo{
+			Path:   imp.Path(),
+			Public: imp.IsPublic,
+			Weak:   imp.IsWeak,
+		})
+	}
+
+	fileInfo := FileInfo{
+		Path:           file.Path(),
+		Package:        string(file.Package()),
+		Syntax:         file.Syntax().String(),
+		Imports:        imports,
+		Options:        optionsOf(file),
+		MessageCount:   file.Messages().Len(),
+		EnumCount:      file.Enums().Len(),
+		ServiceCount:   file.Services().Len(),
+		ExtensionCount: file.Extensions().Len(),
+	}
+
+	if file.Syntax() == protoreflect.Editions {
+		fileInfo.Edition = protodesc.ToFileDescriptorProto(file).GetEdition().String()
+	}
+
+	return fileInfo
+}
+
+// newServiceInfo converts a protobuf service to ServiceInfo
+func newServiceInfo(service protoreflect.ServiceDescriptor, opts convertOptions) ServiceInfo {
+	methods := make([]MethodInfo, 0, service.Methods().Len())
+	for i := 0; i < service.Methods().Len(); i++ {
+		methods = append(methods, newMethodInfo(service.Methods().Get(i), opts))
+	}
+
+	return ServiceInfo{
+		Name:        string(service.Name()),
+		FullName:    string(service.FullName()),
+		Methods:     methods,
+		File:        service.ParentFile().Path(),
+		Package:     string(service.ParentFile().Package()),
+		Description: descriptionOf(service, opts.IncludeDetachedComments),
+		Options:     optionsOf(service),
+		Location:    protoutil.SpanOf(service),
+	}
+}
+
+// newMethodInfo converts a protobuf method to MethodInfo
+func newMethodInfo(method protoreflect.MethodDescriptor, opts convertOptions) MethodInfo {
+	return MethodInfo{
+		Name:            string(method.Name()),
+		FullName:        string(method.FullName()),
+		InputType:       string(method.Input().FullName()),
+		OutputType:      string(method.Output().FullName()),
+		ClientStreaming: method.IsStreamingClient(),
+		ServerStreaming: method.IsStreamingServer(),
+		Description:     descriptionOf(method, opts.IncludeDetachedComments),
+		Options:         optionsOf(method),
+		HTTPBindings:    protoutil.HTTPBindings(method),
+		Location:        protoutil.SpanOf(method),
+	}
+}
+
+// newMessageInfo converts a protobuf message, including its nested messages
+// and enums, to MessageInfo
+func newMessageInfo(message protoreflect.MessageDescriptor, opts convertOptions) MessageInfo {
+	fields := make([]FieldInfo, 0, message.Fields().Len())
+	for i := 0; i < message.Fields().Len(); i++ {
+		fields = append(fields, newFieldInfo(message.Fields().Get(i), opts))
+	}
+
+	var nestedMessages []MessageInfo
+	for i := 0; i < message.Messages().Len(); i++ {
+		nested := message.Messages().Get(i)
+		// Map entries are synthesized by the compiler and described by the map field itself
+		if nested.IsMapEntry() {
+			continue
+		}
+		nestedMessages = append(nestedMessages, newMessageInfo(nested, opts))
+	}
+
+	var nestedEnums []EnumInfo
+	for i := 0; i < message.Enums().Len(); i++ {
+		nestedEnums = append(nestedEnums, newEnumInfo(message.Enums().Get(i), opts))
+	}
+
+	return MessageInfo{
+		Name:                string(message.Name()),
+		FullName:            string(message.FullName()),
+		Fields:              fields,
+		NestedMessages:      nestedMessages,
+		NestedEnums:         nestedEnums,
+		File:                message.ParentFile().Path(),
+		Package:             string(message.ParentFile().Package()),
+		Description:         descriptionOf(message, opts.IncludeDetachedComments),
+		Options:             optionsOf(message),
+		ReservedRanges:      convertFieldRanges(message.ReservedRanges()),
+		ReservedNames:       convertNames(message.ReservedNames()),
+		ExtensionRanges:     convertFieldRanges(message.ExtensionRanges()),
+		NextFreeFieldNumber: int32(nextFreeFieldNumber(message)),
+		Location:            protoutil.SpanOf(message),
+	}
+}
+
+// newFieldInfo converts a protobuf field to FieldInfo
+func newFieldInfo(field protoreflect.FieldDescriptor, opts convertOptions) FieldInfo {
+	fieldInfo := FieldInfo{
+		Name:        string(field.Name()),
+		JSONName:    field.JSONName(),
+		Number:      int32(field.Number()),
+		Type:        field.Kind().String(),
+		TypeName:    typeNameOf(field),
+		Optional:    field.HasPresence(),
+		Repeated:    field.Cardinality() == protoreflect.Repeated,
+		Description: descriptionOf(field, opts.IncludeDetachedComments),
+		Options:     optionsOf(field),
+		Location:    protoutil.SpanOf(field),
+	}
+
+	if field.IsMap() {
+		fieldInfo.Type = "map"
+		fieldInfo.MapKeyType = field.MapKey().Kind().String()
+		fieldInfo.MapValueType = field.MapValue().Kind().String()
+		fieldInfo.TypeName = typeNameOf(field.MapValue())
+	}
+
+	if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+		fieldInfo.Oneof = string(oneof.Name())
+	}
+
+	return fieldInfo
+}
+
+// newEnumInfo converts a protobuf enum to EnumInfo
+func newEnumInfo(enum protoreflect.EnumDescriptor, opts convertOptions) EnumInfo {
+	values := make([]EnumValueInfo, 0, enum.Values().Len())
+	for i := 0; i < enum.Values().Len(); i++ {
+		values = append(values, newEnumValueInfo(enum.Values().Get(i), opts))
+	}
+
+	return EnumInfo{
+		Name:           string(enum.Name()),
+		FullName:       string(enum.FullName()),
+		Values:         values,
+		File:           enum.ParentFile().Path(),
+		Package:        string(enum.ParentFile().Package()),
+		Description:    descriptionOf(enum, opts.IncludeDetachedComments),
+		Options:        optionsOf(enum),
+		ReservedRanges: convertEnumRanges(enum.ReservedRanges()),
+		ReservedNames:  convertNames(enum.ReservedNames()),
+		Location:       protoutil.SpanOf(enum),
+	}
+}
+
+// newEnumValueInfo converts a protobuf enum value to EnumValueInfo
+func newEnumValueInfo(value protoreflect.EnumValueDescriptor, opts convertOptions) EnumValueInfo {
+	return EnumValueInfo{
+		Name:        string(value.Name()),
+		Number:      int32(value.Number()),
+		Description: descriptionOf(value, opts.IncludeDetachedComments),
+		Options:     optionsOf(value),
+		Location:    protoutil.SpanOf(value),
+	}
+}
+
+// optionsOf returns the options set on a descriptor, sorted by name. Standard
+// options use their short name (go_package), custom options their full
+// extension name (google.api.http).
+func optionsOf(desc protoreflect.Descriptor) []OptionInfo {
+	options := desc.Options()
+	if options == nil {
+		return nil
+	}
+
+	var result []OptionInfo
+	options.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		name := string(field.Name())
+		if field.IsExtension() {
+			name = string(field.FullName())
+		}
+		result = append(result, OptionInfo{Name: name, Value: optionValue(field, value)})
+		return true
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// optionValue converts an option value to a JSON-friendly value. Enums are
+// represented by their value name and messages by their protojson form.
+func optionValue(field protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
+	if field.IsList() {
+		list := value.List()
+		values := make([]interface{}, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			values = append(values, singularOptionValue(field, list.Get(i)))
+		}
+		return values
+	}
+	return singularOptionValue(field, value)
+}
+
+// singularOptionValue converts a single (non-list) option value
+func singularOptionValue(field protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
+	switch field.Kind() {
+	case protoreflect.EnumKind:
+		if enumValue := field.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return int32(value.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(value.Message().Interface())
+		if err != nil {
+			return nil
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return string(data)
+		}
+		return decoded
+	default:
+		return value.Interface()
+	}
+}
+
+// formatOptionValue renders a converted option value as text: strings as
+// is and everything else as JSON
+func formatOptionValue(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+// typeNameOf returns the full name of the message or enum a field refers to,
+// or an empty string for scalar fields
+func typeNameOf(field protoreflect.FieldDescriptor) string {
+	switch {
+	case field.Message() != nil:
+		return string(field.Message().FullName())
+	case field.Enum() != nil:
+		return string(field.Enum().FullName())
+	default:
+		return ""
+	}
+}
+
+// convertFieldRanges converts field number ranges to RangeInfo.
+// protoreflect field ranges are end-exclusive; RangeInfo is inclusive.
+func convertFieldRanges(ranges protoreflect.FieldRanges) []RangeInfo {
+	if ranges.Len() == 0 {
+		return nil
+	}
+	result := make([]RangeInfo, 0, ranges.Len())
+	for i := 0; i < ranges.Len(); i++ {
+		r := ranges.Get(i)
+		result = append(result, RangeInfo{Start: int32(r[0]), End: int32(r[1] - 1)})
+	}
+	return result
+}
+
+// convertEnumRanges converts enum reserved ranges to RangeInfo
+func convertEnumRanges(ranges protoreflect.EnumRanges) []RangeInfo {
+	if ranges.Len() == 0 {
+		return nil
+	}
+	result := make([]RangeInfo, 0, ranges.Len())
+	for i := 0; i < ranges.Len(); i++ {
+		r := ranges.Get(i)
+		result = append(result, RangeInfo{Start: int32(r[0]), End: int32(r[1])})
+	}
+	return result
+}
+
+// convertNames converts a list of reserved names to strings
+func convertNames(names protoreflect.Names) []string {
+	if names.Len() == 0 {
+		return nil
+	}
+	result := make([]string, 0, names.Len())
+	for i := 0; i < names.Len(); i++ {
+		result = append(result, string(names.Get(i)))
+	}
+	return result
+}
+
+// nextFreeFieldNumber returns the number a new field should use: one past the
+// highest number used by a field or reserved range, skipping extension ranges
+// and the implementation-reserved range. Gaps are deliberately not reused since
+// they usually belong to deleted fields that were never reserved. If the
+// numbering space is exhausted, the lowest unused number is returned instead,
+// or 0 if there is none.
+func nextFreeFieldNumber(message protoreflect.MessageDescriptor) protoreflect.FieldNumber {
+	highest := protoreflect.FieldNumber(0)
+	for i := 0; i < message.Fields().Len(); i++ {
+		if n := message.Fields().Get(i).Number(); n > highest {
+			highest = n
+		}
+	}
+	for i := 0; i < message.ReservedRanges().Len(); i++ {
+		if end := message.ReservedRanges().Get(i)[1] - 1; end > highest {
+			highest = end
+		}
+	}
+
+	if n := skipUnavailableFieldNumbers(message, highest+1); n != 0 {
+		return n
+	}
+	return skipUnavailableFieldNumbers(message, protowire.MinValidNumber)
+}
+
+// skipUnavailableFieldNumbers returns the first number at or after n that is
+// not used by a field, reserved range, extension range or the
+// implementation-reserved range
+func skipUnavailableFieldNumbers(message protoreflect.MessageDescriptor, n protoreflect.FieldNumber) protoreflect.FieldNumber {
+	for n <= protowire.MaxValidNumber {
+		switch {
+		case n >= protowire.FirstReservedNumber && n <= protowire.LastReservedNumber:
+			n = protowire.LastReservedNumber + 1
+		case message.ReservedRanges().Has(n):
+			n = rangeEnd(message.ReservedRanges(), n)
+		case message.ExtensionRanges().Has(n):
+			n = rangeEnd(message.ExtensionRanges(), n)
+		case message.Fields().ByNumber(n) != nil:
+			n++
+		default:
+			return n
+		}
+	}
+	return 0
+}
+
+// rangeEnd returns the exclusive end of the range in ranges containing n
+func rangeEnd(ranges protoreflect.FieldRanges, n protoreflect.FieldNumber) protoreflect.FieldNumber {
+	for i := 0; i < ranges.Len(); i++ {
+		if r := ranges.Get(i); n >= r[0] && n < r[1] {
+			return r[1]
+		}
+	}
+	return n + 1
+}
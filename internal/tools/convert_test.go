@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata/golden")
+
+func TestConvert_Golden(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	// Resolve the golden path before compiling, which changes the working directory
+	goldenPath, err := filepath.Abs(filepath.Join("testdata", "golden", "schema.json"))
+	if err != nil {
+		t.Fatalf("Failed to resolve golden path: %v", err)
+	}
+
+	files, err := project.CompileProtos(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to compile protos: %v", err)
+	}
+
+	schema := SchemaInfo{
+		Messages: []MessageInfo{},
+		Services: []ServiceInfo{},
+		Enums:    []EnumInfo{},
+	}
+	for _, file := range files {
+		for i := 0; i < file.Messages().Len(); i++ {
+			schema.Messages = append(schema.Messages, newMessageInfo(file.Messages().Get(i), convertOptions{}))
+		}
+		for i := 0; i < file.Services().Len(); i++ {
+			schema.Services = append(schema.Services, newServiceInfo(file.Services().Get(i), convertOptions{}))
+		}
+		for i := 0; i < file.Enums().Len(); i++ {
+			schema.Enums = append(schema.Enums, newEnumInfo(file.Enums().Get(i), convertOptions{}))
+		}
+	}
+
+	assertGolden(t, goldenPath, schema)
+}
+
+// assertGolden compares the indented JSON encoding of got with the golden file
+// at path, rewriting the file instead when -update is set
+func assertGolden(t *testing.T, path string, got interface{}) {
+	t.Helper()
+
+	gotJSON, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal result: %v", err)
+	}
+	gotJSON = append(gotJSON, '\n')
+
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, gotJSON, 0o644); err != nil {
+			t.Fatalf("Failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	wantJSON, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+	}
+
+	if string(gotJSON) != string(wantJSON) {
+		t.Fatalf("Output does not match golden file %s (run with -update to regenerate)\ngot:\n%s", path, gotJSON)
+	}
+}
@@ -0,0 +1,256 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+)
+
+// Symbol kinds reported by definition_at in addition to the reference kinds
+// of find_references and the declaration kinds of descriptorKind
+const (
+	symbolKindImport  = "import"
+	symbolKindPackage = "package"
+	symbolKindOption  = "option"
+)
+
+// DefinitionAtTool implements the definition_at MCP tool using mcp-go
+type DefinitionAtTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewDefinitionAtTool creates a new DefinitionAtTool instance
+func NewDefinitionAtTool(projectManager ProjectManagerInterface) *DefinitionAtTool {
+	return &DefinitionAtTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *DefinitionAtTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"definition_at",
+		mcp.WithDescription("Find the symbol at a position in a proto file and where it is defined, including definitions in imported files"),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Proto file path as shown by list_files (e.g. 'api.proto')"),
+		),
+		mcp.WithNumber("line",
+			mcp.Required(),
+			mcp.Description("1-based line number"),
+		),
+		mcp.WithNumber("column",
+			mcp.Required(),
+			mcp.Description("1-based column number"),
+		),
+	)
+}
+
+// DefinitionAtResponse represents the response from definition_at tool
+type DefinitionAtResponse struct {
+	Success    bool            `json:"success"`
+	Message    string          `json:"message"`
+	Symbol     *SymbolInfo     `json:"symbol,omitempty"`
+	Definition *DefinitionInfo `json:"definition,omitempty"`
+}
+
+// SymbolInfo represents the symbol found at a position. Name is the full name
+// of the declaration that contains the symbol (or the import path for
+// imports). Reference is true when the symbol refers to a definition
+// elsewhere, such as a field type or an RPC input.
+type SymbolInfo struct {
+	Kind      string          `json:"kind"`
+	Name      string          `json:"name"`
+	Reference bool            `json:"reference"`
+	Location  *protoutil.Span `json:"location,omitempty"`
+}
+
+// DefinitionInfo represents where a symbol is defined. Name is the full name
+// of the definition, or the path for files.
+type DefinitionInfo struct {
+	Kind     string          `json:"kind"`
+	Name     string          `json:"name"`
+	File     string          `json:"file"`
+	Location *protoutil.Span `json:"location,omitempty"`
+}
+
+// Handle handles the tool execution
+func (t *DefinitionAtTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := req.GetString("path", "")
+	if path == "" {
+		return mcp.NewToolResultError("path parameter is required"), nil
+	}
+	line := req.GetInt("line", 0)
+	column := req.GetInt("column", 0)
+	if line < 1 || column < 1 {
+		return mcp.NewToolResultError("line and column parameters are required and must be positive"), nil
+	}
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &DefinitionAtResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &DefinitionAtResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	file := findFile(files, path)
+	if file == nil {
+		response := &DefinitionAtResponse{
+			Success: false,
+			Message: fmt.Sprintf("File not found: %s", path),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	loc, ok := innermostLocation(file, line, column)
+	if !ok {
+		response := &DefinitionAtResponse{
+			Success: false,
+			Message: fmt.Sprintf("No symbol at %s:%d:%d", path, line, column),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	symbol, definition := symbolAt(file, loc)
+
+	response := &DefinitionAtResponse{
+		Success:    true,
+		Message:    fmt.Sprintf("Found %s %s defined in %s", symbol.Kind, symbol.Name, definition.File),
+		Symbol:     symbol,
+		Definition: definition,
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// symbolAt identifies the symbol at a source location and its definition.
+// Type references, extendees, RPC inputs and outputs, custom options and
+// imports resolve to the referenced declaration; anything else within a
+// declaration resolves to the declaration itself.
+func symbolAt(file protoreflect.FileDescriptor, loc protoreflect.SourceLocation) (*SymbolInfo, *DefinitionInfo) {
+	desc, rest := descriptorAtPath(file, loc.Path)
+	symbol := &SymbolInfo{
+		Kind:     descriptorKind(desc),
+		Name:     string(desc.FullName()),
+		Location: protoutil.SpanOfLocation(file, loc),
+	}
+	if _, ok := desc.(protoreflect.FileDescriptor); ok {
+		symbol.Name = file.Path()
+	}
+
+	var target protoreflect.Descriptor
+	switch d := desc.(type) {
+	case protoreflect.FileDescriptor:
+		switch {
+		case len(rest) >= 2 && rest[0] == fileImportsPathElement && int(rest[1]) < d.Imports().Len():
+			imp := d.Imports().Get(int(rest[1]))
+			symbol.Kind = symbolKindImport
+			symbol.Name = imp.Path()
+			symbol.Reference = true
+			return symbol, &DefinitionInfo{Kind: descriptorKind(imp.FileDescriptor), Name: imp.Path(), File: imp.Path()}
+		case len(rest) >= 1 && rest[0] == filePackagePathElement:
+			symbol.Kind = symbolKindPackage
+			symbol.Name = string(d.Package())
+			return symbol, &DefinitionInfo{Kind: symbolKindPackage, Name: string(d.Package()), File: d.Path(), Location: symbol.Location}
+		}
+	case protoreflect.FieldDescriptor:
+		switch {
+		case len(rest) >= 1 && rest[0] == fieldTypeNamePathElement:
+			field := d
+			symbol.Kind = referenceKindField
+			if d.IsExtension() {
+				symbol.Kind = referenceKindExtension
+			}
+			if d.IsMap() {
+				field = d.MapValue()
+				symbol.Kind = referenceKindMapValue
+			}
+			if field.Message() != nil {
+				target = field.Message()
+			} else if field.Enum() != nil {
+				target = field.Enum()
+			}
+		case len(rest) >= 1 && rest[0] == fieldExtendeePathElement && d.IsExtension():
+			symbol.Kind = referenceKindExtendee
+			target = d.ContainingMessage()
+		}
+	case protoreflect.MethodDescriptor:
+		switch {
+		case len(rest) >= 1 && rest[0] == methodInputPathElement:
+			symbol.Kind = referenceKindMethodInput
+			target = d.Input()
+		case len(rest) >= 1 && rest[0] == methodOutputPathElement:
+			symbol.Kind = referenceKindMethodOutput
+			target = d.Output()
+		}
+	}
+
+	if target == nil && len(rest) >= 2 && rest[0] == optionsPathElement(desc) {
+		if option := optionField(desc, protoreflect.FieldNumber(rest[1])); option != nil {
+			symbol.Kind = symbolKindOption
+			target = option
+		}
+	}
+
+	if target == nil {
+		target = desc
+	} else {
+		symbol.Reference = true
+	}
+
+	definition := &DefinitionInfo{
+		Kind:     descriptorKind(target),
+		Name:     string(target.FullName()),
+		File:     target.ParentFile().Path(),
+		Location: protoutil.SpanOf(target),
+	}
+	if _, ok := target.(protoreflect.FileDescriptor); ok {
+		definition.Name = file.Path()
+		definition.Location = symbol.Location
+	}
+	return symbol, definition
+}
+
+// optionField returns the field or extension set in a declaration's options
+// with the given number, or nil if it is not set
+func optionField(desc protoreflect.Descriptor, number protoreflect.FieldNumber) protoreflect.FieldDescriptor {
+	options := desc.Options()
+	if options == nil {
+		return nil
+	}
+	var found protoreflect.FieldDescriptor
+	options.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if field.Number() == number {
+			found = field
+			return false
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,154 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDefinitionAtTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewDefinitionAtTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "definition_at" {
+		t.Fatalf("Expected tool name 'definition_at', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestDefinitionAtTool_Handle(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewDefinitionAtTool(mockProjectManager)
+
+	tests := []struct {
+		name           string
+		line, column   int
+		symbolKind     string
+		symbolName     string
+		reference      bool
+		definitionKind string
+		definitionName string
+		definitionFile string
+	}{
+		{
+			name: "method output", line: 21, column: 41,
+			symbolKind: referenceKindMethodOutput, symbolName: "example.simple.v1.GreetingService.GetUser", reference: true,
+			definitionKind: "message", definitionName: "example.simple.v1.User", definitionFile: "types.proto",
+		},
+		{
+			name: "custom option in imported file", line: 14, column: 20,
+			symbolKind: symbolKindOption, symbolName: "example.simple.v1.GreetingService.SayHello", reference: true,
+			definitionKind: "extension", definitionName: "google.api.http", definitionFile: "google/api/annotations.proto",
+		},
+		{
+			name: "field type", line: 65, column: 4,
+			symbolKind: referenceKindField, symbolName: "example.simple.v1.HelloResponse.status", reference: true,
+			definitionKind: "enum", definitionName: "example.simple.v1.Status", definitionFile: "types.proto",
+		},
+		{
+			name: "field declaration", line: 58, column: 12,
+			symbolKind: "field", symbolName: "example.simple.v1.HelloRequest.language",
+			definitionKind: "field", definitionName: "example.simple.v1.HelloRequest.language", definitionFile: "api.proto",
+		},
+		{
+			name: "import", line: 7, column: 10,
+			symbolKind: symbolKindImport, symbolName: "types.proto", reference: true,
+			definitionKind: "file", definitionName: "types.proto", definitionFile: "types.proto",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name: "definition_at",
+					Arguments: map[string]interface{}{
+						"path":   "api.proto",
+						"line":   tt.line,
+						"column": tt.column,
+					},
+				},
+			}
+
+			result, err := tool.Handle(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Handle failed: %v", err)
+			}
+
+			var response DefinitionAtResponse
+			if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+				if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+			} else {
+				t.Fatalf("Expected text content in response")
+			}
+
+			if !response.Success {
+				t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+			}
+
+			symbol := response.Symbol
+			if symbol.Kind != tt.symbolKind || symbol.Name != tt.symbolName || symbol.Reference != tt.reference {
+				t.Errorf("Expected symbol %s %s (reference=%v), got %+v", tt.symbolKind, tt.symbolName, tt.reference, symbol)
+			}
+			if symbol.Location == nil || symbol.Location.StartLine > tt.line || symbol.Location.EndLine < tt.line {
+				t.Errorf("Expected symbol location to contain line %d, got %+v", tt.line, symbol.Location)
+			}
+
+			definition := response.Definition
+			if definition.Kind != tt.definitionKind || definition.Name != tt.definitionName || definition.File != tt.definitionFile {
+				t.Errorf("Expected definition %s %s in %s, got %+v", tt.definitionKind, tt.definitionName, tt.definitionFile, definition)
+			}
+			if tt.definitionKind != "file" && (definition.Location == nil || definition.Location.File != tt.definitionFile) {
+				t.Errorf("Expected definition location in %s, got %+v", tt.definitionFile, definition.Location)
+			}
+		})
+	}
+}
+
+func TestDefinitionAtTool_Handle_FileNotFound(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewDefinitionAtTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "definition_at",
+			Arguments: map[string]interface{}{"path": "missing.proto", "line": 1, "column": 1},
+		},
+	}
+
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	var response DefinitionAtResponse
+	if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+		if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+	}
+
+	if response.Success {
+		t.Fatalf("Expected success=false for a missing file")
+	}
+}
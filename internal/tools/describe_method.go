@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DescribeMethodTool implements the describe_method MCP tool using mcp-go
+type DescribeMethodTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewDescribeMethodTool creates a new DescribeMethodTool instance
+func NewDescribeMethodTool(projectManager ProjectManagerInterface) *DescribeMethodTool {
+	return &DescribeMethodTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *DescribeMethodTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"describe_method",
+		mcp.WithDescription("Describe an RPC method end-to-end: streaming mode, comments, options, HTTP bindings and fully expanded request and response messages"),
+		mcp.WithString("method",
+			mcp.Required(),
+			mcp.Description("Method reference: 'Service/Method', 'pkg.Service/Method' or 'pkg.Service.Method'"),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description("Maximum nesting depth of expanded messages (unlimited when omitted; recursive types are always cut)"),
+		),
+	)
+}
+
+// DescribeMethodResponse represents the response from describe_method tool
+type DescribeMethodResponse struct {
+	Success       bool         `json:"success"`
+	Message       string       `json:"message"`
+	SchemaVersion string       `json:"schema_version,omitempty"`
+	Service       string       `json:"service,omitempty"`
+	Method        *MethodInfo  `json:"method,omitempty"`
+	Streaming     string       `json:"streaming,omitempty"`
+	Input         *MessageTree `json:"input,omitempty"`
+	Output        *MessageTree `json:"output,omitempty"`
+}
+
+// MessageTree is a message with its message-typed fields expanded in place
+type MessageTree struct {
+	Name        string      `json:"name"`
+	FullName    string      `json:"full_name"`
+	File        string      `json:"file"`
+	Description string      `json:"description"`
+	Fields      []FieldTree `json:"fields"`
+}
+
+// FieldTree is a field of a MessageTree. Message fields carry the expanded
+// message, enum fields the names of their values. Recursive is set when the
+// message was not expanded because it already appears among its ancestors,
+// Truncated when the depth limit was reached.
+type FieldTree struct {
+	FieldInfo
+	Message    *MessageTree `json:"message,omitempty"`
+	EnumValues []string     `json:"enum_values,omitempty"`
+	Recursive  bool         `json:"recursive,omitempty"`
+	Truncated  bool         `json:"truncated,omitempty"`
+}
+
+// Handle handles the tool execution
+func (t *DescribeMethodTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	methodRef := req.GetString("method", "")
+	if methodRef == "" {
+		return mcp.NewToolResultError("method parameter is required"), nil
+	}
+	depth := req.GetInt("depth", -1)
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &DescribeMethodResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &DescribeMethodResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	method, err := findMethod(files, methodRef)
+	if err != nil {
+		response := &DescribeMethodResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to find method: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	methodInfo := newMethodInfo(method, convertOptions{})
+	response := &DescribeMethodResponse{
+		Success:       true,
+		Message:       fmt.Sprintf("Described method %s", method.FullName()),
+		SchemaVersion: SchemaVersion,
+		Service:       string(method.Parent().FullName()),
+		Method:        &methodInfo,
+		Streaming:     streamingMode(method),
+		Input:         newMessageTree(method.Input(), depth, nil),
+		Output:        newMessageTree(method.Output(), depth, nil),
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// streamingMode describes the streaming mode of a method
+func streamingMode(method protoreflect.MethodDescriptor) string {
+	switch {
+	case method.IsStreamingClient() && method.IsStreamingServer():
+		return "bidi_streaming"
+	case method.IsStreamingClient():
+		return "client_streaming"
+	case method.IsStreamingServer():
+		return "server_streaming"
+	default:
+		return "unary"
+	}
+}
+
+// newMessageTree expands a message and its message-typed fields up to depth
+// levels (negative for unlimited). ancestors holds the messages currently
+// being expanded and is used to cut recursion.
+func newMessageTree(message protoreflect.MessageDescriptor, depth int, ancestors map[protoreflect.FullName]bool) *MessageTree {
+	if ancestors == nil {
+		ancestors = make(map[protoreflect.FullName]bool)
+	}
+	ancestors[message.FullName()] = true
+	defer delete(ancestors, message.FullName())
+
+	tree := &MessageTree{
+		Name:        string(message.Name()),
+		FullName:    string(message.FullName()),
+		File:        message.ParentFile().Path(),
+		Description: descriptionOf(message, false),
+		Fields:      make([]FieldTree, 0, message.Fields().Len()),
+	}
+
+	for i := 0; i < message.Fields().Len(); i++ {
+		field := message.Fields().Get(i)
+		fieldTree := FieldTree{FieldInfo: newFieldInfo(field, convertOptions{})}
+
+		target := field
+		if field.IsMap() {
+			target = field.MapValue()
+		}
+		switch {
+		case target.Message() != nil:
+			switch {
+			case ancestors[target.Message().FullName()]:
+				fieldTree.Recursive = true
+			case depth == 0:
+				fieldTree.Truncated = true
+			default:
+				fieldTree.Message = newMessageTree(target.Message(), depth-1, ancestors)
+			}
+		case target.Enum() != nil:
+			values := target.Enum().Values()
+			for j := 0; j < values.Len(); j++ {
+				fieldTree.EnumValues = append(fieldTree.EnumValues, string(values.Get(j).Name()))
+			}
+		}
+
+		tree.Fields = append(tree.Fields, fieldTree)
+	}
+
+	return tree
+}
@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDescribeMethodTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewDescribeMethodTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "describe_method" {
+		t.Fatalf("Expected tool name 'describe_method', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestDescribeMethodTool_Handle(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewDescribeMethodTool(mockProjectManager)
+
+	callDescribeMethod := func(arguments map[string]interface{}) DescribeMethodResponse {
+		req := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "describe_method",
+				Arguments: arguments,
+			},
+		}
+
+		result, err := tool.Handle(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+
+		var response DescribeMethodResponse
+		if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+			if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+		} else {
+			t.Fatalf("Expected text content in response")
+		}
+		return response
+	}
+
+	t.Run("ReferenceForms", func(t *testing.T) {
+		for _, ref := range []string{
+			"GreetingService/GetUser",
+			"example.simple.v1.GreetingService/GetUser",
+			"example.simple.v1.GreetingService.GetUser",
+		} {
+			response := callDescribeMethod(map[string]interface{}{"method": ref})
+			if !response.Success {
+				t.Fatalf("Expected success for %s, got: %s", ref, response.Message)
+			}
+			if response.Method.FullName != "example.simple.v1.GreetingService.GetUser" {
+				t.Fatalf("Expected GetUser for %s, got %s", ref, response.Method.FullName)
+			}
+		}
+	})
+
+	t.Run("HTTPBindingsAndExpansion", func(t *testing.T) {
+		response := callDescribeMethod(map[string]interface{}{"method": "GreetingService/GetUser"})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+
+		if response.Service != "example.simple.v1.GreetingService" {
+			t.Fatalf("Unexpected service: %s", response.Service)
+		}
+		if response.Streaming != "unary" {
+			t.Fatalf("Expected unary, got %s", response.Streaming)
+		}
+		if response.Method.Description != "Get user information" {
+			t.Fatalf("Unexpected description: %q", response.Method.Description)
+		}
+
+		bindings := response.Method.HTTPBindings
+		if len(bindings) != 2 {
+			t.Fatalf("Expected 2 HTTP bindings, got %+v", bindings)
+		}
+		if bindings[0].Method != "GET" || bindings[0].Path != "/v1/users/{user_id}" {
+			t.Fatalf("Unexpected primary binding: %+v", bindings[0])
+		}
+		if bindings[1].Path != "/v1/greeting/users/{user_id}" {
+			t.Fatalf("Unexpected additional binding: %+v", bindings[1])
+		}
+
+		foundOption := false
+		for _, option := range response.Method.Options {
+			if option.Name == "google.api.http" {
+				foundOption = true
+			}
+		}
+		if !foundOption {
+			t.Fatalf("Expected google.api.http in options, got %+v", response.Method.Options)
+		}
+
+		if response.Input.FullName != "example.simple.v1.GetUserRequest" {
+			t.Fatalf("Unexpected input: %s", response.Input.FullName)
+		}
+
+		// User.address.coordinates is expanded two levels deep
+		var address *MessageTree
+		for _, field := range response.Output.Fields {
+			switch field.Name {
+			case "address":
+				address = field.Message
+			case "role":
+				if len(field.EnumValues) != 6 || field.EnumValues[0] != "USER_ROLE_UNSPECIFIED" {
+					t.Fatalf("Unexpected enum values for role: %v", field.EnumValues)
+				}
+			}
+		}
+		if address == nil {
+			t.Fatalf("Expected address to be expanded")
+		}
+		coordinates := address.Fields[len(address.Fields)-1]
+		if coordinates.Message == nil || coordinates.Message.Name != "Coordinates" {
+			t.Fatalf("Expected coordinates to be expanded, got %+v", coordinates)
+		}
+	})
+
+	t.Run("DepthAndStreaming", func(t *testing.T) {
+		response := callDescribeMethod(map[string]interface{}{"method": "GreetingService/Chat", "depth": 0})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+		if response.Streaming != "bidi_streaming" {
+			t.Fatalf("Expected bidi_streaming, got %s", response.Streaming)
+		}
+
+		response = callDescribeMethod(map[string]interface{}{"method": "GreetingService/GetUser", "depth": 0})
+		for _, field := range response.Output.Fields {
+			if field.Name == "address" && (field.Message != nil || !field.Truncated) {
+				t.Fatalf("Expected address to be truncated at depth 0, got %+v", field)
+			}
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		response := callDescribeMethod(map[string]interface{}{"method": "GreetingService/Missing"})
+		if response.Success {
+			t.Fatalf("Expected success=false for a missing method")
+		}
+		if !strings.Contains(response.Message, "method Missing not found") {
+			t.Fatalf("Unexpected message: %s", response.Message)
+		}
+	})
+}
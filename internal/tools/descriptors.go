@@ -0,0 +1,307 @@
+package tools
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/bufbuild/protocompile/linker"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// findFile finds a file by path among the compiled files and their transitive
+// imports, returning nil if no such file exists
+func findFile(files linker.Files, path string) protoreflect.FileDescriptor {
+	for _, file := range allFiles(files) {
+		if file.Path() == path {
+			return file
+		}
+	}
+	return nil
+}
+
+// matchesPackage checks if a package matches a package filter. The filter is
+// either an exact package name or a glob such as "example.*".
+func matchesPackage(pkg, filter string) bool {
+	if filter == "" || pkg == filter {
+		return true
+	}
+	matched, err := path.Match(filter, pkg)
+	return err == nil && matched
+}
+
+// packageVersionPattern matches version suffixes such as v1, v1beta1,
+// v2alpha or v1p1beta1
+var packageVersionPattern = regexp.MustCompile(`^v\d+((alpha|beta)\d*)?(p\d+((alpha|beta)\d*)?)?$`)
+
+// packageVersion returns the version suffix of a package (the last component
+// if it looks like v1, v1beta1, ...), or an empty string if there is none
+func packageVersion(pkg string) string {
+	last := pkg[strings.LastIndexByte(pkg, '.')+1:]
+	if packageVersionPattern.MatchString(last) {
+		return last
+	}
+	return ""
+}
+
+// allFiles returns the compiled files followed by their transitive imports,
+// each file once, in a deterministic order
+func allFiles(files linker.Files) []protoreflect.FileDescriptor {
+	seen := make(map[string]bool)
+	var result []protoreflect.FileDescriptor
+	var visit func(file protoreflect.FileDescriptor)
+	visit = func(file protoreflect.FileDescriptor) {
+		if seen[file.Path()] {
+			return
+		}
+		seen[file.Path()] = true
+		result = append(result, file)
+		for i := 0; i < file.Imports().Len(); i++ {
+			visit(file.Imports().Get(i).FileDescriptor)
+		}
+	}
+
+	// Visit the compiled files first so that they come before dependencies
+	for _, file := range files {
+		seen[file.Path()] = true
+		result = append(result, file)
+	}
+	for _, file := range files {
+		for i := 0; i < file.Imports().Len(); i++ {
+			visit(file.Imports().Get(i).FileDescriptor)
+		}
+	}
+
+	return result
+}
+
+// findDescriptor finds a descriptor by full name among the compiled files and
+// their transitive imports. A leading dot is accepted. Returns nil if no such
+// element exists.
+func findDescriptor(files linker.Files, name string) protoreflect.Descriptor {
+	fullName := protoreflect.FullName(strings.TrimPrefix(name, "."))
+	for _, file := range allFiles(files) {
+		if linked, ok := file.(linker.File); ok {
+			if desc := linked.FindDescriptorByName(fullName); desc != nil {
+				return desc
+			}
+		}
+	}
+	return nil
+}
+
+// referencedTypes returns the messages and enums directly referenced by the
+// fields of a message, in field order. Map fields contribute their value type
+// rather than the synthesized map entry.
+func referencedTypes(message protoreflect.MessageDescriptor) []protoreflect.Descriptor {
+	var refs []protoreflect.Descriptor
+	for i := 0; i < message.Fields().Len(); i++ {
+		field := message.Fields().Get(i)
+		if field.IsMap() {
+			field = field.MapValue()
+		}
+		switch {
+		case field.Message() != nil:
+			refs = append(refs, field.Message())
+		case field.Enum() != nil:
+			refs = append(refs, field.Enum())
+		}
+	}
+	return refs
+}
+
+// typeClosure walks the types referenced from the roots breadth-first and
+// returns every message and enum reached, each once, in visiting order.
+// maxDepth limits the number of reference hops from the roots; a negative
+// value means no limit. truncated reports whether the limit cut off
+// further references.
+func typeClosure(roots []protoreflect.Descriptor, maxDepth int) (messages []protoreflect.MessageDescriptor, enums []protoreflect.EnumDescriptor, truncated bool) {
+	seen := make(map[protoreflect.FullName]bool)
+	current := roots
+	for depth := 0; len(current) > 0; depth++ {
+		var next []protoreflect.Descriptor
+		for _, desc := range current {
+			if seen[desc.FullName()] {
+				continue
+			}
+			seen[desc.FullName()] = true
+
+			switch d := desc.(type) {
+			case protoreflect.MessageDescriptor:
+				messages = append(messages, d)
+				for _, ref := range referencedTypes(d) {
+					if seen[ref.FullName()] {
+						continue
+					}
+					if maxDepth >= 0 && depth >= maxDepth {
+						truncated = true
+						continue
+					}
+					next = append(next, ref)
+				}
+			case protoreflect.EnumDescriptor:
+				enums = append(enums, d)
+			}
+		}
+		current = next
+	}
+	return messages, enums, truncated
+}
+
+// findMethod finds an RPC method by reference. Accepted forms are
+// "Service/Method", "pkg.Service/Method" and "pkg.Service.Method". Short
+// service names are matched against every service in the compiled files and
+// must be unambiguous.
+func findMethod(files linker.Files, ref string) (protoreflect.MethodDescriptor, error) {
+	ref = strings.TrimPrefix(ref, ".")
+
+	var serviceName, methodName string
+	if i := strings.LastIndexByte(ref, '/'); i >= 0 {
+		serviceName, methodName = ref[:i], ref[i+1:]
+	} else {
+		if method, ok := findDescriptor(files, ref).(protoreflect.MethodDescriptor); ok {
+			return method, nil
+		}
+		i := strings.LastIndexByte(ref, '.')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid method reference %q: expected Service/Method or pkg.Service.Method", ref)
+		}
+		serviceName, methodName = ref[:i], ref[i+1:]
+	}
+
+	var candidates []protoreflect.ServiceDescriptor
+	if service, ok := findDescriptor(files, serviceName).(protoreflect.ServiceDescriptor); ok {
+		candidates = append(candidates, service)
+	} else {
+		for _, file := range files {
+			for i := 0; i < file.Services().Len(); i++ {
+				if service := file.Services().Get(i); string(service.Name()) == serviceName {
+					candidates = append(candidates, service)
+				}
+			}
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("service not found: %s", serviceName)
+	case 1:
+	default:
+		names := make([]string, 0, len(candidates))
+		for _, service := range candidates {
+			names = append(names, string(service.FullName()))
+		}
+		return nil, fmt.Errorf("service name %s is ambiguous, use one of: %s", serviceName, strings.Join(names, ", "))
+	}
+
+	method := candidates[0].Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", methodName, candidates[0].FullName())
+	}
+	return method, nil
+}
+
+// forEachMessage calls fn for every message declared in a file, including
+// nested messages but excluding synthesized map entries, in declaration order
+func forEachMessage(file protoreflect.FileDescriptor, fn func(protoreflect.MessageDescriptor)) {
+	var visit func(messages protoreflect.MessageDescriptors)
+	visit = func(messages protoreflect.MessageDescriptors) {
+		for i := 0; i < messages.Len(); i++ {
+			message := messages.Get(i)
+			if message.IsMapEntry() {
+				continue
+			}
+			fn(message)
+			visit(message.Messages())
+		}
+	}
+	visit(file.Messages())
+}
+
+// forEachEnum calls fn for every enum declared in a file, including enums
+// nested in messages, in declaration order
+func forEachEnum(file protoreflect.FileDescriptor, fn func(protoreflect.EnumDescriptor)) {
+	for i := 0; i < file.Enums().Len(); i++ {
+		fn(file.Enums().Get(i))
+	}
+	forEachMessage(file, func(message protoreflect.MessageDescriptor) {
+		for i := 0; i < message.Enums().Len(); i++ {
+			fn(message.Enums().Get(i))
+		}
+	})
+}
+
+// forEachExtension calls fn for every extension declared in a file, at the
+// top level or nested in messages
+func forEachExtension(file protoreflect.FileDescriptor, fn func(protoreflect.ExtensionDescriptor)) {
+	for i := 0; i < file.Extensions().Len(); i++ {
+		fn(file.Extensions().Get(i))
+	}
+	forEachMessage(file, func(message protoreflect.MessageDescriptor) {
+		for i := 0; i < message.Extensions().Len(); i++ {
+			fn(message.Extensions().Get(i))
+		}
+	})
+}
+
+// descriptorKind returns the kind of declaration a descriptor represents
+func descriptorKind(desc protoreflect.Descriptor) string {
+	switch d := desc.(type) {
+	case protoreflect.FileDescriptor:
+		return "file"
+	case protoreflect.MessageDescriptor:
+		return "message"
+	case protoreflect.FieldDescriptor:
+		if d.IsExtension() {
+			return "extension"
+		}
+		return "field"
+	case protoreflect.OneofDescriptor:
+		return "oneof"
+	case protoreflect.EnumDescriptor:
+		return "enum"
+	case protoreflect.EnumValueDescriptor:
+		return "enum_value"
+	case protoreflect.ServiceDescriptor:
+		return "service"
+	case protoreflect.MethodDescriptor:
+		return "method"
+	default:
+		return "unknown"
+	}
+}
+
+// forEachDeclaration calls fn for every named declaration in a file:
+// messages, fields, oneofs, enums, enum values, services, methods and
+// extensions. Map entries and synthetic oneofs of proto3 optional fields are
+// skipped.
+func forEachDeclaration(file protoreflect.FileDescriptor, fn func(protoreflect.Descriptor)) {
+	forEachMessage(file, func(message protoreflect.MessageDescriptor) {
+		fn(message)
+		for i := 0; i < message.Fields().Len(); i++ {
+			fn(message.Fields().Get(i))
+		}
+		for i := 0; i < message.Oneofs().Len(); i++ {
+			if oneof := message.Oneofs().Get(i); !oneof.IsSynthetic() {
+				fn(oneof)
+			}
+		}
+	})
+	forEachEnum(file, func(enum protoreflect.EnumDescriptor) {
+		fn(enum)
+		for i := 0; i < enum.Values().Len(); i++ {
+			fn(enum.Values().Get(i))
+		}
+	})
+	for i := 0; i < file.Services().Len(); i++ {
+		service := file.Services().Get(i)
+		fn(service)
+		for j := 0; j < service.Methods().Len(); j++ {
+			fn(service.Methods().Get(j))
+		}
+	}
+	forEachExtension(file, func(extension protoreflect.ExtensionDescriptor) {
+		fn(extension)
+	})
+}
@@ -0,0 +1,640 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/breaking"
+	"github.com/yuemori/protobuf-mcp-server/internal/compiler"
+	"github.com/yuemori/protobuf-mcp-server/internal/config"
+	"github.com/yuemori/protobuf-mcp-server/internal/gitrepo"
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+	"github.com/yuemori/protobuf-mcp-server/internal/snapshot"
+)
+
+// Kinds of schema changes
+const (
+	schemaChangeAdded    = "added"
+	schemaChangeRemoved  = "removed"
+	schemaChangeModified = "modified"
+)
+
+// DiffSchemaTool implements the diff_schema MCP tool using mcp-go
+type DiffSchemaTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewDiffSchemaTool creates a new DiffSchemaTool instance
+func NewDiffSchemaTool(projectManager ProjectManagerInterface) *DiffSchemaTool {
+	return &DiffSchemaTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *DiffSchemaTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"diff_schema",
+		mcp.WithDescription("Produce a changelog between two versions of the schema: added, removed and modified files, services, methods, messages, fields, oneofs, enums, enum values and extensions, including option and comment changes, as JSON and Markdown for PR descriptions and release notes. Each version is a git ref, a directory or a FileDescriptorSet file"),
+		mcp.WithString("from",
+			mcp.Required(),
+			mcp.Description("Previous version: a git ref, a directory or a FileDescriptorSet file (paths relative to the project root); prefix with 'git:', 'dir:' or 'set:' to disambiguate"),
+		),
+		mcp.WithString("to",
+			mcp.Description("New version, in the same forms as from; defaults to the working tree of the activated project"),
+		),
+		mcp.WithBoolean("include_comments",
+			mcp.Description("Report comment changes (default true)"),
+		),
+	)
+}
+
+// DiffSchemaResponse represents the response from diff_schema tool
+type DiffSchemaResponse struct {
+	Success  bool           `json:"success"`
+	Message  string         `json:"message"`
+	From     string         `json:"from,omitempty"`
+	To       string         `json:"to,omitempty"`
+	Summary  *DiffSummary   `json:"summary,omitempty"`
+	Changes  []SchemaChange `json:"changes,omitempty"`
+	Markdown string         `json:"markdown,omitempty"`
+}
+
+// DiffSummary counts the changes of each kind
+type DiffSummary struct {
+	Added    int `json:"added"`
+	Removed  int `json:"removed"`
+	Modified int `json:"modified"`
+}
+
+// SchemaChange is one entry of a schema changelog. Name is the full name of
+// the element, or the path for files. Declaration shows added and removed
+// fields, enum values and methods as written in source.
+type SchemaChange struct {
+	Change      string          `json:"change"`
+	Kind        string          `json:"kind"`
+	Name        string          `json:"name"`
+	File        string          `json:"file"`
+	Declaration string          `json:"declaration,omitempty"`
+	Details     []ChangeDetail  `json:"details,omitempty"`
+	Location    *protoutil.Span `json:"location,omitempty"`
+}
+
+// ChangeDetail is a modified attribute of an element. From or To is empty
+// when the attribute (e.g. an option or a comment) was added or removed.
+type ChangeDetail struct {
+	Attribute string `json:"attribute"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+}
+
+// Handle handles the tool execution
+func (t *DiffSchemaTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	from := req.GetString("from", "")
+	if from == "" {
+		return mcp.NewToolResultError("from parameter is required"), nil
+	}
+	to := req.GetString("to", "")
+	includeComments := req.GetBool("include_comments", true)
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &DiffSchemaResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	previous, err := loadSchemaVersion(ctx, project, from)
+	if err != nil {
+		response := &DiffSchemaResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to load %s: %v", from, err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+	current, err := loadSchemaVersion(ctx, project, to)
+	if err != nil {
+		response := &DiffSchemaResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to load %s: %v", schemaVersionLabel(to), err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	changes := diffSchemas(previous, current, includeComments)
+	summary := summarizeChanges(changes)
+
+	response := &DiffSchemaResponse{
+		Success:  true,
+		Message:  fmt.Sprintf("%d added, %d removed, %d modified", summary.Added, summary.Removed, summary.Modified),
+		From:     from,
+		To:       schemaVersionLabel(to),
+		Summary:  summary,
+		Changes:  changes,
+		Markdown: renderSchemaChangelog(from, schemaVersionLabel(to), changes),
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// schemaVersionLabel names a version for messages; the empty version is the
+// working tree
+func schemaVersionLabel(spec string) string {
+	if spec == "" {
+		return "working tree"
+	}
+	return spec
+}
+
+// loadSchemaVersion loads the project files of one version of the schema.
+// An empty spec is the working tree. Otherwise the spec is a git ref, a
+// directory or a FileDescriptorSet file, either prefixed with 'git:', 'dir:'
+// or 'set:' or recognized by what exists at the path.
+func loadSchemaVersion(ctx context.Context, project *compiler.ProtobufProject, spec string) ([]protoreflect.FileDescriptor, error) {
+	if spec == "" {
+		files, err := project.CompileProtos(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return breaking.FileDescriptors(files), nil
+	}
+
+	kind, value, explicit := strings.Cut(spec, ":")
+	if !explicit || (kind != "git" && kind != "dir" && kind != "set") {
+		kind, value = "git", spec
+		if info, err := os.Stat(resolveProjectPath(project, spec)); err == nil {
+			kind = "set"
+			if info.IsDir() {
+				kind = "dir"
+			}
+		}
+	}
+
+	switch kind {
+	case "dir":
+		return compileDirectory(ctx, project, resolveProjectPath(project, value))
+	case "set":
+		return snapshot.Read(resolveProjectPath(project, value))
+	default:
+		files, err := gitrepo.CompileProtos(ctx, project.ProjectRoot, project.Config, value)
+		if err != nil {
+			return nil, err
+		}
+		return breaking.FileDescriptors(files), nil
+	}
+}
+
+// resolveProjectPath resolves a path relative to the project root
+func resolveProjectPath(project *compiler.ProtobufProject, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(project.ProjectRoot, path)
+}
+
+// compileDirectory compiles the proto files of another directory, using its
+// own .protobuf-mcp.yml if it has one and the activated project's
+// configuration otherwise. The working directory is restored afterwards.
+func compileDirectory(ctx context.Context, project *compiler.ProtobufProject, dir string) ([]protoreflect.FileDescriptor, error) {
+	cfg := project.Config
+	if config.ProjectExists(dir) {
+		var err error
+		if cfg, err = config.LoadProjectConfig(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	defer os.Chdir(cwd)
+
+	files, err := compiler.CompileProtos(ctx, dir, cfg.ProtoFiles, cfg.ImportPaths)
+	if err != nil {
+		return nil, err
+	}
+	return breaking.FileDescriptors(files), nil
+}
+
+// schemaDiff holds the state of one comparison
+type schemaDiff struct {
+	previous, current           map[protoreflect.FullName]protoreflect.Descriptor
+	previousFiles, currentFiles map[string]protoreflect.FileDescriptor
+	// renamed holds the current names of fields and enum values matched to a
+	// previous element by number
+	renamed         map[protoreflect.FullName]bool
+	includeComments bool
+	changes         []SchemaChange
+}
+
+// diffSchemas compares two versions of a schema. Elements are matched by
+// full name, except that fields and enum values that changed name but kept
+// their number are reported as modified. Elements added or removed together
+// with their parent are not reported separately.
+func diffSchemas(previous, current []protoreflect.FileDescriptor, includeComments bool) []SchemaChange {
+	d := &schemaDiff{
+		previous:        declarationsByName(previous),
+		current:         declarationsByName(current),
+		previousFiles:   filesByPath(previous),
+		currentFiles:    filesByPath(current),
+		renamed:         make(map[protoreflect.FullName]bool),
+		includeComments: includeComments,
+	}
+
+	for _, file := range sortedFiles(previous) {
+		if next, ok := d.currentFiles[file.Path()]; ok {
+			d.modified(file, next)
+		} else {
+			d.add(schemaChangeRemoved, file)
+		}
+	}
+	for _, file := range sortedFiles(current) {
+		if _, ok := d.previousFiles[file.Path()]; !ok {
+			d.add(schemaChangeAdded, file)
+		}
+	}
+
+	for _, file := range sortedFiles(previous) {
+		forEachDeclaration(file, func(desc protoreflect.Descriptor) {
+			if next, ok := d.current[desc.FullName()]; ok && descriptorKind(next) == descriptorKind(desc) {
+				d.modified(desc, next)
+				return
+			}
+			if !d.parentSurvives(desc, d.current, d.currentFiles) {
+				return
+			}
+			if next := d.renamedTo(desc); next != nil {
+				d.renamed[next.FullName()] = true
+				d.modified(desc, next)
+				return
+			}
+			d.add(schemaChangeRemoved, desc)
+		})
+	}
+	for _, file := range sortedFiles(current) {
+		forEachDeclaration(file, func(desc protoreflect.Descriptor) {
+			if prev, ok := d.previous[desc.FullName()]; ok && descriptorKind(prev) == descriptorKind(desc) {
+				return
+			}
+			if d.renamed[desc.FullName()] || !d.parentSurvives(desc, d.previous, d.previousFiles) {
+				return
+			}
+			d.add(schemaChangeAdded, desc)
+		})
+	}
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		a, b := d.changes[i], d.changes[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if (a.Kind == "file") != (b.Kind == "file") {
+			return a.Kind == "file"
+		}
+		return a.Name < b.Name
+	})
+	return d.changes
+}
+
+// parentSurvives reports whether the parent of a declaration exists in the
+// other version, so that the declaration itself is worth reporting
+func (d *schemaDiff) parentSurvives(desc protoreflect.Descriptor, other map[protoreflect.FullName]protoreflect.Descriptor, otherFiles map[string]protoreflect.FileDescriptor) bool {
+	parent := desc.Parent()
+	if file, ok := parent.(protoreflect.FileDescriptor); ok {
+		_, ok := otherFiles[file.Path()]
+		return ok
+	}
+	counterpart, ok := other[parent.FullName()]
+	return ok && descriptorKind(counterpart) == descriptorKind(parent)
+}
+
+// renamedTo finds the current field or enum value with the same number as a
+// previous one whose name no longer exists
+func (d *schemaDiff) renamedTo(desc protoreflect.Descriptor) protoreflect.Descriptor {
+	var next protoreflect.Descriptor
+	switch prev := desc.(type) {
+	case protoreflect.FieldDescriptor:
+		message, ok := d.current[prev.Parent().FullName()].(protoreflect.MessageDescriptor)
+		if prev.IsExtension() || !ok {
+			return nil
+		}
+		if field := message.Fields().ByNumber(prev.Number()); field != nil {
+			next = field
+		}
+	case protoreflect.EnumValueDescriptor:
+		enum, ok := d.current[prev.Parent().FullName()].(protoreflect.EnumDescriptor)
+		if !ok {
+			return nil
+		}
+		if value := enum.Values().ByNumber(prev.Number()); value != nil {
+			next = value
+		}
+	}
+	if next == nil {
+		return nil
+	}
+	if _, exists := d.previous[next.FullName()]; exists {
+		return nil
+	}
+	return next
+}
+
+// add records an added or removed element
+func (d *schemaDiff) add(change string, desc protoreflect.Descriptor) {
+	d.changes = append(d.changes, SchemaChange{
+		Change:      change,
+		Kind:        descriptorKind(desc),
+		Name:        changeName(desc),
+		File:        desc.ParentFile().Path(),
+		Declaration: declarationOf(desc),
+		Location:    protoutil.SpanOf(desc),
+	})
+}
+
+// modified records the differences between two versions of an element, if any
+func (d *schemaDiff) modified(previous, current protoreflect.Descriptor) {
+	details := d.compare(previous, current)
+	if len(details) == 0 {
+		return
+	}
+	d.changes = append(d.changes, SchemaChange{
+		Change:   schemaChangeModified,
+		Kind:     descriptorKind(current),
+		Name:     changeName(current),
+		File:     current.ParentFile().Path(),
+		Details:  details,
+		Location: protoutil.SpanOf(current),
+	})
+}
+
+// compare lists the attributes that differ between two versions of an element
+func (d *schemaDiff) compare(previous, current protoreflect.Descriptor) []ChangeDetail {
+	var details []ChangeDetail
+	detail := func(attribute, from, to string) {
+		if from != to {
+			details = append(details, ChangeDetail{Attribute: attribute, From: from, To: to})
+		}
+	}
+
+	switch prev := previous.(type) {
+	case protoreflect.FileDescriptor:
+		next := current.(protoreflect.FileDescriptor)
+		detail("package", string(prev.Package()), string(next.Package()))
+		detail("syntax", prev.Syntax().String(), next.Syntax().String())
+	case protoreflect.FieldDescriptor:
+		next := current.(protoreflect.FieldDescriptor)
+		detail("name", string(prev.Name()), string(next.Name()))
+		detail("number", fmt.Sprint(prev.Number()), fmt.Sprint(next.Number()))
+		detail("type", queryResultType(prev), queryResultType(next))
+		detail("label", fieldLabelOf(prev), fieldLabelOf(next))
+		detail("json_name", prev.JSONName(), next.JSONName())
+		detail("oneof", realOneofName(prev), realOneofName(next))
+		detail("default", defaultValueOf(prev), defaultValueOf(next))
+		if prev.IsExtension() {
+			detail("extendee", string(prev.ContainingMessage().FullName()), string(next.ContainingMessage().FullName()))
+		}
+	case protoreflect.EnumValueDescriptor:
+		next := current.(protoreflect.EnumValueDescriptor)
+		detail("name", string(prev.Name()), string(next.Name()))
+		detail("number", fmt.Sprint(prev.Number()), fmt.Sprint(next.Number()))
+	case protoreflect.MethodDescriptor:
+		next := current.(protoreflect.MethodDescriptor)
+		detail("input", string(prev.Input().FullName()), string(next.Input().FullName()))
+		detail("output", string(prev.Output().FullName()), string(next.Output().FullName()))
+		detail("client_streaming", fmt.Sprint(prev.IsStreamingClient()), fmt.Sprint(next.IsStreamingClient()))
+		detail("server_streaming", fmt.Sprint(prev.IsStreamingServer()), fmt.Sprint(next.IsStreamingServer()))
+	}
+
+	before := optionValues(previous)
+	after := optionValues(current)
+	for _, name := range sortedKeys(before, after) {
+		detail("option "+name, before[name], after[name])
+	}
+
+	if d.includeComments {
+		detail("comment", descriptionOf(previous, false), descriptionOf(current, false))
+	}
+
+	return details
+}
+
+// summarizeChanges counts the changes of each kind
+func summarizeChanges(changes []SchemaChange) *DiffSummary {
+	summary := &DiffSummary{}
+	for _, change := range changes {
+		switch change.Change {
+		case schemaChangeAdded:
+			summary.Added++
+		case schemaChangeRemoved:
+			summary.Removed++
+		case schemaChangeModified:
+			summary.Modified++
+		}
+	}
+	return summary
+}
+
+// renderSchemaChangelog renders changes as a Markdown changelog grouped into
+// added, removed and modified elements
+func renderSchemaChangelog(from, to string, changes []SchemaChange) string {
+	var b strings.Builder
+	b.WriteString("## Schema changes\n\n")
+
+	summary := summarizeChanges(changes)
+	fmt.Fprintf(&b, "Comparing `%s` to `%s`: %d added, %d removed, %d modified.\n", from, to, summary.Added, summary.Removed, summary.Modified)
+
+	for _, section := range []struct {
+		change string
+		title  string
+	}{
+		{schemaChangeAdded, "Added"},
+		{schemaChangeRemoved, "Removed"},
+		{schemaChangeModified, "Modified"},
+	} {
+		var lines []string
+		for _, change := range changes {
+			if change.Change != section.change {
+				continue
+			}
+			line := fmt.Sprintf("- %s `%s`", changeKindLabel(change.Kind), change.Name)
+			if change.Declaration != "" {
+				line += fmt.Sprintf(": `%s`", change.Declaration)
+			}
+			lines = append(lines, line)
+			for _, detail := range change.Details {
+				lines = append(lines, "  - "+renderChangeDetail(detail))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n%s\n", section.title, strings.Join(lines, "\n"))
+	}
+
+	return b.String()
+}
+
+// renderChangeDetail renders one modified attribute for Markdown. Comments
+// can span several lines, so only their change is mentioned.
+func renderChangeDetail(detail ChangeDetail) string {
+	switch {
+	case detail.Attribute == "comment":
+		switch {
+		case detail.From == "":
+			return "comment added"
+		case detail.To == "":
+			return "comment removed"
+		default:
+			return "comment changed"
+		}
+	case detail.From == "":
+		return fmt.Sprintf("%s added: `%s`", detail.Attribute, detail.To)
+	case detail.To == "":
+		return fmt.Sprintf("%s removed: `%s`", detail.Attribute, detail.From)
+	default:
+		return fmt.Sprintf("%s: `%s` → `%s`", detail.Attribute, detail.From, detail.To)
+	}
+}
+
+// changeKindLabel returns the word used for a kind of element in Markdown
+func changeKindLabel(kind string) string {
+	switch kind {
+	case "method":
+		return "rpc"
+	case "enum_value":
+		return "enum value"
+	default:
+		return kind
+	}
+}
+
+// changeName returns the full name of an element, or the path of a file
+func changeName(desc protoreflect.Descriptor) string {
+	if file, ok := desc.(protoreflect.FileDescriptor); ok {
+		return file.Path()
+	}
+	return string(desc.FullName())
+}
+
+// declarationOf renders fields, enum values and methods as written in source
+func declarationOf(desc protoreflect.Descriptor) string {
+	switch d := desc.(type) {
+	case protoreflect.FieldDescriptor:
+		return protoutil.Printer{OmitComments: true}.FieldDeclaration(d)
+	case protoreflect.EnumValueDescriptor:
+		return fmt.Sprintf("%s = %d;", d.Name(), d.Number())
+	case protoreflect.MethodDescriptor:
+		return fmt.Sprintf("rpc %s%s;", d.Name(), queryResultType(d))
+	default:
+		return ""
+	}
+}
+
+// fieldLabelOf returns the label of a field: repeated, required, optional
+// or empty. Map fields have no label; their type says they are maps.
+func fieldLabelOf(field protoreflect.FieldDescriptor) string {
+	switch {
+	case field.IsMap():
+		return ""
+	case field.IsList():
+		return "repeated"
+	case field.Cardinality() == protoreflect.Required:
+		return "required"
+	case field.HasOptionalKeyword() && realOneofName(field) == "":
+		return "optional"
+	default:
+		return ""
+	}
+}
+
+// realOneofName returns the name of the non-synthetic oneof containing a
+// field, or an empty string
+func realOneofName(field protoreflect.FieldDescriptor) string {
+	if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+		return string(oneof.Name())
+	}
+	return ""
+}
+
+// defaultValueOf returns the explicit default value of a field, or an empty
+// string
+func defaultValueOf(field protoreflect.FieldDescriptor) string {
+	if !field.HasDefault() {
+		return ""
+	}
+	if enumValue := field.DefaultEnumValue(); enumValue != nil {
+		return string(enumValue.Name())
+	}
+	return fmt.Sprint(field.Default().Interface())
+}
+
+// optionValues returns the options set on an element, rendered as text
+func optionValues(desc protoreflect.Descriptor) map[string]string {
+	values := make(map[string]string)
+	for _, option := range optionsOf(desc) {
+		values[option.Name] = formatOptionValue(option.Value)
+	}
+	return values
+}
+
+// sortedKeys returns the keys of two maps, each once, in order
+func sortedKeys(a, b map[string]string) []string {
+	var keys []string
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// declarationsByName indexes the declarations of files by full name
+func declarationsByName(files []protoreflect.FileDescriptor) map[protoreflect.FullName]protoreflect.Descriptor {
+	declarations := make(map[protoreflect.FullName]protoreflect.Descriptor)
+	for _, file := range files {
+		forEachDeclaration(file, func(desc protoreflect.Descriptor) {
+			declarations[desc.FullName()] = desc
+		})
+	}
+	return declarations
+}
+
+// filesByPath indexes files by path
+func filesByPath(files []protoreflect.FileDescriptor) map[string]protoreflect.FileDescriptor {
+	result := make(map[string]protoreflect.FileDescriptor, len(files))
+	for _, file := range files {
+		result[file.Path()] = file
+	}
+	return result
+}
+
+// sortedFiles returns files ordered by path
+func sortedFiles(files []protoreflect.FileDescriptor) []protoreflect.FileDescriptor {
+	sorted := append([]protoreflect.FileDescriptor(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path() < sorted[j].Path() })
+	return sorted
+}
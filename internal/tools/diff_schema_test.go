@@ -0,0 +1,242 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/snapshot"
+)
+
+func TestDiffSchemaTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewDiffSchemaTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "diff_schema" {
+		t.Fatalf("Expected tool name 'diff_schema', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestDiffSchemaTool_Handle(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test.v1;
+
+// A book
+message Book {
+  string name = 1;
+  string title = 2;
+  int32 pages = 3;
+}
+
+message Unused {}
+
+enum State {
+  STATE_UNSPECIFIED = 0;
+  STATE_DRAFT = 1;
+}
+
+service Library {
+  rpc GetBook(Book) returns (Book);
+}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	files, err := project.CompileProtos(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to compile project: %v", err)
+	}
+	if err := snapshot.Write(filepath.Join(project.ProjectRoot, "baseline.binpb"), files); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(project.ProjectRoot, "api.proto"), []byte(`syntax = "proto3";
+package test.v1;
+
+// A book in the library
+message Book {
+  string name = 1;
+  string book_title = 2;
+  int64 pages = 3 [deprecated = true];
+  repeated string tags = 4;
+}
+
+enum State {
+  STATE_UNSPECIFIED = 0;
+  STATE_DRAFT = 1;
+  STATE_PUBLISHED = 2;
+}
+
+message Shelf {
+  string name = 1;
+}
+
+service Library {
+  rpc GetBook(Book) returns (Book);
+  rpc ListShelves(Shelf) returns (stream Shelf);
+}
+`), 0o644)
+	if err != nil {
+		t.Fatalf("Failed to modify proto file: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewDiffSchemaTool(mockProjectManager)
+
+	response := callTool[DiffSchemaResponse](t, tool.Handle, "diff_schema", map[string]interface{}{"from": "baseline.binpb"})
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+
+	var got []string
+	for _, change := range response.Changes {
+		got = append(got, change.Change+" "+change.Kind+" "+change.Name)
+	}
+	// Fields of the added message are not listed separately
+	want := []string{
+		"modified message test.v1.Book",
+		"modified field test.v1.Book.book_title",
+		"modified field test.v1.Book.pages",
+		"added field test.v1.Book.tags",
+		"added method test.v1.Library.ListShelves",
+		"added enum_value test.v1.STATE_PUBLISHED",
+		"added message test.v1.Shelf",
+		"removed message test.v1.Unused",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Unexpected changes:\ngot  %q\nwant %q", got, want)
+	}
+	if *response.Summary != (DiffSummary{Added: 4, Removed: 1, Modified: 3}) {
+		t.Errorf("Unexpected summary %+v", response.Summary)
+	}
+
+	pages := response.Changes[2]
+	wantDetails := []ChangeDetail{
+		{Attribute: "type", From: "int32", To: "int64"},
+		{Attribute: "option deprecated", To: "true"},
+	}
+	if !reflect.DeepEqual(pages.Details, wantDetails) {
+		t.Errorf("Unexpected details for pages: %+v", pages.Details)
+	}
+	if renamed := response.Changes[1].Details; len(renamed) != 2 || renamed[0] != (ChangeDetail{Attribute: "name", From: "title", To: "book_title"}) {
+		t.Errorf("Expected the renamed field to keep its number, got %+v", renamed)
+	}
+	if tags := response.Changes[3]; tags.Declaration != "repeated string tags = 4;" || tags.Location == nil || tags.Location.StartLine != 9 {
+		t.Errorf("Unexpected added field %+v", tags)
+	}
+
+	for _, line := range []string{
+		"## Schema changes",
+		"### Added",
+		"- rpc `test.v1.Library.ListShelves`: `rpc ListShelves(test.v1.Shelf) returns (stream test.v1.Shelf);`",
+		"  - type: `int32` → `int64`",
+		"  - option deprecated added: `true`",
+		"  - comment changed",
+		"### Removed",
+		"- message `test.v1.Unused`",
+	} {
+		if !strings.Contains(response.Markdown, line) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", line, response.Markdown)
+		}
+	}
+
+	// Without comments, the message itself is unchanged
+	response = callTool[DiffSchemaResponse](t, tool.Handle, "diff_schema", map[string]interface{}{"from": "set:baseline.binpb", "include_comments": false})
+	if response.Summary.Modified != 2 {
+		t.Errorf("Expected 2 modified elements without comments, got %+v", response.Changes)
+	}
+}
+
+func TestDiffSchemaTool_Directories(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test.v1;
+
+message Book { string name = 1; }
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	previous := t.TempDir()
+	err = os.WriteFile(filepath.Join(previous, "api.proto"), []byte(`syntax = "proto3";
+package test.v1;
+
+message Book { string name = 1; }
+`), 0o644)
+	if err != nil {
+		t.Fatalf("Failed to write proto file: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(previous, "old.proto"), []byte(`syntax = "proto3";
+package test.v1;
+
+message Old {}
+`), 0o644)
+	if err != nil {
+		t.Fatalf("Failed to write proto file: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewDiffSchemaTool(mockProjectManager)
+
+	response := callTool[DiffSchemaResponse](t, tool.Handle, "diff_schema", map[string]interface{}{"from": "dir:" + previous})
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+	// Declarations of a removed file are not listed separately
+	if len(response.Changes) != 1 || response.Changes[0].Kind != "file" || response.Changes[0].Name != "old.proto" {
+		t.Errorf("Expected only the removed file, got %+v", response.Changes)
+	}
+
+	// Comparing a version with itself reports nothing
+	response = callTool[DiffSchemaResponse](t, tool.Handle, "diff_schema", map[string]interface{}{"from": project.ProjectRoot, "to": project.ProjectRoot})
+	if !response.Success || len(response.Changes) != 0 || !strings.Contains(response.Markdown, "0 added, 0 removed, 0 modified") {
+		t.Errorf("Expected no changes, got %+v", response)
+	}
+}
+
+func TestDiffSchemaTool_InvalidParams(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewDiffSchemaTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "diff_schema",
+			Arguments: map[string]interface{}{},
+		},
+	}
+
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	if !result.IsError {
+		t.Errorf("Expected an error result without from")
+	}
+}
+
+func TestDiffSchemaTool_NoProject(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewDiffSchemaTool(mockProjectManager)
+
+	response := callTool[DiffSchemaResponse](t, tool.Handle, "diff_schema", map[string]interface{}{"from": "HEAD"})
+	if response.Success {
+		t.Errorf("Expected success=false without an activated project")
+	}
+}
@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bufbuild/protocompile/linker"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+)
+
+// Reference kinds reported by find_references
+const (
+	referenceKindField        = "field"
+	referenceKindMapValue     = "map_value"
+	referenceKindMethodInput  = "method_input"
+	referenceKindMethodOutput = "method_output"
+	referenceKindExtension    = "extension"
+	referenceKindExtendee     = "extendee"
+)
+
+// FindReferencesTool implements the find_references MCP tool using mcp-go
+type FindReferencesTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewFindReferencesTool creates a new FindReferencesTool instance
+func NewFindReferencesTool(projectManager ProjectManagerInterface) *FindReferencesTool {
+	return &FindReferencesTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *FindReferencesTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"find_references",
+		mcp.WithDescription("Find every field, map value, RPC input/output and extension that references a message or enum"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Fully-qualified message or enum name (e.g. 'example.simple.v1.User')"),
+		),
+	)
+}
+
+// FindReferencesResponse represents the response from find_references tool
+type FindReferencesResponse struct {
+	Success       bool            `json:"success"`
+	Message       string          `json:"message"`
+	SchemaVersion string          `json:"schema_version,omitempty"`
+	Target        string          `json:"target,omitempty"`
+	References    []ReferenceInfo `json:"references,omitempty"`
+	Count         int             `json:"count"`
+}
+
+// ReferenceInfo represents a single reference to a type. Element is the
+// referencing field, extension or method and Parent the message, service or
+// file that declares it. Location points at the type reference itself.
+type ReferenceInfo struct {
+	Kind     string          `json:"kind"`
+	Element  string          `json:"element"`
+	Parent   string          `json:"parent"`
+	Location *protoutil.Span `json:"location,omitempty"`
+}
+
+// Handle handles the tool execution
+func (t *FindReferencesTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := req.GetString("name", "")
+	if name == "" {
+		return mcp.NewToolResultError("name parameter is required"), nil
+	}
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &FindReferencesResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &FindReferencesResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	target := findDescriptor(files, name)
+	switch target.(type) {
+	case protoreflect.MessageDescriptor, protoreflect.EnumDescriptor:
+	default:
+		response := &FindReferencesResponse{
+			Success: false,
+			Message: fmt.Sprintf("Type not found: %s (expected a fully-qualified message or enum name)", name),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	references := findReferences(files, target.FullName())
+
+	response := &FindReferencesResponse{
+		Success:       true,
+		Message:       fmt.Sprintf("Found %d references to %s", len(references), target.FullName()),
+		SchemaVersion: SchemaVersion,
+		Target:        string(target.FullName()),
+		References:    references,
+		Count:         len(references),
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// findReferences collects all references to the named type across the
+// compiled files, in file and declaration order
+func findReferences(files linker.Files, target protoreflect.FullName) []ReferenceInfo {
+	references := []ReferenceInfo{}
+
+	for _, file := range files {
+		forEachMessage(file, func(message protoreflect.MessageDescriptor) {
+			for i := 0; i < message.Fields().Len(); i++ {
+				field := message.Fields().Get(i)
+				kind, ref := referenceKindField, field
+				if field.IsMap() {
+					kind, ref = referenceKindMapValue, field.MapValue()
+				}
+				if typeNameOf(ref) == string(target) {
+					references = append(references, ReferenceInfo{
+						Kind:     kind,
+						Element:  string(field.FullName()),
+						Parent:   string(message.FullName()),
+						Location: subSourceSpanOf(field, fieldTypeNamePathElement),
+					})
+				}
+			}
+		})
+
+		forEachExtension(file, func(extension protoreflect.ExtensionDescriptor) {
+			parent := string(file.Package())
+			if extension.Parent() != nil {
+				parent = string(extension.Parent().FullName())
+			}
+			if extension.ContainingMessage().FullName() == target {
+				references = append(references, ReferenceInfo{
+					Kind:     referenceKindExtendee,
+					Element:  string(extension.FullName()),
+					Parent:   parent,
+					Location: subSourceSpanOf(extension, fieldExtendeePathElement),
+				})
+			}
+			if typeNameOf(extension) == string(target) {
+				references = append(references, ReferenceInfo{
+					Kind:     referenceKindExtension,
+					Element:  string(extension.FullName()),
+					Parent:   parent,
+					Location: subSourceSpanOf(extension, fieldTypeNamePathElement),
+				})
+			}
+		})
+
+		for i := 0; i < file.Services().Len(); i++ {
+			service := file.Services().Get(i)
+			for j := 0; j < service.Methods().Len(); j++ {
+				method := service.Methods().Get(j)
+				if method.Input().FullName() == target {
+					references = append(references, ReferenceInfo{
+						Kind:     referenceKindMethodInput,
+						Element:  string(method.FullName()),
+						Parent:   string(service.FullName()),
+						Location: subSourceSpanOf(method, methodInputPathElement),
+					})
+				}
+				if method.Output().FullName() == target {
+					references = append(references, ReferenceInfo{
+						Kind:     referenceKindMethodOutput,
+						Element:  string(method.FullName()),
+						Parent:   string(service.FullName()),
+						Location: subSourceSpanOf(method, methodOutputPathElement),
+					})
+				}
+			}
+		}
+	}
+
+	return references
+}
@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestFindReferencesTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewFindReferencesTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "find_references" {
+		t.Fatalf("Expected tool name 'find_references', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestFindReferencesTool_Handle(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"shop.proto": `syntax = "proto2";
+package shop;
+
+message Item {
+  optional string id = 1;
+  extensions 100 to 199;
+}
+
+message Cart {
+  repeated Item items = 1;
+  map<string, Item> by_id = 2;
+  message Line {
+    optional Item item = 1;
+  }
+}
+
+extend Item {
+  optional Item related = 100;
+}
+
+service CartService {
+  rpc AddItem(Item) returns (Cart);
+  rpc GetItem(Cart) returns (Item);
+}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewFindReferencesTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "find_references",
+			Arguments: map[string]interface{}{"name": "shop.Item"},
+		},
+	}
+
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	var response FindReferencesResponse
+	if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+		if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+	} else {
+		t.Fatalf("Expected text content in response")
+	}
+
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+
+	expected := []string{
+		"field shop.Cart.items shop.proto:10:12",
+		"map_value shop.Cart.by_id shop.proto:11:3",
+		"field shop.Cart.Line.item shop.proto:13:14",
+		"extendee shop.related shop.proto:17:8",
+		"extension shop.related shop.proto:18:12",
+		"method_input shop.CartService.AddItem shop.proto:22:15",
+		"method_output shop.CartService.GetItem shop.proto:23:30",
+	}
+	if response.Count != len(expected) {
+		t.Fatalf("Expected %d references, got %d: %+v", len(expected), response.Count, response.References)
+	}
+	for i, ref := range response.References {
+		got := fmt.Sprintf("%s %s %s:%d:%d", ref.Kind, ref.Element, ref.Location.File, ref.Location.StartLine, ref.Location.StartColumn)
+		if got != expected[i] {
+			t.Errorf("Reference %d: expected %q, got %q", i, expected[i], got)
+		}
+	}
+}
+
+func TestFindReferencesTool_Handle_NotFound(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewFindReferencesTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "find_references",
+			Arguments: map[string]interface{}{"name": "example.simple.v1.GreetingService"},
+		},
+	}
+
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	var response FindReferencesResponse
+	if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+		if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+	}
+
+	if response.Success {
+		t.Fatalf("Expected success=false for a service name")
+	}
+}
@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/format"
+	"github.com/yuemori/protobuf-mcp-server/internal/lint"
+)
+
+// FormatProtoTool implements the format_proto MCP tool using mcp-go
+type FormatProtoTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewFormatProtoTool creates a new FormatProtoTool instance
+func NewFormatProtoTool(projectManager ProjectManagerInterface) *FormatProtoTool {
+	return &FormatProtoTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *FormatProtoTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"format_proto",
+		mcp.WithDescription("Rewrite the proto files of the activated project in a canonical style: two-space indentation, one declaration per line, syntax, package, sorted imports and file options first, one option per line in multi-option brackets and message-valued options, with every comment preserved. Files are parsed, not compiled, so they need not resolve. Use check to only report the files that need formatting"),
+		mcp.WithString("files",
+			mcp.Description("Comma-separated files, directories or glob patterns relative to the project root (default: all project files)"),
+		),
+		mcp.WithBoolean("check",
+			mcp.Description("Report the files that need formatting without rewriting them"),
+		),
+	)
+}
+
+// FormatProtoResponse represents the response from format_proto tool
+type FormatProtoResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Files   []FormattedFile `json:"files,omitempty"`
+	Checked int             `json:"checked"`
+	Changed int             `json:"changed"`
+}
+
+// FormattedFile is a file that formatting changed, or failed to parse
+type FormattedFile struct {
+	File    string `json:"file"`
+	Changed bool   `json:"changed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Handle handles the tool execution
+func (t *FormatProtoTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &FormatProtoResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.SourceFiles()
+	if err != nil {
+		response := &FormatProtoResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	if patterns := splitList(req.GetString("files", "")); len(patterns) > 0 {
+		var selected []string
+		for _, file := range files {
+			if lint.MatchesAny(patterns, file) {
+				selected = append(selected, file)
+			}
+		}
+		files = selected
+	}
+
+	check := req.GetBool("check", false)
+	response := &FormatProtoResponse{
+		Success: true,
+		Checked: len(files),
+	}
+	failed := 0
+	for _, file := range files {
+		changed, err := format.File(filepath.Join(project.ProjectRoot, filepath.FromSlash(file)), !check)
+		switch {
+		case err != nil:
+			failed++
+			response.Files = append(response.Files, FormattedFile{File: file, Error: err.Error()})
+		case changed:
+			response.Changed++
+			response.Files = append(response.Files, FormattedFile{File: file, Changed: true})
+		}
+	}
+
+	switch {
+	case check && response.Changed > 0:
+		response.Message = fmt.Sprintf("%d of %d files need formatting", response.Changed, len(files))
+	case check:
+		response.Message = fmt.Sprintf("All %d files are formatted", len(files))
+	default:
+		response.Message = fmt.Sprintf("Formatted %d of %d files", response.Changed, len(files))
+	}
+	if failed > 0 {
+		response.Message += fmt.Sprintf("; %d files could not be parsed", failed)
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatProtoTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewFormatProtoTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "format_proto" {
+		t.Fatalf("Expected tool name 'format_proto', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestFormatProtoTool_Handle(t *testing.T) {
+	unformatted := `syntax = "proto3";
+package library.v1;
+import "library/v1/types.proto";
+import "google/protobuf/empty.proto";
+// A shelf
+message Shelf {
+    repeated Book books = 1; // The books
+}
+`
+	project, err := CreateTempProject(t, map[string]string{
+		"library/v1/api.proto": unformatted,
+		"library/v1/types.proto": `syntax = "proto3";
+
+package library.v1;
+
+message Book {}
+`,
+		"other/broken.proto": `syntax = "proto3";
+message Broken {
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewFormatProtoTool(mockProjectManager)
+	apiPath := filepath.Join(project.ProjectRoot, "library", "v1", "api.proto")
+
+	// Check mode reports the unformatted and broken files without writing
+	response := callTool[FormatProtoResponse](t, tool.Handle, "format_proto", map[string]interface{}{"check": true})
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+	if response.Checked != 3 || response.Changed != 1 || len(response.Files) != 2 {
+		t.Fatalf("Expected 1 of 3 files to change and 1 to fail, got %+v", response)
+	}
+	if response.Files[0].File != "library/v1/api.proto" || !response.Files[0].Changed {
+		t.Errorf("Expected api.proto to need formatting, got %+v", response.Files[0])
+	}
+	if response.Files[1].File != "other/broken.proto" || response.Files[1].Error == "" {
+		t.Errorf("Expected broken.proto to fail, got %+v", response.Files[1])
+	}
+	if content, _ := os.ReadFile(apiPath); string(content) != unformatted {
+		t.Errorf("Expected check mode to leave the file unchanged, got:\n%s", content)
+	}
+
+	// Formatting selected files rewrites them
+	response = callTool[FormatProtoResponse](t, tool.Handle, "format_proto", map[string]interface{}{"files": "library"})
+	if response.Checked != 2 || response.Changed != 1 {
+		t.Fatalf("Expected 1 of 2 files formatted, got %+v", response)
+	}
+	expected := `syntax = "proto3";
+
+package library.v1;
+
+import "google/protobuf/empty.proto";
+import "library/v1/types.proto";
+
+// A shelf
+message Shelf {
+  repeated Book books = 1; // The books
+}
+`
+	if content, _ := os.ReadFile(apiPath); string(content) != expected {
+		t.Errorf("Unexpected formatted file:\n%s", content)
+	}
+
+	response = callTool[FormatProtoResponse](t, tool.Handle, "format_proto", map[string]interface{}{"files": "library", "check": true})
+	if response.Changed != 0 || len(response.Files) != 0 {
+		t.Errorf("Expected the formatted files to be unchanged, got %+v", response)
+	}
+}
+
+func TestFormatProtoTool_NoProject(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewFormatProtoTool(mockProjectManager)
+
+	response := callTool[FormatProtoResponse](t, tool.Handle, "format_proto", map[string]interface{}{})
+	if response.Success {
+		t.Errorf("Expected success=false without an activated project")
+	}
+}
@@ -0,0 +1,484 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bufbuild/protocompile/linker"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+)
+
+// fileImportsPathElement is the FileDescriptorProto.dependency field number,
+// used to locate import statements
+const fileImportsPathElement = 3
+
+// GetDependencyGraphTool implements the get_dependency_graph MCP tool using mcp-go
+type GetDependencyGraphTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewGetDependencyGraphTool creates a new GetDependencyGraphTool instance
+func NewGetDependencyGraphTool(projectManager ProjectManagerInterface) *GetDependencyGraphTool {
+	return &GetDependencyGraphTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *GetDependencyGraphTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"get_dependency_graph",
+		mcp.WithDescription("Get the file import graph of the activated project with package-level aggregation, package cycles, unused imports and the files affected by changing a file"),
+		mcp.WithString("file",
+			mcp.Description("Report the files that directly or transitively import this file"),
+		),
+		mcp.WithBoolean("include_external",
+			mcp.Description("Include imported files outside the project (e.g. google/protobuf/*.proto) as nodes"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Additional rendering of the file graph: 'dot' or 'mermaid'"),
+		),
+	)
+}
+
+// GetDependencyGraphResponse represents the response from get_dependency_graph tool
+type GetDependencyGraphResponse struct {
+	Success   bool             `json:"success"`
+	Message   string           `json:"message"`
+	Graph     *DependencyGraph `json:"graph,omitempty"`
+	Affected  []string         `json:"affected,omitempty"`
+	Rendering string           `json:"rendering,omitempty"`
+}
+
+// DependencyGraph represents the import graph of a project
+type DependencyGraph struct {
+	Nodes         []GraphNode        `json:"nodes"`
+	Edges         []GraphEdge        `json:"edges"`
+	Packages      []PackageGraphEdge `json:"packages"`
+	PackageCycles [][]string         `json:"package_cycles"`
+	UnusedImports []UnusedImport     `json:"unused_imports"`
+}
+
+// GraphNode is a file in the import graph. External files are imported by
+// the project but not part of its configured proto files.
+type GraphNode struct {
+	Path     string `json:"path"`
+	Package  string `json:"package"`
+	External bool   `json:"external"`
+}
+
+// GraphEdge is an import from one file to another
+type GraphEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Public bool   `json:"public"`
+	Weak   bool   `json:"weak"`
+}
+
+// PackageGraphEdge aggregates the imports from files of one package to files
+// of another
+type PackageGraphEdge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Imports int    `json:"imports"`
+}
+
+// UnusedImport is an import none of whose symbols are used by the importing file
+type UnusedImport struct {
+	File     string          `json:"file"`
+	Import   string          `json:"import"`
+	Location *protoutil.Span `json:"location,omitempty"`
+}
+
+// Handle handles the tool execution
+func (t *GetDependencyGraphTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	changedFile := req.GetString("file", "")
+	includeExternal := req.GetBool("include_external", false)
+	format := req.GetString("format", "")
+	if format != "" && format != "dot" && format != "mermaid" {
+		return mcp.NewToolResultError(fmt.Sprintf("unsupported format %q: expected 'dot' or 'mermaid'", format)), nil
+	}
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &GetDependencyGraphResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &GetDependencyGraphResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	if changedFile != "" && findFile(files, changedFile) == nil {
+		response := &GetDependencyGraphResponse{
+			Success: false,
+			Message: fmt.Sprintf("File not found: %s", changedFile),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	graph := buildDependencyGraph(files, includeExternal)
+
+	response := &GetDependencyGraphResponse{
+		Success: true,
+		Message: fmt.Sprintf("Built dependency graph with %d files and %d imports", len(graph.Nodes), len(graph.Edges)),
+		Graph:   graph,
+	}
+	if changedFile != "" {
+		// Affected files are computed over the full graph so that changes to
+		// external files are traced through to project files as well
+		response.Affected = affectedFiles(buildDependencyGraph(files, true), changedFile)
+	}
+	switch format {
+	case "dot":
+		response.Rendering = renderDependencyGraphDOT(graph)
+	case "mermaid":
+		response.Rendering = renderDependencyGraphMermaid(graph)
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// buildDependencyGraph builds the import graph of the compiled files
+func buildDependencyGraph(files linker.Files, includeExternal bool) *DependencyGraph {
+	graph := &DependencyGraph{
+		Nodes:         []GraphNode{},
+		Edges:         []GraphEdge{},
+		Packages:      []PackageGraphEdge{},
+		PackageCycles: [][]string{},
+		UnusedImports: []UnusedImport{},
+	}
+
+	internal := make(map[string]bool, len(files))
+	for _, file := range files {
+		internal[file.Path()] = true
+	}
+
+	included := make(map[string]bool)
+	for _, file := range allFiles(files) {
+		if !internal[file.Path()] && !includeExternal {
+			continue
+		}
+		included[file.Path()] = true
+		graph.Nodes = append(graph.Nodes, GraphNode{
+			Path:     file.Path(),
+			Package:  string(file.Package()),
+			External: !internal[file.Path()],
+		})
+	}
+
+	packageEdges := make(map[[2]string]int)
+	for _, file := range allFiles(files) {
+		if !included[file.Path()] {
+			continue
+		}
+		for i := 0; i < file.Imports().Len(); i++ {
+			imp := file.Imports().Get(i)
+			if !included[imp.Path()] {
+				continue
+			}
+			graph.Edges = append(graph.Edges, GraphEdge{
+				From:   file.Path(),
+				To:     imp.Path(),
+				Public: imp.IsPublic,
+				Weak:   imp.IsWeak,
+			})
+			if from, to := string(file.Package()), string(imp.Package()); from != to {
+				packageEdges[[2]string{from, to}]++
+			}
+		}
+	}
+
+	for edge, count := range packageEdges {
+		graph.Packages = append(graph.Packages, PackageGraphEdge{From: edge[0], To: edge[1], Imports: count})
+	}
+	sort.Slice(graph.Packages, func(i, j int) bool {
+		if graph.Packages[i].From != graph.Packages[j].From {
+			return graph.Packages[i].From < graph.Packages[j].From
+		}
+		return graph.Packages[i].To < graph.Packages[j].To
+	})
+	graph.PackageCycles = packageCycles(graph.Packages)
+
+	// Only project files are checked; unused imports in dependencies are not actionable
+	for _, file := range files {
+		graph.UnusedImports = append(graph.UnusedImports, unusedImports(file)...)
+	}
+
+	return graph
+}
+
+// packageCycles returns the strongly connected components of the package
+// graph with more than one package, each sorted, using Tarjan's algorithm
+func packageCycles(edges []PackageGraphEdge) [][]string {
+	adjacency := make(map[string][]string)
+	var nodes []string
+	seenNode := make(map[string]bool)
+	for _, edge := range edges {
+		adjacency[edge.From] = append(adjacency[edge.From], edge.To)
+		for _, node := range []string{edge.From, edge.To} {
+			if !seenNode[node] {
+				seenNode[node] = true
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	sort.Strings(nodes)
+
+	index := 0
+	indices := make(map[string]int)
+	lowlinks := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	cycles := [][]string{}
+
+	var strongConnect func(node string)
+	strongConnect = func(node string) {
+		indices[node] = index
+		lowlinks[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range adjacency[node] {
+			if _, visited := indices[next]; !visited {
+				strongConnect(next)
+				lowlinks[node] = min(lowlinks[node], lowlinks[next])
+			} else if onStack[next] {
+				lowlinks[node] = min(lowlinks[node], indices[next])
+			}
+		}
+
+		if lowlinks[node] == indices[node] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == node {
+					break
+				}
+			}
+			if len(component) > 1 {
+				sort.Strings(component)
+				cycles = append(cycles, component)
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		if _, visited := indices[node]; !visited {
+			strongConnect(node)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
+
+// unusedImports returns the imports of a file that provide no type, extendee
+// or custom option used by the file. An import also counts as used when a
+// file it publicly re-exports is used. Public imports are never reported
+// since they exist to re-export symbols to importers.
+func unusedImports(file protoreflect.FileDescriptor) []UnusedImport {
+	used := usedFiles(file)
+
+	var unused []UnusedImport
+	for i := 0; i < file.Imports().Len(); i++ {
+		imp := file.Imports().Get(i)
+		if imp.IsPublic || providesUsedFile(imp.FileDescriptor, used, make(map[string]bool)) {
+			continue
+		}
+		unused = append(unused, UnusedImport{
+			File:     file.Path(),
+			Import:   imp.Path(),
+			Location: pathSourceSpan(file, protoreflect.SourcePath{fileImportsPathElement, int32(i)}),
+		})
+	}
+	return unused
+}
+
+// providesUsedFile checks if a file, or any file it publicly imports, is used
+func providesUsedFile(file protoreflect.FileDescriptor, used map[string]bool, seen map[string]bool) bool {
+	if seen[file.Path()] {
+		return false
+	}
+	seen[file.Path()] = true
+	if used[file.Path()] {
+		return true
+	}
+	for i := 0; i < file.Imports().Len(); i++ {
+		if imp := file.Imports().Get(i); imp.IsPublic && providesUsedFile(imp.FileDescriptor, used, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// usedFiles returns the paths of the files declaring any type, extendee or
+// custom option referenced by a file
+func usedFiles(file protoreflect.FileDescriptor) map[string]bool {
+	used := make(map[string]bool)
+	markType := func(desc protoreflect.Descriptor) {
+		if desc != nil && desc.ParentFile() != nil {
+			used[desc.ParentFile().Path()] = true
+		}
+	}
+	markField := func(field protoreflect.FieldDescriptor) {
+		if field.IsMap() {
+			field = field.MapValue()
+		}
+		if field.Message() != nil {
+			markType(field.Message())
+		}
+		if field.Enum() != nil {
+			markType(field.Enum())
+		}
+	}
+	markOptions := func(desc protoreflect.Descriptor) {
+		if options := desc.Options(); options != nil {
+			options.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+				if field.IsExtension() {
+					markType(field)
+				}
+				return true
+			})
+		}
+	}
+
+	markOptions(file)
+	forEachMessage(file, func(message protoreflect.MessageDescriptor) {
+		markOptions(message)
+		for i := 0; i < message.Fields().Len(); i++ {
+			markField(message.Fields().Get(i))
+			markOptions(message.Fields().Get(i))
+		}
+		for i := 0; i < message.Oneofs().Len(); i++ {
+			markOptions(message.Oneofs().Get(i))
+		}
+	})
+	forEachEnum(file, func(enum protoreflect.EnumDescriptor) {
+		markOptions(enum)
+		for i := 0; i < enum.Values().Len(); i++ {
+			markOptions(enum.Values().Get(i))
+		}
+	})
+	forEachExtension(file, func(extension protoreflect.ExtensionDescriptor) {
+		markField(extension)
+		markType(extension.ContainingMessage())
+		markOptions(extension)
+	})
+	for i := 0; i < file.Services().Len(); i++ {
+		service := file.Services().Get(i)
+		markOptions(service)
+		for j := 0; j < service.Methods().Len(); j++ {
+			method := service.Methods().Get(j)
+			markType(method.Input())
+			markType(method.Output())
+			markOptions(method)
+		}
+	}
+
+	return used
+}
+
+// affectedFiles returns the files that directly or transitively import the
+// given file, sorted by path
+func affectedFiles(graph *DependencyGraph, path string) []string {
+	importers := make(map[string][]string)
+	for _, edge := range graph.Edges {
+		importers[edge.To] = append(importers[edge.To], edge.From)
+	}
+
+	affected := []string{}
+	seen := map[string]bool{path: true}
+	queue := []string{path}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, importer := range importers[current] {
+			if !seen[importer] {
+				seen[importer] = true
+				affected = append(affected, importer)
+				queue = append(queue, importer)
+			}
+		}
+	}
+
+	sort.Strings(affected)
+	return affected
+}
+
+// renderDependencyGraphDOT renders the file graph in Graphviz DOT format
+func renderDependencyGraphDOT(graph *DependencyGraph) string {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, node := range graph.Nodes {
+		attrs := fmt.Sprintf("label=%q", node.Path)
+		if node.External {
+			attrs += " style=dashed"
+		}
+		fmt.Fprintf(&b, "  %q [%s];\n", node.Path, attrs)
+	}
+	for _, edge := range graph.Edges {
+		switch {
+		case edge.Public:
+			fmt.Fprintf(&b, "  %q -> %q [label=\"public\" style=bold];\n", edge.From, edge.To)
+		case edge.Weak:
+			fmt.Fprintf(&b, "  %q -> %q [label=\"weak\" style=dotted];\n", edge.From, edge.To)
+		default:
+			fmt.Fprintf(&b, "  %q -> %q;\n", edge.From, edge.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// renderDependencyGraphMermaid renders the file graph as a Mermaid flowchart
+func renderDependencyGraphMermaid(graph *DependencyGraph) string {
+	ids := make(map[string]string, len(graph.Nodes))
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for i, node := range graph.Nodes {
+		ids[node.Path] = fmt.Sprintf("f%d", i)
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[node.Path], node.Path)
+	}
+	for _, edge := range graph.Edges {
+		switch {
+		case edge.Public:
+			fmt.Fprintf(&b, "  %s ==>|public| %s\n", ids[edge.From], ids[edge.To])
+		case edge.Weak:
+			fmt.Fprintf(&b, "  %s -.->|weak| %s\n", ids[edge.From], ids[edge.To])
+		default:
+			fmt.Fprintf(&b, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+		}
+	}
+	return b.String()
+}
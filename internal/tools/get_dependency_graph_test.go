@@ -0,0 +1,224 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestGetDependencyGraphTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewGetDependencyGraphTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "get_dependency_graph" {
+		t.Fatalf("Expected tool name 'get_dependency_graph', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestGetDependencyGraphTool_Handle(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"a/two.proto": `syntax = "proto3";
+package a;
+
+message Two {}
+`,
+		"b/one.proto": `syntax = "proto3";
+package b;
+
+import "a/two.proto";
+
+message One {
+  a.Two two = 1;
+}
+`,
+		"a/one.proto": `syntax = "proto3";
+package a;
+
+import "b/one.proto";
+import "a/two.proto";
+
+message Root {
+  b.One one = 1;
+}
+`,
+		"c/reexport.proto": `syntax = "proto3";
+package c;
+
+import public "b/one.proto";
+`,
+		"d/use.proto": `syntax = "proto3";
+package d;
+
+import "c/reexport.proto";
+
+message Use {
+  b.One one = 1;
+}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewGetDependencyGraphTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_dependency_graph",
+			Arguments: map[string]interface{}{"file": "a/two.proto", "format": "dot"},
+		},
+	}
+
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	var response GetDependencyGraphResponse
+	if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+		if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+	} else {
+		t.Fatalf("Expected text content in response")
+	}
+
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+
+	if len(response.Graph.Nodes) != 5 {
+		t.Errorf("Expected 5 nodes, got %d: %+v", len(response.Graph.Nodes), response.Graph.Nodes)
+	}
+
+	var public []string
+	for _, edge := range response.Graph.Edges {
+		if edge.Public {
+			public = append(public, edge.From+"->"+edge.To)
+		}
+	}
+	if !reflect.DeepEqual(public, []string{"c/reexport.proto->b/one.proto"}) {
+		t.Errorf("Unexpected public edges: %v", public)
+	}
+
+	expectedPackages := []PackageGraphEdge{
+		{From: "a", To: "b", Imports: 1},
+		{From: "b", To: "a", Imports: 1},
+		{From: "c", To: "b", Imports: 1},
+		{From: "d", To: "c", Imports: 1},
+	}
+	if !reflect.DeepEqual(response.Graph.Packages, expectedPackages) {
+		t.Errorf("Expected package edges %+v, got %+v", expectedPackages, response.Graph.Packages)
+	}
+
+	if !reflect.DeepEqual(response.Graph.PackageCycles, [][]string{{"a", "b"}}) {
+		t.Errorf("Expected package cycle [a b], got %v", response.Graph.PackageCycles)
+	}
+
+	// d/use.proto only uses b.One, which c/reexport.proto re-exports publicly
+	if len(response.Graph.UnusedImports) != 1 {
+		t.Fatalf("Expected 1 unused import, got %+v", response.Graph.UnusedImports)
+	}
+	unused := response.Graph.UnusedImports[0]
+	if unused.File != "a/one.proto" || unused.Import != "a/two.proto" {
+		t.Errorf("Expected a/one.proto to have unused import a/two.proto, got %+v", unused)
+	}
+	if unused.Location == nil || unused.Location.StartLine != 5 {
+		t.Errorf("Expected unused import location at line 5, got %+v", unused.Location)
+	}
+
+	expectedAffected := []string{"a/one.proto", "b/one.proto", "c/reexport.proto", "d/use.proto"}
+	if !reflect.DeepEqual(response.Affected, expectedAffected) {
+		t.Errorf("Expected affected files %v, got %v", expectedAffected, response.Affected)
+	}
+
+	if !strings.Contains(response.Rendering, `"c/reexport.proto" -> "b/one.proto" [label="public" style=bold];`) {
+		t.Errorf("Expected DOT rendering to contain the public import, got:\n%s", response.Rendering)
+	}
+}
+
+func TestGetDependencyGraphTool_Handle_UsedOptionImport(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewGetDependencyGraphTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_dependency_graph",
+			Arguments: map[string]interface{}{"include_external": true, "format": "mermaid"},
+		},
+	}
+
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	var response GetDependencyGraphResponse
+	if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+		if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+	}
+
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+
+	// google/api/annotations.proto is only used through the google.api.http option
+	for _, unused := range response.Graph.UnusedImports {
+		if unused.Import == "google/api/annotations.proto" {
+			t.Errorf("Expected option import to count as used, got %+v", unused)
+		}
+	}
+
+	external := false
+	for _, node := range response.Graph.Nodes {
+		if node.Path == "google/api/http.proto" && node.External {
+			external = true
+		}
+	}
+	if !external {
+		t.Errorf("Expected google/api/http.proto as an external node, got %+v", response.Graph.Nodes)
+	}
+
+	if !strings.HasPrefix(response.Rendering, "graph LR\n") {
+		t.Errorf("Expected Mermaid flowchart, got:\n%s", response.Rendering)
+	}
+}
+
+func TestGetDependencyGraphTool_Handle_InvalidFormat(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewGetDependencyGraphTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_dependency_graph",
+			Arguments: map[string]interface{}{"format": "svg"},
+		},
+	}
+
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("Expected error result for unsupported format")
+	}
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GetFileTool implements the get_file MCP tool using mcp-go
+type GetFileTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewGetFileTool creates a new GetFileTool instance
+func NewGetFileTool(projectManager ProjectManagerInterface) *GetFileTool {
+	return &GetFileTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *GetFileTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"get_file",
+		mcp.WithDescription("Get file-level metadata for a proto file: package, syntax, imports, options and top-level declarations"),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Path of the proto file as it is imported (e.g. 'api.proto' or 'google/api/http.proto')"),
+		),
+	)
+}
+
+// GetFileResponse represents the response from get_file tool
+type GetFileResponse struct {
+	Success       bool          `json:"success"`
+	Message       string        `json:"message"`
+	SchemaVersion string        `json:"schema_version,omitempty"`
+	File          *FileInfo     `json:"file,omitempty"`
+	Declarations  *Declarations `json:"declarations,omitempty"`
+}
+
+// Declarations lists the full names of the top-level declarations of a file
+type Declarations struct {
+	Messages   []string `json:"messages"`
+	Enums      []string `json:"enums"`
+	Services   []string `json:"services"`
+	Extensions []string `json:"extensions"`
+}
+
+// Handle handles the tool execution
+func (t *GetFileTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := req.GetString("path", "")
+	if path == "" {
+		return mcp.NewToolResultError("path parameter is required"), nil
+	}
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &GetFileResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &GetFileResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	file := findFile(files, path)
+	if file == nil {
+		response := &GetFileResponse{
+			Success: false,
+			Message: fmt.Sprintf("File not found: %s", path),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	fileInfo := newFileInfo(file)
+	response := &GetFileResponse{
+		Success:       true,
+		Message:       fmt.Sprintf("Retrieved file information for %s", file.Path()),
+		SchemaVersion: SchemaVersion,
+		File:          &fileInfo,
+		Declarations:  newDeclarations(file),
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// newDeclarations collects the top-level declaration names of a file
+func newDeclarations(file protoreflect.FileDescriptor) *Declarations {
+	declarations := &Declarations{
+		Messages:   []string{},
+		Enums:      []string{},
+		Services:   []string{},
+		Extensions: []string{},
+	}
+	for i := 0; i < file.Messages().Len(); i++ {
+		declarations.Messages = append(declarations.Messages, string(file.Messages().Get(i).FullName()))
+	}
+	for i := 0; i < file.Enums().Len(); i++ {
+		declarations.Enums = append(declarations.Enums, string(file.Enums().Get(i).FullName()))
+	}
+	for i := 0; i < file.Services().Len(); i++ {
+		declarations.Services = append(declarations.Services, string(file.Services().Get(i).FullName()))
+	}
+	for i := 0; i < file.Extensions().Len(); i++ {
+		declarations.Extensions = append(declarations.Extensions, string(file.Extensions().Get(i).FullName()))
+	}
+	return declarations
+}
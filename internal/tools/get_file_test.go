@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestGetFileTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewGetFileTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "get_file" {
+		t.Fatalf("Expected tool name 'get_file', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestGetFileTool_Handle_MissingPath(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewGetFileTool(mockProjectManager)
+
+	result, err := tool.Handle(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	if !result.IsError {
+		t.Fatalf("Expected error, got success")
+	}
+}
+
+func TestGetFileTool_Handle(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewGetFileTool(mockProjectManager)
+
+	callGetFile := func(path string) GetFileResponse {
+		req := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "get_file",
+				Arguments: map[string]interface{}{"path": path},
+			},
+		}
+
+		result, err := tool.Handle(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+
+		var response GetFileResponse
+		if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+			if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+		} else {
+			t.Fatalf("Expected text content in response")
+		}
+		return response
+	}
+
+	response := callGetFile("types.proto")
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+
+	if response.File.Path != "types.proto" {
+		t.Fatalf("Expected types.proto, got %s", response.File.Path)
+	}
+	if response.File.EnumCount != len(response.Declarations.Enums) || response.File.EnumCount != 5 {
+		t.Fatalf("Expected 5 enums, got count=%d declarations=%v", response.File.EnumCount, response.Declarations.Enums)
+	}
+	if response.Declarations.Messages[0] != "example.simple.v1.User" {
+		t.Fatalf("Expected first message example.simple.v1.User, got %s", response.Declarations.Messages[0])
+	}
+
+	response = callGetFile("missing.proto")
+	if response.Success {
+		t.Fatalf("Expected success=false for missing file")
+	}
+	if !strings.Contains(response.Message, "File not found") {
+		t.Fatalf("Expected file not found message, got: %s", response.Message)
+	}
+}
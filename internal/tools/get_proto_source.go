@@ -0,0 +1,243 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GetProtoSourceTool implements the get_proto_source MCP tool using mcp-go
+type GetProtoSourceTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewGetProtoSourceTool creates a new GetProtoSourceTool instance
+func NewGetProtoSourceTool(projectManager ProjectManagerInterface) *GetProtoSourceTool {
+	return &GetProtoSourceTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *GetProtoSourceTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"get_proto_source",
+		mcp.WithDescription("Render a message, service, enum or whole file as formatted .proto source reconstructed from the compiled descriptors, with comments, options and reserved ranges; a compact alternative to get_schema"),
+		mcp.WithString("name",
+			mcp.Description("Fully-qualified message, service or enum name (e.g. 'example.simple.v1.User')"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Proto file path to render as a whole (e.g. 'api.proto'); used when name is not given"),
+		),
+		mcp.WithBoolean("include_references",
+			mcp.Description("Also render the messages and enums the element references, transitively"),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description("Maximum number of reference hops to follow with include_references (unlimited when omitted)"),
+		),
+		mcp.WithBoolean("include_comments",
+			mcp.Description("Include comments from the source files (default true)"),
+		),
+	)
+}
+
+// GetProtoSourceResponse represents the response from get_proto_source tool
+type GetProtoSourceResponse struct {
+	Success   bool     `json:"success"`
+	Message   string   `json:"message"`
+	Source    string   `json:"source,omitempty"`
+	Types     []string `json:"types,omitempty"`
+	Truncated bool     `json:"truncated"`
+}
+
+// Handle handles the tool execution
+func (t *GetProtoSourceTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := req.GetString("name", "")
+	path := req.GetString("path", "")
+	if name == "" && path == "" {
+		return mcp.NewToolResultError("name or path parameter is required"), nil
+	}
+	includeReferences := req.GetBool("include_references", false)
+	depth := req.GetInt("depth", -1)
+	printer := protoutil.Printer{OmitComments: !req.GetBool("include_comments", true)}
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &GetProtoSourceResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &GetProtoSourceResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	var root protoreflect.Descriptor
+	if name != "" {
+		switch desc := findDescriptor(files, name).(type) {
+		case protoreflect.MessageDescriptor, protoreflect.EnumDescriptor, protoreflect.ServiceDescriptor:
+			root = desc
+		}
+		if root == nil {
+			response := &GetProtoSourceResponse{
+				Success: false,
+				Message: fmt.Sprintf("Type not found: %s (expected a fully-qualified message, service or enum name)", name),
+			}
+			responseJSON, _ := json.Marshal(response)
+			return mcp.NewToolResultText(string(responseJSON)), nil
+		}
+	} else if file := findFile(files, path); file != nil {
+		root = file
+	} else {
+		response := &GetProtoSourceResponse{
+			Success: false,
+			Message: fmt.Sprintf("File not found: %s", path),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	var source strings.Builder
+	source.WriteString(printer.Print(root))
+	types := []string{rootName(root)}
+
+	truncated := false
+	if includeReferences && depth != 0 {
+		var referenced []protoreflect.Descriptor
+		referenced, truncated = referencedDeclarations(root, depth)
+		for _, desc := range referenced {
+			fmt.Fprintf(&source, "\n// ---- %s (%s) ----\n", desc.FullName(), desc.ParentFile().Path())
+			source.WriteString(printer.Print(desc))
+			types = append(types, string(desc.FullName()))
+		}
+	}
+
+	response := &GetProtoSourceResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("Rendered %s with %d referenced types", types[0], len(types)-1),
+		Source:    source.String(),
+		Types:     types,
+		Truncated: truncated,
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// rootName returns the full name of a declaration, or the path of a file
+func rootName(desc protoreflect.Descriptor) string {
+	if file, ok := desc.(protoreflect.FileDescriptor); ok {
+		return file.Path()
+	}
+	return string(desc.FullName())
+}
+
+// referencedDeclarations returns the messages and enums a declaration
+// references transitively that are not already printed as part of it. Types
+// nested in another returned type are left out since they are printed with
+// their parent. The depth counts reference hops from the declaration.
+func referencedDeclarations(root protoreflect.Descriptor, depth int) ([]protoreflect.Descriptor, bool) {
+	var roots []protoreflect.Descriptor
+	addMethods := func(service protoreflect.ServiceDescriptor) {
+		for i := 0; i < service.Methods().Len(); i++ {
+			method := service.Methods().Get(i)
+			roots = append(roots, method.Input(), method.Output())
+		}
+	}
+
+	// Messages, including every message of a file, are hop 0; for services
+	// the first hop is the RPC inputs and outputs
+	closureDepth := depth
+	switch d := root.(type) {
+	case protoreflect.MessageDescriptor:
+		roots = append(roots, d)
+	case protoreflect.ServiceDescriptor:
+		addMethods(d)
+		closureDepth--
+	case protoreflect.FileDescriptor:
+		forEachMessage(d, func(message protoreflect.MessageDescriptor) {
+			roots = append(roots, message)
+		})
+		for i := 0; i < d.Services().Len(); i++ {
+			addMethods(d.Services().Get(i))
+		}
+	default:
+		return nil, false
+	}
+	if depth < 0 {
+		closureDepth = -1
+	}
+
+	messages, enums, truncated := typeClosure(roots, closureDepth)
+	closure := make([]protoreflect.Descriptor, 0, len(messages)+len(enums))
+	for _, message := range messages {
+		closure = append(closure, message)
+	}
+	for _, enum := range enums {
+		closure = append(closure, enum)
+	}
+
+	printed := map[protoreflect.FullName]bool{}
+	if file, ok := root.(protoreflect.FileDescriptor); ok {
+		for _, desc := range closure {
+			if desc.ParentFile().Path() == file.Path() {
+				printed[desc.FullName()] = true
+			}
+		}
+	} else {
+		printed[root.FullName()] = true
+	}
+
+	var result []protoreflect.Descriptor
+	for _, desc := range closure {
+		if !printedWithin(desc, printed) {
+			result = append(result, desc)
+		}
+	}
+
+	// Drop types nested in other returned types
+	returned := make(map[protoreflect.FullName]bool, len(result))
+	for _, desc := range result {
+		returned[desc.FullName()] = true
+	}
+	filtered := result[:0]
+	for _, desc := range result {
+		if parent, ok := desc.Parent().(protoreflect.MessageDescriptor); ok && printedWithin(parent, returned) {
+			continue
+		}
+		filtered = append(filtered, desc)
+	}
+	return filtered, truncated
+}
+
+// printedWithin checks if a declaration or one of its enclosing messages is in a set
+func printedWithin(desc protoreflect.Descriptor, set map[protoreflect.FullName]bool) bool {
+	for d := desc; d != nil; d = d.Parent() {
+		if _, ok := d.(protoreflect.FileDescriptor); ok {
+			return false
+		}
+		if set[d.FullName()] {
+			return true
+		}
+	}
+	return false
+}
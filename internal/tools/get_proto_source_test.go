@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetProtoSourceTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewGetProtoSourceTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "get_proto_source" {
+		t.Fatalf("Expected tool name 'get_proto_source', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestGetProtoSourceTool_Handle(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"shop/v1/shop.proto": `syntax = "proto3";
+
+package shop.v1;
+
+import "common/money.proto";
+
+// A cart of items
+message Cart {
+  reserved 4;
+
+  repeated Item items = 1;
+  common.Money total = 2; // Sum of item prices
+
+  message Item {
+    string id = 1;
+    common.Money price = 2;
+  }
+}
+
+service CartService {
+  rpc GetCart(Cart.Item) returns (Cart);
+}
+`,
+		"common/money.proto": `syntax = "proto3";
+
+package common;
+
+message Money {
+  int64 units = 1;
+  Currency currency = 2;
+}
+
+enum Currency {
+  CURRENCY_UNSPECIFIED = 0;
+}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewGetProtoSourceTool(mockProjectManager)
+
+	response := callTool[GetProtoSourceResponse](t, tool.Handle, "get_proto_source", map[string]interface{}{"name": "shop.v1.Cart"})
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+	expected := `// A cart of items
+message Cart {
+  reserved 4;
+
+  repeated Item items = 1;
+  common.Money total = 2; // Sum of item prices
+
+  message Item {
+    string id = 1;
+    common.Money price = 2;
+  }
+}
+`
+	if response.Source != expected {
+		t.Errorf("Expected source:\n%s\ngot:\n%s", expected, response.Source)
+	}
+
+	response = callTool[GetProtoSourceResponse](t, tool.Handle, "get_proto_source", map[string]interface{}{
+		"name":               "shop.v1.CartService",
+		"include_references": true,
+		"include_comments":   false,
+	})
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+	expectedTypes := []string{"shop.v1.CartService", "shop.v1.Cart", "common.Money", "common.Currency"}
+	if !reflect.DeepEqual(response.Types, expectedTypes) {
+		t.Errorf("Expected types %v, got %v", expectedTypes, response.Types)
+	}
+	if strings.Contains(response.Source, "Sum of item prices") {
+		t.Errorf("Expected comments to be omitted, got:\n%s", response.Source)
+	}
+	if !strings.Contains(response.Source, "// ---- common.Money (common/money.proto) ----\nmessage Money {\n  int64 units = 1;\n  Currency currency = 2;\n}\n") {
+		t.Errorf("Expected referenced Money message, got:\n%s", response.Source)
+	}
+
+	response = callTool[GetProtoSourceResponse](t, tool.Handle, "get_proto_source", map[string]interface{}{
+		"path":               "shop/v1/shop.proto",
+		"include_references": true,
+		"depth":              1,
+	})
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+	if !reflect.DeepEqual(response.Types, []string{"shop/v1/shop.proto", "common.Money"}) || !response.Truncated {
+		t.Errorf("Expected the file with Money at depth 1 and truncated=true, got %v (truncated=%v)", response.Types, response.Truncated)
+	}
+	if !strings.HasPrefix(response.Source, "syntax = \"proto3\";\n\npackage shop.v1;\n\nimport \"common/money.proto\";\n") {
+		t.Errorf("Expected file header, got:\n%s", response.Source)
+	}
+}
+
+func TestGetProtoSourceTool_Handle_NotFound(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewGetProtoSourceTool(mockProjectManager)
+
+	response := callTool[GetProtoSourceResponse](t, tool.Handle, "get_proto_source", map[string]interface{}{"name": "example.simple.v1.Missing"})
+	if response.Success {
+		t.Fatalf("Expected success=false for a missing type")
+	}
+}
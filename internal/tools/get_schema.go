@@ -2,14 +2,15 @@ package tools
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/bufbuild/protocompile/linker"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/yuemori/protobuf-mcp-server/internal/compiler"
-	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // GetSchemaTool implements the get_schema MCP tool using mcp-go
@@ -35,6 +36,27 @@ func (t *GetSchemaTool) GetTool() mcp.Tool {
 		mcp.WithString("type",
 			mcp.Description("Filter by type: 'service', 'enum', or 'message'"),
 		),
+		mcp.WithString("package",
+			mcp.Description("Filter by package name or glob (e.g. 'example.simple.v1' or 'example.*')"),
+		),
+		mcp.WithBoolean("include_detached_comments",
+			mcp.Description("Include detached comments (separated by a blank line) in descriptions"),
+		),
+		mcp.WithNumber("page_size",
+			mcp.Description("Maximum number of messages, services and enums to return per page (all when omitted)"),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("Cursor from a previous response's next_cursor to fetch the next page"),
+		),
+		mcp.WithString("fields",
+			mcp.Description("Comma-separated JSON keys to keep for each element, with dots for nested lists (e.g. 'name,full_name,fields.name'); prefix a key with '-' to drop it at any depth (e.g. '-description,-location')"),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description("Maximum size of the response in bytes; elements beyond the budget are left for the next page and the response is marked truncated. An element that does not fit on its own is reduced to its names and listed in truncated_elements"),
+		),
+		mcp.WithBoolean("summary",
+			mcp.Description("Return only element counts and full names instead of full definitions"),
+		),
 	)
 }
 
@@ -44,14 +66,47 @@ type GetSchemaParams struct {
 	Name string `json:"name,omitempty"`
 	// Type filter: "service", "enum", or "message"
 	Type string `json:"type,omitempty"`
+	// Package filter: exact package name or glob
+	Package string `json:"package,omitempty"`
+	// IncludeDetachedComments adds leading detached comments to descriptions
+	IncludeDetachedComments bool `json:"include_detached_comments,omitempty"`
+	// PageSize limits the number of elements per page; 0 returns all
+	PageSize int `json:"page_size,omitempty"`
+	// Cursor resumes from a previous page
+	Cursor string `json:"cursor,omitempty"`
+	// Fields projects each element onto the listed JSON keys
+	Fields string `json:"fields,omitempty"`
+	// MaxBytes limits the size of the response; 0 means unlimited
+	MaxBytes int `json:"max_bytes,omitempty"`
+	// Summary returns only counts and names
+	Summary bool `json:"summary,omitempty"`
 }
 
 // GetSchemaResponse represents the response for get_schema tool
 type GetSchemaResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Schema  *SchemaInfo `json:"schema,omitempty"`
-	Count   int         `json:"count"`
+	Success       bool           `json:"success"`
+	Message       string         `json:"message"`
+	SchemaVersion string         `json:"schema_version,omitempty"`
+	Schema        *SchemaInfo    `json:"schema,omitempty"`
+	Summary       *SchemaSummary `json:"summary,omitempty"`
+	Count         int            `json:"count"`
+	Total         int            `json:"total"`
+	NextCursor    string         `json:"next_cursor,omitempty"`
+	Truncated     bool           `json:"truncated,omitempty"`
+	// TruncatedElements lists the full names of elements reduced to their
+	// names because they alone exceed max_bytes
+	TruncatedElements []string `json:"truncated_elements,omitempty"`
+}
+
+// SchemaSummary lists the full names of all matching elements without
+// their definitions
+type SchemaSummary struct {
+	MessageCount int      `json:"message_count"`
+	ServiceCount int      `json:"service_count"`
+	EnumCount    int      `json:"enum_count"`
+	Messages     []string `json:"messages"`
+	Services     []string `json:"services"`
+	Enums        []string `json:"enums"`
 }
 
 // SchemaInfo represents detailed schema information
@@ -61,57 +116,24 @@ type SchemaInfo struct {
 	Enums    []EnumInfo    `json:"enums"`
 }
 
-// MessageInfo represents detailed information about a protobuf message
-type MessageInfo struct {
-	Name        string       `json:"name"`
-	FullName    string       `json:"full_name"`
-	Fields      []FieldInfo  `json:"fields"`
-	File        string       `json:"file"`
-	Package     string       `json:"package"`
-	Description string       `json:"description"`
-	Options     []OptionInfo `json:"options,omitempty"`
-}
-
-// FieldInfo represents information about a message field
-type FieldInfo struct {
-	Name        string       `json:"name"`
-	Number      int32        `json:"number"`
-	Type        string       `json:"type"`
-	Optional    bool         `json:"optional"`
-	Repeated    bool         `json:"repeated"`
-	Description string       `json:"description"`
-	Options     []OptionInfo `json:"options,omitempty"`
-}
-
-// ServiceInfo and MethodInfo are defined in types.go
-
-// EnumInfo represents detailed information about a protobuf enum
-type EnumInfo struct {
-	Name        string          `json:"name"`
-	FullName    string          `json:"full_name"`
-	Values      []EnumValueInfo `json:"values"`
-	File        string          `json:"file"`
-	Package     string          `json:"package"`
-	Description string          `json:"description"`
-	Options     []OptionInfo    `json:"options,omitempty"`
-}
-
-// EnumValueInfo represents information about an enum value
-type EnumValueInfo struct {
-	Name        string       `json:"name"`
-	Number      int32        `json:"number"`
-	Description string       `json:"description"`
-	Options     []OptionInfo `json:"options,omitempty"`
-}
-
-// OptionInfo is defined in types.go
-
 // Handle handles the tool execution
 func (t *GetSchemaTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// Parse parameters
 	var params GetSchemaParams
 	params.Name = req.GetString("name", "")
 	params.Type = req.GetString("type", "")
+	params.Package = req.GetString("package", "")
+	params.IncludeDetachedComments = req.GetBool("include_detached_comments", false)
+	params.PageSize = req.GetInt("page_size", 0)
+	params.Cursor = req.GetString("cursor", "")
+	params.Fields = req.GetString("fields", "")
+	params.MaxBytes = req.GetInt("max_bytes", 0)
+	params.Summary = req.GetBool("summary", false)
+
+	projection, err := parseSchemaProjection(params.Fields)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid fields: %v", err)), nil
+	}
 
 	// Get current project
 	project := t.projectManager.GetProject()
@@ -145,23 +167,274 @@ func (t *GetSchemaTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*m
 		return mcp.NewToolResultText(string(responseJSON)), nil
 	}
 
-	// Calculate count
-	count := len(schemaInfo.Messages) + len(schemaInfo.Services) + len(schemaInfo.Enums)
+	total := len(schemaInfo.Messages) + len(schemaInfo.Services) + len(schemaInfo.Enums)
+
+	if params.Summary {
+		response := &GetSchemaResponse{
+			Success: true,
+			Message: fmt.Sprintf("Retrieved schema summary: %d messages, %d services, %d enums",
+				len(schemaInfo.Messages), len(schemaInfo.Services), len(schemaInfo.Enums)),
+			SchemaVersion: SchemaVersion,
+			Summary:       summarizeSchema(schemaInfo),
+			Count:         total,
+			Total:         total,
+		}
+		responseJSON, err := json.Marshal(response)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	offset, err := decodeSchemaCursor(params.Cursor)
+	if err != nil || offset > total {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid cursor %q", params.Cursor)), nil
+	}
+	end := total
+	if params.PageSize > 0 && offset+params.PageSize < total {
+		end = offset + params.PageSize
+	}
 
+	// Shrink the page until it fits the byte budget, always keeping at least
+	// one element so that paging makes progress. Each step cuts the page in
+	// proportion to the overshoot, so large pages converge in a few renders.
+	var responseText string
+	for {
+		responseText, err = renderSchemaPage(schemaInfo, offset, end, total, projection, false)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+		}
+		if params.MaxBytes <= 0 || len(responseText) <= params.MaxBytes || end-offset <= 1 {
+			break
+		}
+		shrunk := offset + (end-offset)*params.MaxBytes/len(responseText)
+		end = max(offset+1, min(shrunk, end-1))
+	}
+
+	// A single element over the budget is reduced to the names of itself and
+	// its members, or to its own names when even those do not fit
+	for _, fallback := range schemaNameProjections {
+		if params.MaxBytes <= 0 || len(responseText) <= params.MaxBytes {
+			break
+		}
+		responseText, err = renderSchemaPage(schemaInfo, offset, end, total, fallback, true)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+		}
+	}
+
+	return mcp.NewToolResultText(responseText), nil
+}
+
+// schemaNameProjections are the projections tried in turn for an element
+// that exceeds max_bytes on its own
+var schemaNameProjections = []*schemaProjection{
+	mustParseSchemaProjection("name,full_name,fields.name,methods.name,values.name,nested_messages.name,nested_enums.name"),
+	mustParseSchemaProjection("name,full_name"),
+}
+
+// mustParseSchemaProjection parses a fields parameter known to be valid
+func mustParseSchemaProjection(spec string) *schemaProjection {
+	projection, err := parseSchemaProjection(spec)
+	if err != nil {
+		panic(err)
+	}
+	return projection
+}
+
+// renderSchemaPage renders the response for elements [start, end) of the
+// schema, counting messages first, then services, then enums. Reduced pages
+// list their elements in truncated_elements.
+func renderSchemaPage(schemaInfo *SchemaInfo, start, end, total int, projection *schemaProjection, reduced bool) (string, error) {
+	page := pageSchemaInfo(schemaInfo, start, end)
 	response := &GetSchemaResponse{
 		Success: true,
 		Message: fmt.Sprintf("Retrieved schema information: %d messages, %d services, %d enums",
-			len(schemaInfo.Messages), len(schemaInfo.Services), len(schemaInfo.Enums)),
-		Schema: schemaInfo,
-		Count:  count,
+			len(page.Messages), len(page.Services), len(page.Enums)),
+		SchemaVersion: SchemaVersion,
+		Schema:        page,
+		Count:         end - start,
+		Total:         total,
+	}
+	if end < total {
+		response.NextCursor = encodeSchemaCursor(end)
+		response.Truncated = true
+	}
+	if reduced {
+		response.Truncated = true
+		response.TruncatedElements = summarizeSchema(page).fullNames()
 	}
 
 	responseJSON, err := json.Marshal(response)
+	if err != nil || projection == nil {
+		return string(responseJSON), err
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(responseJSON, &decoded); err != nil {
+		return "", err
+	}
+	if schema, ok := decoded["schema"].(map[string]interface{}); ok {
+		for key, elements := range schema {
+			schema[key] = projection.apply(elements)
+		}
+	}
+	responseJSON, err = json.Marshal(decoded)
+	return string(responseJSON), err
+}
+
+// pageSchemaInfo returns the elements [start, end) of a schema, counting
+// messages first, then services, then enums
+func pageSchemaInfo(schemaInfo *SchemaInfo, start, end int) *SchemaInfo {
+	window := func(length int) (int, int) {
+		from, to := min(max(start, 0), length), min(max(end, 0), length)
+		start, end = start-length, end-length
+		return from, to
+	}
+
+	page := &SchemaInfo{}
+	from, to := window(len(schemaInfo.Messages))
+	page.Messages = schemaInfo.Messages[from:to]
+	from, to = window(len(schemaInfo.Services))
+	page.Services = schemaInfo.Services[from:to]
+	from, to = window(len(schemaInfo.Enums))
+	page.Enums = schemaInfo.Enums[from:to]
+	return page
+}
+
+// summarizeSchema lists the full names of the elements of a schema
+func summarizeSchema(schemaInfo *SchemaInfo) *SchemaSummary {
+	summary := &SchemaSummary{
+		MessageCount: len(schemaInfo.Messages),
+		ServiceCount: len(schemaInfo.Services),
+		EnumCount:    len(schemaInfo.Enums),
+		Messages:     make([]string, 0, len(schemaInfo.Messages)),
+		Services:     make([]string, 0, len(schemaInfo.Services)),
+		Enums:        make([]string, 0, len(schemaInfo.Enums)),
+	}
+	for _, message := range schemaInfo.Messages {
+		summary.Messages = append(summary.Messages, message.FullName)
+	}
+	for _, service := range schemaInfo.Services {
+		summary.Services = append(summary.Services, service.FullName)
+	}
+	for _, enum := range schemaInfo.Enums {
+		summary.Enums = append(summary.Enums, enum.FullName)
+	}
+	return summary
+}
+
+// fullNames returns the full names of all elements of a summary, messages
+// first, then services, then enums
+func (s *SchemaSummary) fullNames() []string {
+	names := append([]string{}, s.Messages...)
+	names = append(names, s.Services...)
+	return append(names, s.Enums...)
+}
+
+// schemaCursorPrefix versions the cursor format so it can change without
+// silently misreading old cursors
+const schemaCursorPrefix = "v1:"
+
+// encodeSchemaCursor encodes an element offset as an opaque cursor
+func encodeSchemaCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(schemaCursorPrefix + strconv.Itoa(offset)))
+}
+
+// decodeSchemaCursor decodes a cursor into an element offset; an empty cursor is offset 0
+func decodeSchemaCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+		return 0, err
+	}
+	text, ok := strings.CutPrefix(string(data), schemaCursorPrefix)
+	if !ok {
+		return 0, fmt.Errorf("unsupported cursor version")
+	}
+	offset, err := strconv.Atoi(text)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor offset")
 	}
+	return offset, nil
+}
+
+// schemaProjection selects the JSON keys kept for each schema element. A nil
+// include keeps every key; excluded keys are dropped at any depth.
+type schemaProjection struct {
+	include map[string]*schemaProjection
+	exclude map[string]bool
+}
+
+// parseSchemaProjection parses a comma-separated fields parameter, returning
+// nil when it is empty
+func parseSchemaProjection(spec string) (*schemaProjection, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	root := &schemaProjection{exclude: make(map[string]bool)}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if key, ok := strings.CutPrefix(entry, "-"); ok {
+			if key == "" || strings.Contains(key, ".") {
+				return nil, fmt.Errorf("invalid excluded field %q: expected a single key", entry)
+			}
+			root.exclude[key] = true
+			continue
+		}
 
-	return mcp.NewToolResultText(string(responseJSON)), nil
+		node := root
+		for _, key := range strings.Split(entry, ".") {
+			if key == "" {
+				return nil, fmt.Errorf("invalid field %q", entry)
+			}
+			if node.include == nil {
+				node.include = make(map[string]*schemaProjection)
+			}
+			child, ok := node.include[key]
+			if !ok {
+				child = &schemaProjection{exclude: root.exclude}
+				node.include[key] = child
+			}
+			node = child
+		}
+	}
+	return root, nil
+}
+
+// apply projects a decoded JSON value
+func (p *schemaProjection) apply(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		projected := make([]interface{}, len(v))
+		for i, element := range v {
+			projected[i] = p.apply(element)
+		}
+		return projected
+	case map[string]interface{}:
+		projected := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			if p.exclude[key] {
+				continue
+			}
+			if p.include == nil {
+				projected[key] = p.apply(element)
+				continue
+			}
+			if child, ok := p.include[key]; ok {
+				projected[key] = child.apply(element)
+			}
+		}
+		return projected
+	default:
+		return value
+	}
 }
 
 // matchesName checks if a name matches the search criteria
@@ -189,15 +462,20 @@ func (t *GetSchemaTool) buildSchemaInfo(project *compiler.ProtobufProject, files
 		Services: []ServiceInfo{},
 		Enums:    []EnumInfo{},
 	}
+	opts := convertOptions{IncludeDetachedComments: params.IncludeDetachedComments}
 
 	// Process each file in the compiled protos
 	for _, file := range files {
+		if !matchesPackage(string(file.Package()), params.Package) {
+			continue
+		}
+
 		// Process messages
 		if t.matchesType("message", params.Type) {
 			for i := 0; i < file.Messages().Len(); i++ {
 				message := file.Messages().Get(i)
 				if t.matchesName(string(message.Name()), string(message.FullName()), params.Name) {
-					messageInfo := t.convertMessageToInfo(message)
+					messageInfo := newMessageInfo(message, opts)
 					schemaInfo.Messages = append(schemaInfo.Messages, messageInfo)
 				}
 			}
@@ -208,7 +486,7 @@ func (t *GetSchemaTool) buildSchemaInfo(project *compiler.ProtobufProject, files
 			for i := 0; i < file.Services().Len(); i++ {
 				service := file.Services().Get(i)
 				if t.matchesName(string(service.Name()), string(service.FullName()), params.Name) {
-					serviceInfo := t.convertServiceToInfo(service)
+					serviceInfo := newServiceInfo(service, opts)
 					schemaInfo.Services = append(schemaInfo.Services, serviceInfo)
 				}
 			}
@@ -219,7 +497,7 @@ func (t *GetSchemaTool) buildSchemaInfo(project *compiler.ProtobufProject, files
 			for i := 0; i < file.Enums().Len(); i++ {
 				enum := file.Enums().Get(i)
 				if t.matchesName(string(enum.Name()), string(enum.FullName()), params.Name) {
-					enumInfo := t.convertEnumToInfo(enum)
+					enumInfo := newEnumInfo(enum, opts)
 					schemaInfo.Enums = append(schemaInfo.Enums, enumInfo)
 				}
 			}
@@ -228,87 +506,3 @@ func (t *GetSchemaTool) buildSchemaInfo(project *compiler.ProtobufProject, files
 
 	return schemaInfo, nil
 }
-
-// convertMessageToInfo converts a protobuf message to MessageInfo
-func (t *GetSchemaTool) convertMessageToInfo(message protoreflect.MessageDescriptor) MessageInfo {
-	// Convert fields
-	fields := make([]FieldInfo, 0, message.Fields().Len())
-	for i := 0; i < message.Fields().Len(); i++ {
-		field := message.Fields().Get(i)
-		fieldInfo := FieldInfo{
-			Name:        string(field.Name()),
-			Number:      int32(field.Number()),
-			Type:        field.Kind().String(),
-			Optional:    field.HasPresence(),
-			Repeated:    field.Cardinality() == protoreflect.Repeated,
-			Description: strings.TrimSpace(message.ParentFile().SourceLocations().ByDescriptor(field).LeadingComments),
-			Options:     []OptionInfo{}, // TODO: Extract field options
-		}
-		fields = append(fields, fieldInfo)
-	}
-
-	return MessageInfo{
-		Name:        string(message.Name()),
-		FullName:    string(message.FullName()),
-		Fields:      fields,
-		File:        string(message.ParentFile().Path()),
-		Package:     string(message.ParentFile().Package()),
-		Description: strings.TrimSpace(message.ParentFile().SourceLocations().ByDescriptor(message).LeadingComments),
-		Options:     []OptionInfo{}, // TODO: Extract message options
-	}
-}
-
-// convertServiceToInfo converts a protobuf service to ServiceInfo
-func (t *GetSchemaTool) convertServiceToInfo(service protoreflect.ServiceDescriptor) ServiceInfo {
-	// Convert methods
-	methods := make([]MethodInfo, 0, service.Methods().Len())
-	for i := 0; i < service.Methods().Len(); i++ {
-		method := service.Methods().Get(i)
-		methodInfo := MethodInfo{
-			Name:            string(method.Name()),
-			InputType:       string(method.Input().FullName()),
-			OutputType:      string(method.Output().FullName()),
-			ClientStreaming: method.IsStreamingClient(),
-			ServerStreaming: method.IsStreamingServer(),
-			Description:     strings.TrimSpace(service.ParentFile().SourceLocations().ByDescriptor(method).LeadingComments),
-			Options:         []OptionInfo{}, // TODO: Extract method options
-		}
-		methods = append(methods, methodInfo)
-	}
-
-	return ServiceInfo{
-		Name:        string(service.Name()),
-		FullName:    string(service.FullName()),
-		Methods:     methods,
-		File:        string(service.ParentFile().Path()),
-		Package:     string(service.ParentFile().Package()),
-		Description: strings.TrimSpace(service.ParentFile().SourceLocations().ByDescriptor(service).LeadingComments),
-		Options:     []OptionInfo{}, // TODO: Extract service options
-	}
-}
-
-// convertEnumToInfo converts a protobuf enum to EnumInfo
-func (t *GetSchemaTool) convertEnumToInfo(enum protoreflect.EnumDescriptor) EnumInfo {
-	// Convert enum values
-	values := make([]EnumValueInfo, 0, enum.Values().Len())
-	for i := 0; i < enum.Values().Len(); i++ {
-		value := enum.Values().Get(i)
-		valueInfo := EnumValueInfo{
-			Name:        string(value.Name()),
-			Number:      int32(value.Number()),
-			Description: strings.TrimSpace(enum.ParentFile().SourceLocations().ByDescriptor(value).LeadingComments),
-			Options:     []OptionInfo{}, // TODO: Extract value options
-		}
-		values = append(values, valueInfo)
-	}
-
-	return EnumInfo{
-		Name:        string(enum.Name()),
-		FullName:    string(enum.FullName()),
-		Values:      values,
-		File:        string(enum.ParentFile().Path()),
-		Package:     string(enum.ParentFile().Package()),
-		Description: strings.TrimSpace(enum.ParentFile().SourceLocations().ByDescriptor(enum).LeadingComments),
-		Options:     []OptionInfo{}, // TODO: Extract enum options
-	}
-}
@@ -3,6 +3,8 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -279,3 +281,368 @@ func TestGetSchemaTool_Handle_WithFilters(t *testing.T) {
 		t.Fatalf("Expected no messages with type filter 'service', got %d", len(response.Schema.Messages))
 	}
 }
+
+func TestGetSchemaTool_Handle_ReservedRanges(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewGetSchemaTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_schema",
+			Arguments: map[string]interface{}{},
+		},
+	}
+
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	var response GetSchemaResponse
+	if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+		if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+	} else {
+		t.Fatalf("Expected text content in response")
+	}
+
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+
+	var user *MessageInfo
+	for i := range response.Schema.Messages {
+		if response.Schema.Messages[i].Name == "User" {
+			user = &response.Schema.Messages[i]
+		}
+	}
+	if user == nil {
+		t.Fatalf("Expected to find User message")
+	}
+
+	expectedRanges := []RangeInfo{{Start: 9, End: 9}, {Start: 20, End: 25}}
+	if len(user.ReservedRanges) != len(expectedRanges) {
+		t.Fatalf("Expected reserved ranges %v, got %v", expectedRanges, user.ReservedRanges)
+	}
+	for i, r := range expectedRanges {
+		if user.ReservedRanges[i] != r {
+			t.Fatalf("Expected reserved range %v, got %v", r, user.ReservedRanges[i])
+		}
+	}
+
+	if len(user.ReservedNames) != 1 || user.ReservedNames[0] != "nickname" {
+		t.Fatalf("Expected reserved names [nickname], got %v", user.ReservedNames)
+	}
+
+	// Highest field is 12 but 20-25 is reserved, so the next free number is 26
+	if user.NextFreeFieldNumber != 26 {
+		t.Fatalf("Expected next_free_field_number=26, got %d", user.NextFreeFieldNumber)
+	}
+
+	var userStatus *EnumInfo
+	for i := range response.Schema.Enums {
+		if response.Schema.Enums[i].Name == "UserStatus" {
+			userStatus = &response.Schema.Enums[i]
+		}
+	}
+	if userStatus == nil {
+		t.Fatalf("Expected to find UserStatus enum")
+	}
+
+	if len(userStatus.ReservedRanges) != 1 || userStatus.ReservedRanges[0] != (RangeInfo{Start: 5, End: 5}) {
+		t.Fatalf("Expected enum reserved range 5, got %v", userStatus.ReservedRanges)
+	}
+	if len(userStatus.ReservedNames) != 1 || userStatus.ReservedNames[0] != "USER_STATUS_DELETED" {
+		t.Fatalf("Expected enum reserved names [USER_STATUS_DELETED], got %v", userStatus.ReservedNames)
+	}
+}
+
+func TestGetSchemaTool_Handle_Comments(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewGetSchemaTool(mockProjectManager)
+
+	findHelloRequest := func(response GetSchemaResponse) MessageInfo {
+		for _, message := range response.Schema.Messages {
+			if message.Name == "HelloRequest" {
+				return message
+			}
+		}
+		t.Fatalf("Expected to find HelloRequest message")
+		return MessageInfo{}
+	}
+
+	response := callToolSuccessfully[GetSchemaResponse](t, tool.Handle, "get_schema", map[string]interface{}{"name": "HelloRequest"})
+	helloRequest := findHelloRequest(response)
+
+	if helloRequest.Description != "Request message for saying hello" {
+		t.Fatalf("Expected leading comment only, got %q", helloRequest.Description)
+	}
+
+	if helloRequest.Fields[1].Description != "Optional language preference" {
+		t.Fatalf("Expected trailing comment in field description, got %q", helloRequest.Fields[1].Description)
+	}
+
+	if helloRequest.Location == nil {
+		t.Fatalf("Expected message location")
+	}
+	if helloRequest.Location.File != "api.proto" || helloRequest.Location.StartLine != 56 || helloRequest.Location.EndLine != 59 {
+		t.Fatalf("Expected location api.proto:56-59, got %+v", *helloRequest.Location)
+	}
+	if field := helloRequest.Fields[1]; field.Location == nil || field.Location.StartLine != 58 {
+		t.Fatalf("Expected field location on line 58, got %+v", field.Location)
+	}
+
+	response = callToolSuccessfully[GetSchemaResponse](t, tool.Handle, "get_schema", map[string]interface{}{
+		"name":                      "HelloRequest",
+		"include_detached_comments": true,
+	})
+	helloRequest = findHelloRequest(response)
+
+	expected := "Greeting messages\n\nRequest message for saying hello"
+	if helloRequest.Description != expected {
+		t.Fatalf("Expected description %q, got %q", expected, helloRequest.Description)
+	}
+}
+
+func TestGetSchemaTool_Handle_Pagination(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewGetSchemaTool(mockProjectManager)
+
+	names := func(schema *SchemaInfo) []string {
+		var result []string
+		for _, message := range schema.Messages {
+			result = append(result, message.FullName)
+		}
+		for _, service := range schema.Services {
+			result = append(result, service.FullName)
+		}
+		for _, enum := range schema.Enums {
+			result = append(result, enum.FullName)
+		}
+		return result
+	}
+
+	full := callToolSuccessfully[GetSchemaResponse](t, tool.Handle, "get_schema", map[string]interface{}{})
+	expected := names(full.Schema)
+	if full.Total != len(expected) || full.NextCursor != "" || full.Truncated {
+		t.Fatalf("Expected a single complete page, got total=%d next_cursor=%q", full.Total, full.NextCursor)
+	}
+
+	var paged []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > len(expected) {
+			t.Fatalf("Pagination did not terminate")
+		}
+		response := callToolSuccessfully[GetSchemaResponse](t, tool.Handle, "get_schema", map[string]interface{}{"page_size": 4, "cursor": cursor})
+		if response.Count > 4 || response.Total != len(expected) {
+			t.Fatalf("Unexpected page: count=%d total=%d", response.Count, response.Total)
+		}
+		paged = append(paged, names(response.Schema)...)
+		if response.NextCursor == "" {
+			break
+		}
+		cursor = response.NextCursor
+	}
+
+	if !reflect.DeepEqual(paged, expected) {
+		t.Fatalf("Expected pages to cover %v, got %v", expected, paged)
+	}
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_schema",
+			Arguments: map[string]interface{}{"cursor": "not-a-cursor"},
+		},
+	}
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("Expected error result for an invalid cursor")
+	}
+}
+
+func TestGetSchemaTool_Handle_Projection(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewGetSchemaTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "get_schema",
+			Arguments: map[string]interface{}{
+				"name":   "HelloRequest",
+				"type":   "message",
+				"fields": "full_name,fields.name,fields.description,-description",
+			},
+		},
+	}
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatalf("Expected text content in response")
+	}
+
+	var decoded struct {
+		Schema struct {
+			Messages []map[string]interface{} `json:"messages"`
+		} `json:"schema"`
+	}
+	if err := json.Unmarshal([]byte(textContent.Text), &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"full_name": "example.simple.v1.HelloRequest",
+		"fields": []interface{}{
+			map[string]interface{}{"name": "name"},
+			map[string]interface{}{"name": "language"},
+		},
+	}
+	if len(decoded.Schema.Messages) != 1 || !reflect.DeepEqual(decoded.Schema.Messages[0], expected) {
+		t.Fatalf("Expected projected message %v, got %v", expected, decoded.Schema.Messages)
+	}
+}
+
+func TestGetSchemaTool_Handle_MaxBytes(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewGetSchemaTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_schema",
+			Arguments: map[string]interface{}{"max_bytes": 4000},
+		},
+	}
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatalf("Expected text content in response")
+	}
+	if len(textContent.Text) > 4000 {
+		t.Fatalf("Expected response within 4000 bytes, got %d", len(textContent.Text))
+	}
+
+	var response GetSchemaResponse
+	if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if !response.Truncated || response.NextCursor == "" || response.Count == 0 || response.Count >= response.Total {
+		t.Fatalf("Expected a truncated page with a cursor, got count=%d total=%d truncated=%v", response.Count, response.Total, response.Truncated)
+	}
+}
+
+func TestGetSchemaTool_Handle_MaxBytesSingleElement(t *testing.T) {
+	var fields strings.Builder
+	for i := 1; i <= 40; i++ {
+		fmt.Fprintf(&fields, "  // Field number %d of a message too large for the budget\n  string field_%d = %d;\n", i, i, i)
+	}
+	project, err := CreateTempProject(t, map[string]string{
+		"large/v1/large.proto": "syntax = \"proto3\";\n\npackage large.v1;\n\nmessage Large {\n" + fields.String() + "}\n",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewGetSchemaTool(mockProjectManager)
+
+	for _, maxBytes := range []int{1500, 300} {
+		req := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "get_schema",
+				Arguments: map[string]interface{}{"max_bytes": maxBytes},
+			},
+		}
+		result, err := tool.Handle(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+		textContent, ok := mcp.AsTextContent(result.Content[0])
+		if !ok {
+			t.Fatalf("Expected text content in response")
+		}
+		if len(textContent.Text) > maxBytes {
+			t.Errorf("Expected response within %d bytes, got %d: %s", maxBytes, len(textContent.Text), textContent.Text)
+		}
+
+		var response GetSchemaResponse
+		if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if !response.Truncated || response.NextCursor != "" || !reflect.DeepEqual(response.TruncatedElements, []string{"large.v1.Large"}) {
+			t.Errorf("Expected large.v1.Large to be reduced, got truncated=%v truncated_elements=%v", response.Truncated, response.TruncatedElements)
+		}
+		if len(response.Schema.Messages) != 1 || response.Schema.Messages[0].FullName != "large.v1.Large" {
+			t.Fatalf("Expected the reduced message, got %+v", response.Schema.Messages)
+		}
+		// The larger budget still fits the field names
+		if fields := response.Schema.Messages[0].Fields; maxBytes == 1500 && (len(fields) != 40 || fields[0].Name != "field_1" || fields[0].Description != "") {
+			t.Errorf("Expected only the names of 40 fields, got %+v", fields)
+		}
+	}
+}
+
+func TestGetSchemaTool_Handle_Summary(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewGetSchemaTool(mockProjectManager)
+
+	response := callToolSuccessfully[GetSchemaResponse](t, tool.Handle, "get_schema", map[string]interface{}{"summary": true, "type": "service"})
+	if response.Schema != nil {
+		t.Fatalf("Expected no schema definitions in summary mode")
+	}
+	expected := &SchemaSummary{
+		ServiceCount: 2,
+		Messages:     []string{},
+		Services:     []string{"example.simple.v1.GreetingService", "example.simple.v1.UserService"},
+		Enums:        []string{},
+	}
+	if !reflect.DeepEqual(response.Summary, expected) {
+		t.Fatalf("Expected summary %+v, got %+v", expected, response.Summary)
+	}
+}
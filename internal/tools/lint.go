@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/breaking"
+	"github.com/yuemori/protobuf-mcp-server/internal/lint"
+	"github.com/yuemori/protobuf-mcp-server/internal/textedit"
+)
+
+// LintTool implements the lint MCP tool using mcp-go
+type LintTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewLintTool creates a new LintTool instance
+func NewLintTool(projectManager ProjectManagerInterface) *LintTool {
+	return &LintTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *LintTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"lint",
+		mcp.WithDescription("Check the proto files of the activated project against style rules: versioned packages, go_package, PascalCase messages, enums, services and RPCs, lower_snake_case fields, UPPER_SNAKE_CASE enum values with an _UNSPECIFIED zero value, <Method>Request/<Method>Response naming and comment coverage, plus an opt-in AIP group checking Google AIP resource-oriented design (standard methods, pagination, google.api.http verbs, paths and bodies, FieldMask on Update, resource names). Rules, exceptions and ignored files are configured in the lint section of .protobuf-mcp.yml. Returns findings with file and line; fixable findings (casing, _UNSPECIFIED zero values, missing comments, import order) carry text edits computed from source spans, which apply writes to the files. Renames also edit every reference in the project, but change generated code and the JSON names of fields"),
+		mcp.WithString("rules",
+			mcp.Description("Comma-separated rule IDs or groups (STYLE, COMMENTS, AIP, DEFAULT, ALL) to run instead of the configured rules"),
+		),
+		mcp.WithString("files",
+			mcp.Description("Comma-separated files, directories or glob patterns to lint (default: all project files)"),
+		),
+		mcp.WithBoolean("list_rules",
+			mcp.Description("List the available rules instead of linting"),
+		),
+		mcp.WithBoolean("apply",
+			mcp.Description("Write the fixes to the proto files and return the findings that remain"),
+		),
+	)
+}
+
+// LintResponse represents the response from lint tool
+type LintResponse struct {
+	Success  bool           `json:"success"`
+	Message  string         `json:"message"`
+	Findings []lint.Finding `json:"findings,omitempty"`
+	Count    int            `json:"count"`
+	Fixable  int            `json:"fixable"`
+	Applied  []string       `json:"applied,omitempty"`
+	Rules    []LintRuleInfo `json:"rules,omitempty"`
+}
+
+// LintRuleInfo describes a lint rule
+type LintRuleInfo struct {
+	ID          string `json:"id"`
+	Group       string `json:"group"`
+	Description string `json:"description"`
+}
+
+// Handle handles the tool execution
+func (t *LintTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if req.GetBool("list_rules", false) {
+		var rules []LintRuleInfo
+		for _, rule := range lint.Rules() {
+			rules = append(rules, LintRuleInfo{ID: rule.ID, Group: rule.Group, Description: rule.Description})
+		}
+		response := &LintResponse{
+			Success: true,
+			Message: fmt.Sprintf("%d lint rules", len(rules)),
+			Rules:   rules,
+		}
+		responseJSON, err := json.Marshal(response)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &LintResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	cfg := project.Config.Lint
+	if rules := splitList(req.GetString("rules", "")); len(rules) > 0 {
+		cfg.Rules = rules
+		cfg.Except = nil
+	}
+	linter, err := lint.New(cfg)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	patterns := splitList(req.GetString("files", ""))
+	run := func() ([]lint.Finding, int, error) {
+		files, err := project.CompileProtos(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		// Every file is indexed, so that renames reach references in files
+		// outside the patterns, but only the matching ones are linted
+		var include func(path string) bool
+		if len(patterns) > 0 {
+			include = func(path string) bool {
+				return lint.MatchesAny(patterns, path)
+			}
+		}
+		all := breaking.FileDescriptors(files)
+		count := 0
+		for _, file := range all {
+			if include == nil || include(file.Path()) {
+				count++
+			}
+		}
+		return linter.Run(all, include), count, nil
+	}
+
+	findings, fileCount, err := run()
+	if err != nil {
+		response := &LintResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	var applied []string
+	var appliedEdits int
+	if edits := lint.Edits(findings); req.GetBool("apply", false) && len(edits) > 0 {
+		applied, err = textedit.ApplyFiles(edits, project.SourcePath)
+		if err != nil {
+			response := &LintResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to apply fixes: %v", err),
+			}
+			responseJSON, _ := json.Marshal(response)
+			return mcp.NewToolResultText(string(responseJSON)), nil
+		}
+		appliedEdits = len(edits)
+
+		// Lint again, so that the response reflects the fixed files
+		findings, fileCount, err = run()
+		if err != nil {
+			response := &LintResponse{
+				Success: false,
+				Message: fmt.Sprintf("Applied %d edits to %d files, but they no longer compile: %v", appliedEdits, len(applied), err),
+				Applied: applied,
+			}
+			responseJSON, _ := json.Marshal(response)
+			return mcp.NewToolResultText(string(responseJSON)), nil
+		}
+	}
+
+	fixable := 0
+	for _, finding := range findings {
+		if len(finding.Edits) > 0 {
+			fixable++
+		}
+	}
+
+	message := fmt.Sprintf("No lint findings in %d files", fileCount)
+	if len(findings) > 0 {
+		message = fmt.Sprintf("Found %d lint findings in %d files (%d fixable)", len(findings), fileCount, fixable)
+	}
+	if len(applied) > 0 {
+		message = fmt.Sprintf("Applied %d edits to %d files. %s", appliedEdits, len(applied), message)
+	}
+
+	response := &LintResponse{
+		Success:  true,
+		Message:  message,
+		Findings: findings,
+		Count:    len(findings),
+		Fixable:  fixable,
+		Applied:  applied,
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// splitList splits a comma-separated parameter, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,300 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestLintTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewLintTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "lint" {
+		t.Fatalf("Expected tool name 'lint', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestLintTool_Handle(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"library/v1/api.proto": `syntax = "proto3";
+package library.v1;
+
+option go_package = "example.com/library/v1;libraryv1";
+
+// A book
+message Book {
+  // The title
+  string Title = 1;
+}
+`,
+		"shelf/v1/shelf.proto": `syntax = "proto3";
+package shelf.v1;
+
+option go_package = "example.com/shelf/v1;shelfv1";
+
+message Shelf {}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+	project.Config.Lint.IgnoreOnly = map[string][]string{"COMMENTS": {"shelf"}}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewLintTool(mockProjectManager)
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		expected  []string
+	}{
+		{
+			name:      "configured rules",
+			arguments: map[string]interface{}{},
+			expected:  []string{"FIELD_LOWER_SNAKE_CASE"},
+		},
+		{
+			name:      "selected files",
+			arguments: map[string]interface{}{"files": "shelf/**"},
+			expected:  nil,
+		},
+		{
+			name:      "selected rules",
+			arguments: map[string]interface{}{"rules": "comment_message, FIELD_LOWER_SNAKE_CASE"},
+			expected:  []string{"FIELD_LOWER_SNAKE_CASE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := callTool[LintResponse](t, tool.Handle, "lint", tt.arguments)
+			if !response.Success {
+				t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+			}
+			if response.Count != len(tt.expected) {
+				t.Fatalf("Expected %d findings, got %+v", len(tt.expected), response.Findings)
+			}
+			for i, rule := range tt.expected {
+				if response.Findings[i].Rule != rule {
+					t.Errorf("Expected finding %d to be %s, got %+v", i, rule, response.Findings[i])
+				}
+			}
+		})
+	}
+
+	response := callTool[LintResponse](t, tool.Handle, "lint", map[string]interface{}{})
+	finding := response.Findings[0]
+	if finding.File != "library/v1/api.proto" || finding.Location == nil || finding.Location.StartLine != 9 {
+		t.Errorf("Expected the finding at library/v1/api.proto:9, got %+v", finding)
+	}
+}
+
+func TestLintTool_Apply(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"library/v1/api.proto": `syntax = "proto3";
+package library.v1;
+
+option go_package = "example.com/library/v1;libraryv1";
+
+import "library/v1/types.proto";
+
+// A shelf
+message Shelf {
+  // The books
+  repeated book books = 1;
+}
+`,
+		"library/v1/types.proto": `syntax = "proto3";
+package library.v1;
+
+option go_package = "example.com/library/v1;libraryv1";
+
+// A book
+message book {
+  // The title
+  string title = 1;
+}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewLintTool(mockProjectManager)
+
+	response := callTool[LintResponse](t, tool.Handle, "lint", map[string]interface{}{})
+	if response.Count != 1 || response.Fixable != 1 || len(response.Findings[0].Edits) != 2 {
+		t.Fatalf("Expected one fixable finding with 2 edits, got %+v", response)
+	}
+	if len(response.Applied) != 0 {
+		t.Fatalf("Expected nothing applied without apply, got %v", response.Applied)
+	}
+
+	response = callTool[LintResponse](t, tool.Handle, "lint", map[string]interface{}{"apply": true})
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+	if response.Count != 0 {
+		t.Errorf("Expected no findings after applying, got %+v", response.Findings)
+	}
+	if len(response.Applied) != 2 {
+		t.Errorf("Expected 2 edited files, got %v", response.Applied)
+	}
+
+	content, err := os.ReadFile(filepath.Join(project.ProjectRoot, "library/v1/api.proto"))
+	if err != nil {
+		t.Fatalf("Failed to read api.proto: %v", err)
+	}
+	if !strings.Contains(string(content), "repeated Book books = 1;") {
+		t.Errorf("Expected the reference to be renamed, got:\n%s", content)
+	}
+}
+
+func TestLintTool_ApplyFiles(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"library/v1/a.proto": `syntax = "proto3";
+package library.v1;
+
+// A book
+message bad_name {
+  // The title
+  string title = 1;
+}
+`,
+		"library/v1/b.proto": `syntax = "proto3";
+package library.v1;
+
+import "library/v1/a.proto";
+
+// A shelf
+message Shelf {
+  bad_name book = 1;
+}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewLintTool(mockProjectManager)
+
+	// Only a.proto is linted, but the rename reaches the reference in b.proto
+	response := callTool[LintResponse](t, tool.Handle, "lint", map[string]interface{}{"files": "library/v1/a.proto", "rules": "MESSAGE_PASCAL_CASE", "apply": true})
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+	if strings.Join(response.Applied, ",") != "library/v1/a.proto,library/v1/b.proto" {
+		t.Errorf("Expected both files to be edited, got %v", response.Applied)
+	}
+	if response.Count != 0 || !strings.Contains(response.Message, "in 1 files") {
+		t.Errorf("Expected no findings in the one linted file, got %s: %+v", response.Message, response.Findings)
+	}
+
+	content, err := os.ReadFile(filepath.Join(project.ProjectRoot, "library/v1/b.proto"))
+	if err != nil {
+		t.Fatalf("Failed to read b.proto: %v", err)
+	}
+	if !strings.Contains(string(content), "  BadName book = 1;") {
+		t.Errorf("Expected the reference to be renamed, got:\n%s", content)
+	}
+}
+
+func TestLintTool_ListRules(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewLintTool(mockProjectManager)
+
+	response := callTool[LintResponse](t, tool.Handle, "lint", map[string]interface{}{"list_rules": true})
+	if !response.Success || len(response.Rules) == 0 {
+		t.Fatalf("Expected the list of rules, got %+v", response)
+	}
+	for _, rule := range response.Rules {
+		if rule.ID == "" || rule.Group == "" || rule.Description == "" {
+			t.Errorf("Expected a complete rule description, got %+v", rule)
+		}
+	}
+}
+
+func TestLintTool_InvalidRule(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"api.proto": `syntax = "proto3";
+package test.v1;
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewLintTool(mockProjectManager)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "lint",
+			Arguments: map[string]interface{}{"rules": "NO_SUCH_RULE"},
+		},
+	}
+
+	result, err := tool.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	if !result.IsError {
+		t.Errorf("Expected an error result for an unknown rule")
+	}
+}
+
+func TestLintTool_NoProject(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewLintTool(mockProjectManager)
+
+	response := callTool[LintResponse](t, tool.Handle, "lint", map[string]interface{}{})
+	if response.Success {
+		t.Errorf("Expected success=false without an activated project")
+	}
+}
+
+func TestLintTool_AIP(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewLintTool(mockProjectManager)
+
+	response := callTool[LintResponse](t, tool.Handle, "lint", map[string]interface{}{"rules": "AIP", "files": "api.proto"})
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+
+	// GetUser binds {user_id} rather than the resource name
+	found := false
+	for _, finding := range response.Findings {
+		if finding.Rule == "AIP_HTTP_PATH" && finding.Element == "example.simple.v1.GreetingService.GetUser" {
+			found = true
+		}
+		if !strings.HasPrefix(finding.Rule, "AIP_") {
+			t.Errorf("Expected only AIP findings, got %+v", finding)
+		}
+	}
+	if !found {
+		t.Errorf("Expected an AIP_HTTP_PATH finding for GetUser, got %+v", response.Findings)
+	}
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ListFilesTool implements the list_files MCP tool using mcp-go
+type ListFilesTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewListFilesTool creates a new ListFilesTool instance
+func NewListFilesTool(projectManager ProjectManagerInterface) *ListFilesTool {
+	return &ListFilesTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *ListFilesTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"list_files",
+		mcp.WithDescription("List all proto files in the activated project with their package, syntax, imports, options and declaration counts"),
+	)
+}
+
+// ListFilesResponse represents the response from list_files tool
+type ListFilesResponse struct {
+	Success       bool       `json:"success"`
+	Message       string     `json:"message"`
+	SchemaVersion string     `json:"schema_version,omitempty"`
+	Files         []FileInfo `json:"files,omitempty"`
+	Count         int        `json:"count"`
+}
+
+// Handle handles the tool execution
+func (t *ListFilesTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &ListFilesResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &ListFilesResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	fileInfos := make([]FileInfo, 0, len(files))
+	for _, file := range files {
+		fileInfos = append(fileInfos, newFileInfo(file))
+	}
+
+	response := &ListFilesResponse{
+		Success:       true,
+		Message:       fmt.Sprintf("Found %d files", len(fileInfos)),
+		SchemaVersion: SchemaVersion,
+		Files:         fileInfos,
+		Count:         len(fileInfos),
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
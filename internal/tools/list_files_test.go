@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestListFilesTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewListFilesTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "list_files" {
+		t.Fatalf("Expected tool name 'list_files', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestListFilesTool_Handle_NoProject(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewListFilesTool(mockProjectManager)
+
+	result, err := tool.Handle(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	var response ListFilesResponse
+	if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+		if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+	} else {
+		t.Fatalf("Expected text content in response")
+	}
+
+	if response.Success {
+		t.Fatalf("Expected success=false when no project is activated, got success=true")
+	}
+
+	if !strings.Contains(response.Message, "No project activated") {
+		t.Fatalf("Expected error message about no project activated, got: %s", response.Message)
+	}
+}
+
+func TestListFilesTool_Handle_Success(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewListFilesTool(mockProjectManager)
+
+	result, err := tool.Handle(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	var response ListFilesResponse
+	if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+		if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+	} else {
+		t.Fatalf("Expected text content in response")
+	}
+
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+
+	if response.Count != 2 || len(response.Files) != 2 {
+		t.Fatalf("Expected 2 files, got count=%d files=%d", response.Count, len(response.Files))
+	}
+
+	api := response.Files[0]
+	if api.Path != "api.proto" {
+		t.Fatalf("Expected first file to be api.proto, got %s", api.Path)
+	}
+	if api.Package != "example.simple.v1" {
+		t.Fatalf("Expected package example.simple.v1, got %s", api.Package)
+	}
+	if api.Syntax != "proto3" {
+		t.Fatalf("Expected syntax proto3, got %s", api.Syntax)
+	}
+	if len(api.Imports) != 2 || api.Imports[0].Path != "types.proto" || api.Imports[1].Path != "google/api/annotations.proto" {
+		t.Fatalf("Expected imports of types.proto and google/api/annotations.proto, got %+v", api.Imports)
+	}
+	if api.Imports[0].Public || api.Imports[0].Weak {
+		t.Fatalf("Expected a regular import, got %+v", api.Imports[0])
+	}
+	if api.ServiceCount != 2 {
+		t.Fatalf("Expected 2 services, got %d", api.ServiceCount)
+	}
+	if api.MessageCount == 0 {
+		t.Fatalf("Expected messages in api.proto")
+	}
+
+	foundGoPackage := false
+	for _, option := range api.Options {
+		if option.Name == "go_package" {
+			foundGoPackage = true
+			if option.Value != "github.com/yuemori/protobuf-mcp-server/testdata/simple/v1" {
+				t.Fatalf("Unexpected go_package value: %v", option.Value)
+			}
+		}
+	}
+	if !foundGoPackage {
+		t.Fatalf("Expected go_package option, got %+v", api.Options)
+	}
+}
@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/bufbuild/protocompile/linker"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ListPackagesTool implements the list_packages MCP tool using mcp-go
+type ListPackagesTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewListPackagesTool creates a new ListPackagesTool instance
+func NewListPackagesTool(projectManager ProjectManagerInterface) *ListPackagesTool {
+	return &ListPackagesTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *ListPackagesTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"list_packages",
+		mcp.WithDescription("List proto packages in the activated project with their files, services and declaration counts"),
+		mcp.WithString("package",
+			mcp.Description("Filter by package name or glob (e.g. 'example.simple.v1' or 'example.*')"),
+		),
+	)
+}
+
+// ListPackagesResponse represents the response from list_packages tool
+type ListPackagesResponse struct {
+	Success       bool          `json:"success"`
+	Message       string        `json:"message"`
+	SchemaVersion string        `json:"schema_version,omitempty"`
+	Packages      []PackageInfo `json:"packages,omitempty"`
+	Count         int           `json:"count"`
+}
+
+// PackageInfo represents a proto package and the declarations it groups
+type PackageInfo struct {
+	Name         string   `json:"name"`
+	Version      string   `json:"version,omitempty"`
+	Files        []string `json:"files"`
+	Services     []string `json:"services"`
+	MessageCount int      `json:"message_count"`
+	EnumCount    int      `json:"enum_count"`
+	ServiceCount int      `json:"service_count"`
+}
+
+// Handle handles the tool execution
+func (t *ListPackagesTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	packageFilter := req.GetString("package", "")
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &ListPackagesResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &ListPackagesResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	packages := t.buildPackageInfos(files, packageFilter)
+
+	response := &ListPackagesResponse{
+		Success:       true,
+		Message:       fmt.Sprintf("Found %d packages", len(packages)),
+		SchemaVersion: SchemaVersion,
+		Packages:      packages,
+		Count:         len(packages),
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// buildPackageInfos groups the compiled files by package, sorted by package name
+func (t *ListPackagesTool) buildPackageInfos(files linker.Files, packageFilter string) []PackageInfo {
+	byName := make(map[string]*PackageInfo)
+	for _, file := range files {
+		name := string(file.Package())
+		if !matchesPackage(name, packageFilter) {
+			continue
+		}
+
+		info, ok := byName[name]
+		if !ok {
+			info = &PackageInfo{
+				Name:     name,
+				Version:  packageVersion(name),
+				Files:    []string{},
+				Services: []string{},
+			}
+			byName[name] = info
+		}
+
+		info.Files = append(info.Files, file.Path())
+		for i := 0; i < file.Services().Len(); i++ {
+			info.Services = append(info.Services, string(file.Services().Get(i).FullName()))
+		}
+		info.MessageCount += file.Messages().Len()
+		info.EnumCount += file.Enums().Len()
+		info.ServiceCount += file.Services().Len()
+	}
+
+	packages := make([]PackageInfo, 0, len(byName))
+	for _, info := range byName {
+		packages = append(packages, *info)
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+
+	return packages
+}
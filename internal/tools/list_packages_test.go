@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestListPackagesTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewListPackagesTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "list_packages" {
+		t.Fatalf("Expected tool name 'list_packages', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestListPackagesTool_Handle(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"billing/v1beta1/billing.proto": `syntax = "proto3";
+package acme.billing.v1beta1;
+
+service BillingService {
+  rpc Charge(ChargeRequest) returns (ChargeResponse);
+}
+
+message ChargeRequest {}
+message ChargeResponse {}
+`,
+		"users/v1/users.proto": `syntax = "proto3";
+package acme.users.v1;
+
+message User {}
+enum Role {
+  ROLE_UNSPECIFIED = 0;
+}
+`,
+		"users/v1/users_service.proto": `syntax = "proto3";
+package acme.users.v1;
+
+import "users/v1/users.proto";
+
+service UserService {
+  rpc GetUser(User) returns (User);
+}
+`,
+		"common/common.proto": `syntax = "proto3";
+package acme.common;
+
+message Money {}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewListPackagesTool(mockProjectManager)
+
+	callListPackages := func(arguments map[string]interface{}) ListPackagesResponse {
+		req := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "list_packages",
+				Arguments: arguments,
+			},
+		}
+
+		result, err := tool.Handle(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+
+		var response ListPackagesResponse
+		if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+			if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+		} else {
+			t.Fatalf("Expected text content in response")
+		}
+
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+		return response
+	}
+
+	response := callListPackages(map[string]interface{}{})
+	if response.Count != 3 {
+		t.Fatalf("Expected 3 packages, got %d: %+v", response.Count, response.Packages)
+	}
+
+	names := []string{response.Packages[0].Name, response.Packages[1].Name, response.Packages[2].Name}
+	expectedNames := []string{"acme.billing.v1beta1", "acme.common", "acme.users.v1"}
+	for i := range expectedNames {
+		if names[i] != expectedNames[i] {
+			t.Fatalf("Expected packages %v, got %v", expectedNames, names)
+		}
+	}
+
+	billing, common, users := response.Packages[0], response.Packages[1], response.Packages[2]
+	if billing.Version != "v1beta1" {
+		t.Fatalf("Expected version v1beta1, got %q", billing.Version)
+	}
+	if common.Version != "" {
+		t.Fatalf("Expected no version for acme.common, got %q", common.Version)
+	}
+	if users.Version != "v1" || len(users.Files) != 2 || users.MessageCount != 1 || users.EnumCount != 1 || users.ServiceCount != 1 {
+		t.Fatalf("Unexpected acme.users.v1 package: %+v", users)
+	}
+	if len(users.Services) != 1 || users.Services[0] != "acme.users.v1.UserService" {
+		t.Fatalf("Expected acme.users.v1.UserService, got %v", users.Services)
+	}
+
+	response = callListPackages(map[string]interface{}{"package": "acme.users.*"})
+	if response.Count != 1 || response.Packages[0].Name != "acme.users.v1" {
+		t.Fatalf("Expected only acme.users.v1 with glob filter, got %+v", response.Packages)
+	}
+
+	// The same filter scopes get_schema and list_services
+	schema := callToolSuccessfully[GetSchemaResponse](t, NewGetSchemaTool(mockProjectManager).Handle, "get_schema", map[string]interface{}{"package": "acme.billing.v1beta1"})
+	if len(schema.Schema.Messages) != 2 || len(schema.Schema.Services) != 1 || len(schema.Schema.Enums) != 0 {
+		t.Fatalf("Expected only billing declarations, got %+v", schema.Schema)
+	}
+
+	result, err := NewListServicesTool(mockProjectManager).Handle(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "list_services",
+			Arguments: map[string]interface{}{"package": "acme.users.v1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	var services ListServicesResponse
+	if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+		if err := json.Unmarshal([]byte(textContent.Text), &services); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+	}
+	if services.Count != 1 || services.Services[0].Name != "UserService" {
+		t.Fatalf("Expected only UserService, got %+v", services.Services)
+	}
+}
+
+func TestPackageVersion(t *testing.T) {
+	tests := map[string]string{
+		"example.simple.v1":      "v1",
+		"acme.billing.v1beta1":   "v1beta1",
+		"acme.billing.v2alpha":   "v2alpha",
+		"acme.billing.v1p1beta1": "v1p1beta1",
+		"acme.common":            "",
+		"v1":                     "v1",
+		"acme.video":             "",
+	}
+
+	for pkg, expected := range tests {
+		if got := packageVersion(pkg); got != expected {
+			t.Errorf("packageVersion(%q) = %q, expected %q", pkg, got, expected)
+		}
+	}
+}
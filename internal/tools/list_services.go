@@ -4,10 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
-	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // ListServicesTool implements the list_services MCP tool using mcp-go
@@ -27,19 +25,25 @@ func (t *ListServicesTool) GetTool() mcp.Tool {
 	return mcp.NewTool(
 		"list_services",
 		mcp.WithDescription("List all services in the currently activated protobuf project"),
+		mcp.WithString("package",
+			mcp.Description("Filter by package name or glob (e.g. 'example.simple.v1' or 'example.*')"),
+		),
 	)
 }
 
 // ListServicesResponse represents the response from list_services tool
 type ListServicesResponse struct {
-	Success  bool          `json:"success"`
-	Message  string        `json:"message"`
-	Services []ServiceInfo `json:"services,omitempty"`
-	Count    int           `json:"count"`
+	Success       bool          `json:"success"`
+	Message       string        `json:"message"`
+	SchemaVersion string        `json:"schema_version,omitempty"`
+	Services      []ServiceInfo `json:"services,omitempty"`
+	Count         int           `json:"count"`
 }
 
 // Handle handles the tool execution
 func (t *ListServicesTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	packageFilter := req.GetString("package", "")
+
 	// Get current project
 	project := t.projectManager.GetProject()
 	if project == nil {
@@ -75,15 +79,19 @@ func (t *ListServicesTool) Handle(ctx context.Context, req mcp.CallToolRequest)
 	// Convert to ServiceInfo
 	serviceInfos := make([]ServiceInfo, 0, len(services))
 	for _, service := range services {
-		serviceInfo := t.convertServiceToInfo(service)
+		if !matchesPackage(string(service.ParentFile().Package()), packageFilter) {
+			continue
+		}
+		serviceInfo := newServiceInfo(service, convertOptions{})
 		serviceInfos = append(serviceInfos, serviceInfo)
 	}
 
 	response := &ListServicesResponse{
-		Success:  true,
-		Message:  fmt.Sprintf("Found %d services", len(serviceInfos)),
-		Services: serviceInfos,
-		Count:    len(serviceInfos),
+		Success:       true,
+		Message:       fmt.Sprintf("Found %d services", len(serviceInfos)),
+		SchemaVersion: SchemaVersion,
+		Services:      serviceInfos,
+		Count:         len(serviceInfos),
 	}
 
 	responseJSON, err := json.Marshal(response)
@@ -93,31 +101,3 @@ func (t *ListServicesTool) Handle(ctx context.Context, req mcp.CallToolRequest)
 
 	return mcp.NewToolResultText(string(responseJSON)), nil
 }
-
-// convertServiceToInfo converts a protobuf service to ServiceInfo
-func (t *ListServicesTool) convertServiceToInfo(service protoreflect.ServiceDescriptor) ServiceInfo {
-	// Convert methods
-	methods := make([]MethodInfo, 0, service.Methods().Len())
-
-	for i := 0; i < service.Methods().Len(); i++ {
-		method := service.Methods().Get(i)
-		methodInfo := MethodInfo{
-			Name:            string(method.Name()),
-			InputType:       string(method.Input().Name()),
-			OutputType:      string(method.Output().Name()),
-			ClientStreaming: method.IsStreamingClient(),
-			ServerStreaming: method.IsStreamingServer(),
-			Description:     strings.TrimSpace(method.ParentFile().SourceLocations().ByDescriptor(method).LeadingComments),
-		}
-		methods = append(methods, methodInfo)
-	}
-
-	return ServiceInfo{
-		Name:        string(service.Name()),
-		FullName:    "." + string(service.FullName()),
-		Methods:     methods,
-		Package:     string(service.ParentFile().Package()),
-		File:        string(service.ParentFile().Path()),
-		Description: strings.TrimSpace(service.ParentFile().SourceLocations().ByDescriptor(service).LeadingComments),
-	}
-}
@@ -163,3 +163,39 @@ func TestListServicesTool_Handle_Success(t *testing.T) {
 		t.Fatalf("Expected to find UserService")
 	}
 }
+
+func TestListServicesTool_MatchesGetSchema(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+
+	result, err := NewListServicesTool(mockProjectManager).Handle(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	var listResponse ListServicesResponse
+	if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+		if err := json.Unmarshal([]byte(textContent.Text), &listResponse); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+	} else {
+		t.Fatalf("Expected text content in response")
+	}
+
+	schemaResponse := callToolSuccessfully[GetSchemaResponse](t, NewGetSchemaTool(mockProjectManager).Handle, "get_schema", map[string]interface{}{"type": "service"})
+
+	if listResponse.SchemaVersion != SchemaVersion || schemaResponse.SchemaVersion != SchemaVersion {
+		t.Fatalf("Expected schema_version %q, got %q and %q", SchemaVersion, listResponse.SchemaVersion, schemaResponse.SchemaVersion)
+	}
+
+	listJSON, _ := json.Marshal(listResponse.Services)
+	schemaJSON, _ := json.Marshal(schemaResponse.Schema.Services)
+	if string(listJSON) != string(schemaJSON) {
+		t.Fatalf("Expected list_services and get_schema to describe services identically\nlist_services: %s\nget_schema: %s", listJSON, schemaJSON)
+	}
+}
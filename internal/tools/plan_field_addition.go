@@ -0,0 +1,481 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bufbuild/protocompile/linker"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+	"github.com/yuemori/protobuf-mcp-server/internal/textedit"
+)
+
+// scalarTypes are the field types written as keywords
+var scalarTypes = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true, "uint64": true,
+	"sint32": true, "sint64": true, "fixed32": true, "fixed64": true, "sfixed32": true, "sfixed64": true,
+	"bool": true, "string": true, "bytes": true,
+}
+
+// identifierPattern matches a valid proto identifier
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// PlanFieldAdditionTool implements the plan_field_addition MCP tool using mcp-go
+type PlanFieldAdditionTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewPlanFieldAdditionTool creates a new PlanFieldAdditionTool instance
+func NewPlanFieldAdditionTool(projectManager ProjectManagerInterface) *PlanFieldAdditionTool {
+	return &PlanFieldAdditionTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *PlanFieldAdditionTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"plan_field_addition",
+		mcp.WithDescription("Plan adding a field to a message: returns the next safe field number (above the highest field or reserved number, skipping extension ranges and never reusing gaps unless the numbers run out), warnings about name and JSON name collisions with existing or reserved fields and missing imports, and the edit inserting the declaration after the last field with the indentation and comment style of the surrounding fields. Nothing is written"),
+		mcp.WithString("message",
+			mcp.Required(),
+			mcp.Description("Fully-qualified name of the message (e.g. 'example.simple.v1.User')"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the new field (e.g. 'display_name')"),
+		),
+		mcp.WithString("type",
+			mcp.Required(),
+			mcp.Description("Field type: a scalar type, a message or enum name (fully-qualified, or relative to the message like in proto source) or 'map<key, value>'"),
+		),
+		mcp.WithString("label",
+			mcp.Description("Field label"),
+			mcp.Enum("optional", "repeated"),
+		),
+		mcp.WithString("comment",
+			mcp.Description("Comment documenting the new field, written in the comment style of the surrounding fields"),
+		),
+	)
+}
+
+// PlanFieldAdditionResponse represents the response from plan_field_addition tool
+type PlanFieldAdditionResponse struct {
+	Success     bool           `json:"success"`
+	Message     string         `json:"message"`
+	Target      string         `json:"target,omitempty"`
+	Number      int32          `json:"number,omitempty"`
+	JSONName    string         `json:"json_name,omitempty"`
+	Declaration string         `json:"declaration,omitempty"`
+	Edit        *textedit.Edit `json:"edit,omitempty"`
+	Import      string         `json:"import,omitempty"`
+	Warnings    []string       `json:"warnings,omitempty"`
+}
+
+// Handle handles the tool execution
+func (t *PlanFieldAdditionTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	messageName := req.GetString("message", "")
+	if messageName == "" {
+		return mcp.NewToolResultError("message parameter is required"), nil
+	}
+	name := req.GetString("name", "")
+	if !identifierPattern.MatchString(name) {
+		return mcp.NewToolResultError(fmt.Sprintf("name must be a valid field name, got %q", name)), nil
+	}
+	typ := strings.TrimSpace(req.GetString("type", ""))
+	if typ == "" {
+		return mcp.NewToolResultError("type parameter is required"), nil
+	}
+	label := req.GetString("label", "")
+	if label != "" && label != "optional" && label != "repeated" {
+		return mcp.NewToolResultError(fmt.Sprintf("label must be 'optional' or 'repeated', got %q", label)), nil
+	}
+	comment := strings.TrimSpace(req.GetString("comment", ""))
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &PlanFieldAdditionResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &PlanFieldAdditionResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	message, ok := findDescriptor(files, messageName).(protoreflect.MessageDescriptor)
+	if !ok || message.IsMapEntry() {
+		response := &PlanFieldAdditionResponse{
+			Success: false,
+			Message: fmt.Sprintf("Message not found: %s", messageName),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+	file := message.ParentFile()
+
+	fieldType, types, err := resolveFieldType(files, message, typ)
+	if err != nil {
+		response := &PlanFieldAdditionResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	isMap := strings.HasPrefix(fieldType, "map<")
+	switch {
+	case isMap && label != "":
+		return mcp.NewToolResultError("map fields cannot have a label"), nil
+	case label == "optional" && file.Syntax() == protoreflect.Editions:
+		return mcp.NewToolResultError("fields of editions files cannot have the optional label; use the field_presence feature instead"), nil
+	case label == "" && !isMap && file.Syntax() == protoreflect.Proto2:
+		label = "optional"
+	}
+
+	number := nextFreeFieldNumber(message)
+	if number == 0 {
+		response := &PlanFieldAdditionResponse{
+			Success: false,
+			Message: fmt.Sprintf("No field numbers are free in %s", message.FullName()),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	declaration := fmt.Sprintf("%s %s = %d;", fieldType, name, number)
+	if label != "" {
+		declaration = label + " " + declaration
+	}
+
+	response := &PlanFieldAdditionResponse{
+		Success:     true,
+		Target:      string(message.FullName()),
+		Number:      int32(number),
+		JSONName:    protoutil.DefaultJSONName(name),
+		Declaration: declaration,
+		Warnings:    fieldNameWarnings(message, name, nil),
+	}
+	if highest := highestFieldNumber(message); number < highest {
+		response.Warnings = append(response.Warnings, fmt.Sprintf("No field numbers are free above the highest one in use; %d lies below it and may have belonged to a deleted field that was not reserved", number))
+	}
+	for _, desc := range types {
+		path := desc.ParentFile().Path()
+		if path != file.Path() && !importsFile(file, path) && response.Import == "" {
+			response.Import = path
+			response.Warnings = append(response.Warnings, fmt.Sprintf("%s must import %q to use %s", file.Path(), path, desc.FullName()))
+		}
+	}
+
+	source, err := readSourceLines(project, file.Path())
+	if err != nil {
+		response := &PlanFieldAdditionResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	edit, documented := fieldInsertion(message, source, declaration, comment)
+	if edit == nil {
+		response := &PlanFieldAdditionResponse{
+			Success: false,
+			Message: fmt.Sprintf("No source location for %s; the file changed since it was compiled", message.FullName()),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+	response.Edit = edit
+	if documented && comment == "" {
+		response.Warnings = append(response.Warnings, fmt.Sprintf("The other fields of %s are documented; pass comment to document the new field", message.Name()))
+	}
+
+	response.Message = fmt.Sprintf("Insert %q into %s at %s:%d", declaration, message.FullName(), edit.File, edit.StartLine)
+	if len(response.Warnings) > 0 {
+		response.Message += fmt.Sprintf(" (%d warnings)", len(response.Warnings))
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// resolveFieldType resolves a field type as written by the caller and
+// returns it as it should be written in the message, together with the
+// messages and enums it references
+func resolveFieldType(files linker.Files, message protoreflect.MessageDescriptor, typ string) (string, []protoreflect.Descriptor, error) {
+	if inner, ok := strings.CutPrefix(typ, "map<"); ok && strings.HasSuffix(inner, ">") {
+		key, value, ok := strings.Cut(strings.TrimSuffix(inner, ">"), ",")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || !scalarTypes[key] || key == "double" || key == "float" || key == "bytes" {
+			return "", nil, fmt.Errorf("invalid map type %q: the key must be an integer type or string", typ)
+		}
+		valueType, types, err := resolveFieldType(files, message, value)
+		if err != nil {
+			return "", nil, err
+		}
+		if strings.HasPrefix(valueType, "map<") {
+			return "", nil, fmt.Errorf("invalid map type %q: map values cannot be maps", typ)
+		}
+		return fmt.Sprintf("map<%s, %s>", key, valueType), types, nil
+	}
+	if scalarTypes[typ] {
+		return typ, nil, nil
+	}
+
+	// Relative names are resolved from the innermost scope outwards, like
+	// the compiler does
+	var desc protoreflect.Descriptor
+	if strings.HasPrefix(typ, ".") {
+		desc = findDescriptor(files, typ)
+	} else {
+		for scope := message.FullName(); desc == nil; scope = scope.Parent() {
+			candidate := typ
+			if scope != "" {
+				candidate = string(scope) + "." + typ
+			}
+			desc = findDescriptor(files, candidate)
+			if scope == "" {
+				break
+			}
+		}
+	}
+	if desc == nil {
+		desc = findWellKnownType(typ)
+	}
+	switch d := desc.(type) {
+	case protoreflect.MessageDescriptor:
+		if !d.IsMapEntry() {
+			return protoutil.RelativeTypeName(message, d.FullName()), []protoreflect.Descriptor{d}, nil
+		}
+	case protoreflect.EnumDescriptor:
+		return protoutil.RelativeTypeName(message, d.FullName()), []protoreflect.Descriptor{d}, nil
+	}
+	return "", nil, fmt.Errorf("type not found: %s (expected a scalar type, a message or enum name, or a map)", typ)
+}
+
+// findWellKnownType finds a well-known type by its full name, such as
+// google.protobuf.Duration. These can be imported by every project, so they
+// are found even when no compiled file imports them yet.
+func findWellKnownType(name string) protoreflect.Descriptor {
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(strings.TrimPrefix(name, ".")))
+	if err != nil || !strings.HasPrefix(desc.ParentFile().Path(), "google/protobuf/") {
+		return nil
+	}
+	return desc
+}
+
+// highestFieldNumber returns the highest number used by the fields of a
+// message
+func highestFieldNumber(message protoreflect.MessageDescriptor) protoreflect.FieldNumber {
+	var highest protoreflect.FieldNumber
+	for i := 0; i < message.Fields().Len(); i++ {
+		highest = max(highest, message.Fields().Get(i).Number())
+	}
+	return highest
+}
+
+// fieldNameWarnings describes the collisions of a new field name with the
+// members and reserved names of a message, and of its JSON name with the
+// JSON names of the existing and reserved fields. A renamed field is
+// passed as except, so that it does not clash with itself.
+func fieldNameWarnings(message protoreflect.MessageDescriptor, name string, except protoreflect.FieldDescriptor) []string {
+	var warnings []string
+	fieldName := protoreflect.Name(name)
+	switch {
+	case message.Fields().ByName(fieldName) != nil:
+		warnings = append(warnings, fmt.Sprintf("%s already has a field named %q", message.Name(), name))
+	case message.Oneofs().ByName(fieldName) != nil, message.Messages().ByName(fieldName) != nil,
+		message.Enums().ByName(fieldName) != nil, message.Extensions().ByName(fieldName) != nil:
+		warnings = append(warnings, fmt.Sprintf("%s already declares %q", message.Name(), name))
+	}
+	if message.ReservedNames().Has(fieldName) {
+		warnings = append(warnings, fmt.Sprintf("Field name %q is reserved in %s; it belonged to a deleted field", name, message.Name()))
+	}
+
+	jsonName := protoutil.DefaultJSONName(name)
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if field.Name() == fieldName || field == except {
+			continue
+		}
+		if field.JSONName() == jsonName || protoutil.DefaultJSONName(string(field.Name())) == jsonName {
+			warnings = append(warnings, fmt.Sprintf("JSON name %q clashes with field %q", jsonName, field.Name()))
+		}
+	}
+	reserved := message.ReservedNames()
+	for i := 0; i < reserved.Len(); i++ {
+		if old := reserved.Get(i); old != fieldName && protoutil.DefaultJSONName(string(old)) == jsonName {
+			warnings = append(warnings, fmt.Sprintf("JSON name %q is also the JSON name of reserved field name %q; JSON written for the deleted field would set the new one", jsonName, old))
+		}
+	}
+	return warnings
+}
+
+// importsFile checks if a file can use the declarations of another, directly
+// or through public imports
+func importsFile(file protoreflect.FileDescriptor, path string) bool {
+	imports := file.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		imported := imports.Get(i)
+		if imported.Path() == path || publiclyImports(imported.FileDescriptor, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// publiclyImports checks if a file re-exports another through public imports
+func publiclyImports(file protoreflect.FileDescriptor, path string) bool {
+	imports := file.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		if imported := imports.Get(i); imported.IsPublic && (imported.Path() == path || publiclyImports(imported.FileDescriptor, path)) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldInsertion returns the edit inserting a field declaration after the
+// last field of a message (or its oneof), past the trailing comment of that
+// field, with the same indentation. The comment is written as leading or
+// trailing line comments or as a block comment, whichever the fields of the
+// message mostly use. documented reports whether every existing field has
+// a comment. Returns nil if the message has no source location.
+func fieldInsertion(message protoreflect.MessageDescriptor, source *sourceLines, declaration, comment string) (edit *textedit.Edit, documented bool) {
+	file := message.ParentFile()
+	locations := file.SourceLocations()
+	messageLoc := locations.ByDescriptor(message)
+	if messageLoc.Path == nil {
+		return nil, false
+	}
+	var anchor protoreflect.SourceLocation
+	anchorIsField := false
+	var leading, trailing, block, commented int
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		loc := locations.ByDescriptor(field)
+		if loc.Path == nil {
+			continue
+		}
+		if loc.LeadingComments != "" {
+			leading++
+			if strings.HasSuffix(strings.TrimSpace(source.line(loc.StartLine-1)), "*/") {
+				block++
+			}
+		}
+		if loc.TrailingComments != "" {
+			trailing++
+		}
+		if loc.LeadingComments != "" || loc.TrailingComments != "" {
+			commented++
+		}
+
+		isField := true
+		if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			if oneofLoc := locations.ByDescriptor(oneof); oneofLoc.Path != nil {
+				loc, isField = oneofLoc, false
+			}
+		}
+		if anchor.Path == nil || loc.EndLine > anchor.EndLine || (loc.EndLine == anchor.EndLine && loc.EndColumn > anchor.EndColumn) {
+			anchor, anchorIsField = loc, isField
+		}
+	}
+	documented = fields.Len() > 0 && commented == fields.Len()
+
+	var commentLines []string
+	if comment != "" {
+		commentLines = strings.Split(comment, "\n")
+	}
+	// A comment containing */ cannot be written as a block comment: it is
+	// written as line comments instead, or with */ broken up when the
+	// declaration shares its line with others
+	blockable := !strings.Contains(comment, "*/")
+	inline := declaration
+	if len(commentLines) > 0 {
+		inline = fmt.Sprintf("/* %s */ %s", strings.ReplaceAll(strings.Join(commentLines, " "), "*/", "* /"), declaration)
+	}
+	// text renders the declaration with its comment on lines of their own
+	text := func(indent string) string {
+		var b strings.Builder
+		switch {
+		case len(commentLines) == 0:
+		case len(commentLines) == 1 && trailing > leading:
+			return indent + declaration + " // " + commentLines[0] + "\n"
+		case blockable && block*2 > leading && len(commentLines) == 1:
+			b.WriteString(indent + "/* " + commentLines[0] + " */\n")
+		case blockable && block*2 > leading:
+			b.WriteString(indent + "/*\n")
+			for _, line := range commentLines {
+				b.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+			}
+			b.WriteString(indent + " */\n")
+		default:
+			for _, line := range commentLines {
+				b.WriteString(strings.TrimRight(indent+"// "+line, " ") + "\n")
+			}
+		}
+		b.WriteString(indent + declaration + "\n")
+		return b.String()
+	}
+	insert := func(line, column int, newText string) *textedit.Edit {
+		edit := lineEdit(file, line, column, line, column, "", newText)
+		return &edit
+	}
+
+	if anchor.Path == nil {
+		// No fields: insert before the closing brace of the message
+		braceLine, braceColumn := messageLoc.EndLine, messageLoc.EndColumn-1
+		before, ok := source.rest(braceLine, 0)
+		if !ok {
+			return nil, documented
+		}
+		if strings.HasPrefix(before, "}") {
+			indent := source.indent(braceLine)
+			unit := "  "
+			if strings.Contains(indent, "\t") {
+				unit = "\t"
+			}
+			return insert(braceLine, 0, text(indent+unit)), documented
+		}
+		return insert(braceLine, braceColumn, " "+inline+" "), documented
+	}
+
+	rest, ok := source.rest(anchor.EndLine, anchor.EndColumn)
+	if !ok {
+		return nil, documented
+	}
+	if rest != "" && !strings.HasPrefix(rest, "//") && !strings.HasPrefix(rest, "/*") {
+		// More declarations follow on the same line
+		return insert(anchor.EndLine, anchor.EndColumn, " "+inline), documented
+	}
+
+	last := source.lastLine(anchor, anchorIsField)
+	if last+1 >= len(source.lines) {
+		return nil, documented
+	}
+	return insert(last+1, 0, text(source.indent(anchor.StartLine))), documented
+}
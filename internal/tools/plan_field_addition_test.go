@@ -0,0 +1,374 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/textedit"
+)
+
+const planFieldAdditionLibrary = `syntax = "proto3";
+
+package library.v1;
+
+import "library/v1/types.proto";
+
+// A book
+message Book {
+  reserved 4, 6 to 8;
+  reserved "old_title";
+
+  // The name of the book
+  string name = 1;
+  // The display name
+  string display_name = 2;
+  oneof format {
+    // A printed book
+    Paperback paperback = 3;
+  }
+
+  message Page {}
+}
+
+message Shelf {
+  string name = 1; // The shelf name
+  int32 size = 2; // Number of books
+                  // on the shelf
+}
+
+message Empty {
+}
+
+message Tag {
+  /* The tag name */
+  string name = 1;
+}
+
+message Note { string text = 1; }
+`
+
+const planFieldAdditionTypes = `syntax = "proto3";
+
+package library.v1;
+
+message Paperback {}
+
+enum Genre {
+  GENRE_UNSPECIFIED = 0;
+}
+`
+
+const planFieldAdditionAuthors = `syntax = "proto3";
+
+package authors.v1;
+
+message Author {}
+`
+
+// applyPlannedEdit applies the planned edit to the library file and returns
+// the result
+func applyPlannedEdit(t *testing.T, root string, response PlanFieldAdditionResponse) string {
+	t.Helper()
+
+	if response.Edit == nil {
+		t.Fatalf("Expected an edit, got %+v", response)
+	}
+	content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(response.Edit.File)))
+	if err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	edited, err := textedit.Apply(content, []textedit.Edit{*response.Edit})
+	if err != nil {
+		t.Fatalf("Failed to apply edit: %v", err)
+	}
+	return string(edited)
+}
+
+func TestPlanFieldAdditionTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewPlanFieldAdditionTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "plan_field_addition" {
+		t.Fatalf("Expected tool name 'plan_field_addition', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestPlanFieldAdditionTool_Handle(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"library/v1/library.proto": planFieldAdditionLibrary,
+		"library/v1/types.proto":   planFieldAdditionTypes,
+		"authors/v1/authors.proto": planFieldAdditionAuthors,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewPlanFieldAdditionTool(mockProjectManager)
+
+	t.Run("leading comments after a oneof", func(t *testing.T) {
+		response := callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+			"message": "library.v1.Book",
+			"name":    "genres",
+			"type":    "Genre",
+			"label":   "repeated",
+			"comment": "The genres of the book",
+		})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+		if response.Number != 9 || response.JSONName != "genres" || response.Declaration != "repeated Genre genres = 9;" {
+			t.Errorf("Unexpected plan: %+v", response)
+		}
+		if len(response.Warnings) != 0 || response.Import != "" {
+			t.Errorf("Expected no warnings, got %v", response.Warnings)
+		}
+
+		expected := strings.Replace(planFieldAdditionLibrary, `    Paperback paperback = 3;
+  }
+`, `    Paperback paperback = 3;
+  }
+  // The genres of the book
+  repeated Genre genres = 9;
+`, 1)
+		if edited := applyPlannedEdit(t, project.ProjectRoot, response); edited != expected {
+			t.Errorf("Unexpected edited file:\n%s", edited)
+		}
+	})
+
+	t.Run("trailing comments and missing import", func(t *testing.T) {
+		response := callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+			"message": "library.v1.Shelf",
+			"name":    "curators",
+			"type":    "map<string, authors.v1.Author>",
+			"comment": "Curators by role",
+		})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+		if response.Declaration != "map<string, authors.v1.Author> curators = 3;" {
+			t.Errorf("Unexpected declaration: %s", response.Declaration)
+		}
+		if response.Import != "authors/v1/authors.proto" || len(response.Warnings) != 1 {
+			t.Errorf("Expected a missing import warning, got %+v", response)
+		}
+
+		expected := strings.Replace(planFieldAdditionLibrary, `                  // on the shelf
+`, `                  // on the shelf
+  map<string, authors.v1.Author> curators = 3; // Curators by role
+`, 1)
+		if edited := applyPlannedEdit(t, project.ProjectRoot, response); edited != expected {
+			t.Errorf("Unexpected edited file:\n%s", edited)
+		}
+	})
+
+	t.Run("well-known type not imported yet", func(t *testing.T) {
+		response := callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+			"message": "library.v1.Shelf",
+			"name":    "ttl",
+			"type":    "google.protobuf.Duration",
+			"comment": "How long books stay",
+		})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+		if response.Declaration != "google.protobuf.Duration ttl = 3;" {
+			t.Errorf("Unexpected declaration: %s", response.Declaration)
+		}
+		if response.Import != "google/protobuf/duration.proto" || len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], "must import") {
+			t.Errorf("Expected a missing import warning, got %+v", response)
+		}
+	})
+
+	t.Run("comment closing a block comment", func(t *testing.T) {
+		response := callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+			"message": "library.v1.Tag",
+			"name":    "pattern",
+			"type":    "string",
+			"comment": "Globs like */*.proto",
+		})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+		expected := strings.Replace(planFieldAdditionLibrary, "  string name = 1;\n}\n\nmessage Note", "  string name = 1;\n  // Globs like */*.proto\n  string pattern = 2;\n}\n\nmessage Note", 1)
+		if edited := applyPlannedEdit(t, project.ProjectRoot, response); edited != expected {
+			t.Errorf("Unexpected edited file:\n%s", edited)
+		}
+
+		// On a shared line the comment stays a block comment, with */ broken up
+		response = callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+			"message": "library.v1.Note",
+			"name":    "pattern",
+			"type":    "string",
+			"comment": "Globs like */*.proto",
+		})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+		expected = strings.Replace(planFieldAdditionLibrary, "string text = 1; }", "string text = 1; /* Globs like * /*.proto */ string pattern = 2; }", 1)
+		if edited := applyPlannedEdit(t, project.ProjectRoot, response); edited != expected {
+			t.Errorf("Unexpected edited file:\n%s", edited)
+		}
+	})
+
+	t.Run("empty message", func(t *testing.T) {
+		response := callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+			"message": "library.v1.Empty",
+			"name":    "page",
+			"type":    ".library.v1.Book.Page",
+		})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+		expected := strings.Replace(planFieldAdditionLibrary, "message Empty {\n", "message Empty {\n  Book.Page page = 1;\n", 1)
+		if edited := applyPlannedEdit(t, project.ProjectRoot, response); edited != expected {
+			t.Errorf("Unexpected edited file:\n%s", edited)
+		}
+	})
+
+	t.Run("name collisions", func(t *testing.T) {
+		response := callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+			"message": "library.v1.Book",
+			"name":    "displayName",
+			"type":    "string",
+		})
+		if !response.Success || len(response.Warnings) != 2 {
+			t.Fatalf("Expected a JSON name clash and an undocumented field warning, got %+v", response)
+		}
+		if !strings.Contains(response.Warnings[0], `clashes with field "display_name"`) {
+			t.Errorf("Expected a JSON name clash, got %s", response.Warnings[0])
+		}
+		if !strings.Contains(response.Warnings[1], "documented") {
+			t.Errorf("Expected a comment warning, got %s", response.Warnings[1])
+		}
+
+		response = callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+			"message": "library.v1.Book",
+			"name":    "oldTitle",
+			"type":    "string",
+			"comment": "The old title",
+		})
+		if len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], `reserved field name "old_title"`) {
+			t.Errorf("Expected a reserved JSON name warning, got %v", response.Warnings)
+		}
+
+		response = callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+			"message": "library.v1.Book",
+			"name":    "old_title",
+			"type":    "string",
+			"comment": "The old title",
+		})
+		if len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], "is reserved") {
+			t.Errorf("Expected a reserved name warning, got %v", response.Warnings)
+		}
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		response := callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+			"message": "library.v1.Book",
+			"name":    "author",
+			"type":    "Author",
+		})
+		if response.Success {
+			t.Errorf("Expected success=false for an unknown type")
+		}
+	})
+
+	t.Run("unknown message", func(t *testing.T) {
+		response := callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+			"message": "library.v1.Missing",
+			"name":    "author",
+			"type":    "string",
+		})
+		if response.Success {
+			t.Errorf("Expected success=false for an unknown message")
+		}
+	})
+}
+
+func TestPlanFieldAdditionTool_Gap(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"test.proto": `syntax = "proto2";
+
+package test.v1;
+
+message Full {
+  optional string name = 2;
+  reserved 3 to max;
+}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewPlanFieldAdditionTool(mockProjectManager)
+
+	response := callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+		"message": "test.v1.Full",
+		"name":    "id",
+		"type":    "int64",
+	})
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+	if response.Declaration != "optional int64 id = 1;" {
+		t.Errorf("Expected the gap to be used with the proto2 label, got %s", response.Declaration)
+	}
+	if len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], "deleted field") {
+		t.Errorf("Expected a gap warning, got %v", response.Warnings)
+	}
+}
+
+func TestPlanFieldAdditionTool_InvalidParams(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewPlanFieldAdditionTool(mockProjectManager)
+
+	for _, arguments := range []map[string]interface{}{
+		{"name": "id", "type": "string"},
+		{"message": "test.v1.Book", "name": "1id", "type": "string"},
+		{"message": "test.v1.Book", "name": "id"},
+		{"message": "test.v1.Book", "name": "id", "type": "string", "label": "required"},
+	} {
+		req := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "plan_field_addition",
+				Arguments: arguments,
+			},
+		}
+		result, err := tool.Handle(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("Expected an error result for %v", arguments)
+		}
+	}
+}
+
+func TestPlanFieldAdditionTool_NoProject(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewPlanFieldAdditionTool(mockProjectManager)
+
+	response := callTool[PlanFieldAdditionResponse](t, tool.Handle, "plan_field_addition", map[string]interface{}{
+		"message": "test.v1.Book",
+		"name":    "id",
+		"type":    "string",
+	})
+	if response.Success {
+		t.Errorf("Expected success=false without an activated project")
+	}
+}
@@ -0,0 +1,508 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bufbuild/protocompile/linker"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+	"github.com/yuemori/protobuf-mcp-server/internal/textedit"
+)
+
+// Field reference kinds reported by plan_field_removal
+const (
+	fieldReferenceKindHTTPPath         = "http_path"
+	fieldReferenceKindHTTPBody         = "http_body"
+	fieldReferenceKindHTTPResponseBody = "http_response_body"
+	fieldReferenceKindOption           = "option"
+	fieldReferenceKindComment          = "comment"
+)
+
+// PlanFieldRemovalTool implements the plan_field_removal MCP tool using mcp-go
+type PlanFieldRemovalTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewPlanFieldRemovalTool creates a new PlanFieldRemovalTool instance
+func NewPlanFieldRemovalTool(projectManager ProjectManagerInterface) *PlanFieldRemovalTool {
+	return &PlanFieldRemovalTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *PlanFieldRemovalTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"plan_field_removal",
+		mcp.WithDescription("Plan removing a field from a message: reports what still refers to the field by name (google.api.http path variables, body and response_body bindings, option values such as resource patterns or validation expressions, and mentions in the comments of the message, of the messages embedding it, of the RPCs using them, and field paths naming it in the field mask and string fields of their requests and responses), and returns the edits deleting the field with its comments and adding 'reserved N;' and 'reserved \"name\";' so its number and name are never reused. Nothing is written"),
+		mcp.WithString("field",
+			mcp.Required(),
+			mcp.Description("Fully-qualified name of the field (e.g. 'example.simple.v1.User.email')"),
+		),
+	)
+}
+
+// PlanFieldRemovalResponse represents the response from plan_field_removal tool
+type PlanFieldRemovalResponse struct {
+	Success    bool                 `json:"success"`
+	Message    string               `json:"message"`
+	Field      string               `json:"field,omitempty"`
+	Number     int32                `json:"number,omitempty"`
+	JSONName   string               `json:"json_name,omitempty"`
+	References []FieldReferenceInfo `json:"references,omitempty"`
+	Edits      []textedit.Edit      `json:"edits,omitempty"`
+	Warnings   []string             `json:"warnings,omitempty"`
+}
+
+// FieldReferenceInfo represents a place that refers to a field by name.
+// Element is the declaration holding the reference and Detail the option
+// value, binding or comment line that mentions the field.
+type FieldReferenceInfo struct {
+	Kind     string          `json:"kind"`
+	Element  string          `json:"element"`
+	Detail   string          `json:"detail"`
+	Location *protoutil.Span `json:"location,omitempty"`
+}
+
+// Handle handles the tool execution
+func (t *PlanFieldRemovalTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	fieldName := req.GetString("field", "")
+	if fieldName == "" {
+		return mcp.NewToolResultError("field parameter is required"), nil
+	}
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &PlanFieldRemovalResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &PlanFieldRemovalResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	field, ok := findDescriptor(files, fieldName).(protoreflect.FieldDescriptor)
+	if !ok || field.ContainingMessage().IsMapEntry() {
+		response := &PlanFieldRemovalResponse{
+			Success: false,
+			Message: fmt.Sprintf("Field not found: %s", fieldName),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+	if field.IsExtension() {
+		response := &PlanFieldRemovalResponse{
+			Success: false,
+			Message: fmt.Sprintf("%s is an extension; extension numbers cannot be reserved", field.FullName()),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	source, err := readSourceLines(project, field.ParentFile().Path())
+	if err != nil {
+		response := &PlanFieldRemovalResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	edits, removedOneof := fieldRemovalEdits(field, source)
+	if edits == nil {
+		response := &PlanFieldRemovalResponse{
+			Success: false,
+			Message: fmt.Sprintf("No source location for %s; the file changed since it was compiled", field.FullName()),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	response := &PlanFieldRemovalResponse{
+		Success:    true,
+		Field:      string(field.FullName()),
+		Number:     int32(field.Number()),
+		JSONName:   field.JSONName(),
+		References: fieldReferences(files, field),
+		Edits:      edits,
+	}
+	if removedOneof != nil {
+		response.Warnings = append(response.Warnings, fmt.Sprintf("%s is the only field of oneof %q, so the oneof is removed too", field.Name(), removedOneof.Name()))
+	}
+	if field.Cardinality() == protoreflect.Required {
+		response.Warnings = append(response.Warnings, fmt.Sprintf("%s is required; readers built before the removal reject messages without it", field.Name()))
+	}
+
+	response.Message = fmt.Sprintf("Remove %s and reserve number %d and name %q", field.FullName(), field.Number(), field.Name())
+	if count := len(response.References); count > 0 {
+		response.Message += fmt.Sprintf("; %d references to the field must be updated first", count)
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// fieldReferences finds the HTTP bindings, option values and comments that
+// refer to a field by name. Options and comments are searched on the
+// message declaring the field, the messages with fields of its type and the
+// methods taking or returning any of them. The other messages of those
+// methods, such as a request with a field mask, are only searched on their
+// field mask and string fields, for the name quoted or in a dotted path.
+func fieldReferences(files linker.Files, field protoreflect.FieldDescriptor) []FieldReferenceInfo {
+	message := field.ContainingMessage()
+	related := map[protoreflect.FullName]bool{message.FullName(): true}
+	var declarations, pathDeclarations []protoreflect.Descriptor
+	addMessage := func(m protoreflect.MessageDescriptor) {
+		declarations = append(declarations, m)
+		for i := 0; i < m.Fields().Len(); i++ {
+			if f := m.Fields().Get(i); f != field {
+				declarations = append(declarations, f)
+			}
+		}
+		for i := 0; i < m.Oneofs().Len(); i++ {
+			if oneof := m.Oneofs().Get(i); !oneof.IsSynthetic() {
+				declarations = append(declarations, oneof)
+			}
+		}
+	}
+	addMessage(message)
+	for _, file := range allFiles(files) {
+		forEachMessage(file, func(m protoreflect.MessageDescriptor) {
+			if !related[m.FullName()] && !m.IsMapEntry() && embeds(m, message) {
+				related[m.FullName()] = true
+				addMessage(m)
+			}
+		})
+	}
+
+	var references []FieldReferenceInfo
+	add := func(kind string, desc protoreflect.Descriptor, detail string) {
+		references = append(references, FieldReferenceInfo{
+			Kind:     kind,
+			Element:  string(desc.FullName()),
+			Detail:   detail,
+			Location: protoutil.SpanOf(desc),
+		})
+	}
+
+	for _, file := range allFiles(files) {
+		for i := 0; i < file.Services().Len(); i++ {
+			methods := file.Services().Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				method := methods.Get(j)
+				for _, binding := range protoutil.HTTPBindings(method) {
+					for _, variable := range protoutil.PathVariables(binding.Path) {
+						if fieldPathReaches(method.Input(), variable, field) {
+							add(fieldReferenceKindHTTPPath, method, fmt.Sprintf("%s %s binds {%s}", binding.Method, binding.Path, variable))
+						}
+					}
+					if binding.Body != "*" && fieldPathReaches(method.Input(), binding.Body, field) {
+						add(fieldReferenceKindHTTPBody, method, fmt.Sprintf("body: %q", binding.Body))
+					}
+					if fieldPathReaches(method.Output(), binding.ResponseBody, field) {
+						add(fieldReferenceKindHTTPResponseBody, method, fmt.Sprintf("response_body: %q", binding.ResponseBody))
+					}
+				}
+				if related[method.Input().FullName()] || related[method.Output().FullName()] {
+					declarations = append(declarations, method)
+					for _, m := range []protoreflect.MessageDescriptor{method.Input(), method.Output()} {
+						if related[m.FullName()] {
+							continue
+						}
+						related[m.FullName()] = true
+						for k := 0; k < m.Fields().Len(); k++ {
+							if f := m.Fields().Get(k); isPathField(f) {
+								pathDeclarations = append(pathDeclarations, f)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	search := func(declarations []protoreflect.Descriptor, mentions *regexp.Regexp) {
+		for _, desc := range declarations {
+			forEachStringOption(desc, func(option, value string) {
+				if option != protoutil.HTTPOptionName && mentions.MatchString(value) {
+					add(fieldReferenceKindOption, desc, fmt.Sprintf("(%s) = %q", option, value))
+				}
+			})
+
+			loc := desc.ParentFile().SourceLocations().ByDescriptor(desc)
+			comments := append([]string{loc.LeadingComments, loc.TrailingComments}, loc.LeadingDetachedComments...)
+			for _, comment := range comments {
+				for _, line := range strings.Split(comment, "\n") {
+					if line = strings.TrimSpace(line); mentions.MatchString(line) {
+						add(fieldReferenceKindComment, desc, line)
+					}
+				}
+			}
+		}
+	}
+	search(declarations, namePattern(string(field.Name()), field.JSONName()))
+	search(pathDeclarations, pathPattern(string(field.Name()), field.JSONName()))
+	return references
+}
+
+// isPathField checks if a field can hold field paths: a field mask, or a
+// string such as a comma-separated mask or an order_by expression
+func isPathField(field protoreflect.FieldDescriptor) bool {
+	if field.Message() != nil {
+		return field.Message().FullName() == "google.protobuf.FieldMask"
+	}
+	return field.Kind() == protoreflect.StringKind
+}
+
+// embeds checks if a message has a field, or map value, of another message
+func embeds(message, embedded protoreflect.MessageDescriptor) bool {
+	for i := 0; i < message.Fields().Len(); i++ {
+		field := message.Fields().Get(i)
+		if field.IsMap() {
+			field = field.MapValue()
+		}
+		if field.Message() != nil && field.Message().FullName() == embedded.FullName() {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldPathReaches checks if a dotted field path, as used by HTTP bindings,
+// selects or passes through a field, starting from a message
+func fieldPathReaches(message protoreflect.MessageDescriptor, path string, field protoreflect.FieldDescriptor) bool {
+	if path == "" {
+		return false
+	}
+	for _, name := range strings.Split(path, ".") {
+		if message == nil {
+			return false
+		}
+		current := message.Fields().ByName(protoreflect.Name(strings.TrimSpace(name)))
+		if current == nil {
+			return false
+		}
+		if current.FullName() == field.FullName() {
+			return true
+		}
+		message = current.Message()
+	}
+	return false
+}
+
+// namePattern matches text mentioning any of the names as a whole word,
+// including within dotted paths such as field masks
+func namePattern(names ...string) *regexp.Regexp {
+	var quoted []string
+	for _, name := range names {
+		if name != "" {
+			quoted = append(quoted, regexp.QuoteMeta(name))
+		}
+	}
+	return regexp.MustCompile(`(^|[^A-Za-z0-9_])(` + strings.Join(quoted, "|") + `)($|[^A-Za-z0-9_])`)
+}
+
+// pathPattern matches text mentioning any of the names as a field path:
+// quoted, as in "title", or within a dotted path, as in book.title
+func pathPattern(names ...string) *regexp.Regexp {
+	var quoted []string
+	for _, name := range names {
+		if name != "" {
+			quoted = append(quoted, regexp.QuoteMeta(name))
+		}
+	}
+	name := `(` + strings.Join(quoted, "|") + `)`
+	return regexp.MustCompile(`["'` + "`" + `]` + name + `["'` + "`" + `]` +
+		`|[A-Za-z0-9_]\.` + name + `($|[^A-Za-z0-9_])` +
+		`|(^|[^A-Za-z0-9_.])` + name + `\.[A-Za-z_]`)
+}
+
+// forEachStringOption calls fn with every string value set in the options
+// of a declaration, including those nested in message-valued options,
+// together with the full name of the option holding it
+func forEachStringOption(desc protoreflect.Descriptor, fn func(option, value string)) {
+	options := desc.Options()
+	if options == nil {
+		return
+	}
+	var visit func(option string, message protoreflect.Message)
+	visit = func(option string, message protoreflect.Message) {
+		message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+			name := option
+			if name == "" {
+				name = string(field.FullName())
+				if !field.IsExtension() {
+					name = string(field.Name())
+				}
+			}
+			var values []protoreflect.Value
+			switch {
+			case field.IsMap():
+				value.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+					values = append(values, v)
+					return true
+				})
+				field = field.MapValue()
+			case field.IsList():
+				for i := 0; i < value.List().Len(); i++ {
+					values = append(values, value.List().Get(i))
+				}
+			default:
+				values = []protoreflect.Value{value}
+			}
+			for _, v := range values {
+				switch field.Kind() {
+				case protoreflect.StringKind:
+					fn(name, v.String())
+				case protoreflect.MessageKind, protoreflect.GroupKind:
+					visit(name, v.Message())
+				}
+			}
+			return true
+		})
+	}
+	visit("", options.ProtoReflect())
+}
+
+// fieldRemovalEdits returns the edits deleting a field with its comments and
+// reserving its number and name. A field that is the only member of a
+// oneof is removed with its oneof, which is returned. The reserved
+// statements follow the last reserved statement of the message. If there is
+// none they follow the oneof of the field, or replace the removed
+// declaration outside oneofs. Returns nil if the field has no source
+// location.
+func fieldRemovalEdits(field protoreflect.FieldDescriptor, source *sourceLines) ([]textedit.Edit, protoreflect.OneofDescriptor) {
+	file := field.ParentFile()
+	message := field.ContainingMessage()
+	locations := file.SourceLocations()
+
+	var removedOneof protoreflect.OneofDescriptor
+	var removed protoreflect.Descriptor = field
+	if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() && oneof.Fields().Len() == 1 {
+		removedOneof, removed = oneof, oneof
+	}
+	loc := locations.ByDescriptor(removed)
+	messageLoc := locations.ByDescriptor(message)
+	if loc.Path == nil || messageLoc.Path == nil {
+		return nil, nil
+	}
+
+	reservedName := fmt.Sprintf("%q", field.Name())
+	if file.Syntax() == protoreflect.Editions {
+		reservedName = string(field.Name())
+	}
+	statements := []string{fmt.Sprintf("reserved %d;", field.Number()), fmt.Sprintf("reserved %s;", reservedName)}
+
+	// Find the last reserved statement of the message
+	var reserved protoreflect.SourceLocation
+	for i := 0; i < locations.Len(); i++ {
+		candidate := locations.Get(i)
+		if len(candidate.Path) != len(messageLoc.Path)+1 || !hasPathPrefix(candidate.Path, messageLoc.Path) {
+			continue
+		}
+		if element := candidate.Path[len(candidate.Path)-1]; element != messageReservedRangesPathElement && element != messageReservedNamesPathElement {
+			continue
+		}
+		if reserved.Path == nil || candidate.EndLine > reserved.EndLine {
+			reserved = candidate
+		}
+	}
+
+	var edits []textedit.Edit
+	var replacement []string
+	oneof := field.ContainingOneof()
+	switch {
+	case reserved.Path != nil && reserved.EndLine+1 < len(source.lines):
+		indent := source.indent(reserved.StartLine)
+		text := indent + statements[0] + "\n" + indent + statements[1] + "\n"
+		edits = append(edits, lineEdit(file, reserved.EndLine+1, 0, reserved.EndLine+1, 0, "", text))
+	case oneof != nil && !oneof.IsSynthetic() && removedOneof == nil:
+		// The oneof keeps other fields and cannot hold reserved statements,
+		// so they go after it
+		oneofLoc := locations.ByDescriptor(oneof)
+		if oneofLoc.Path == nil || oneofLoc.EndLine+1 >= len(source.lines) {
+			return nil, nil
+		}
+		indent := source.indent(oneofLoc.StartLine)
+		text := indent + statements[0] + "\n" + indent + statements[1] + "\n"
+		edits = append(edits, lineEdit(file, oneofLoc.EndLine+1, 0, oneofLoc.EndLine+1, 0, "", text))
+	default:
+		replacement = statements
+	}
+
+	startOffset, ok := source.offset(loc.StartLine, loc.StartColumn)
+	lineOffset, _ := source.offset(loc.StartLine, 0)
+	endOffset, ok2 := source.offset(loc.EndLine, loc.EndColumn)
+	rest, ok3 := source.rest(loc.EndLine, loc.EndColumn)
+	if !ok || !ok2 || !ok3 {
+		return nil, nil
+	}
+	before := strings.TrimSpace(string(source.content[lineOffset:startOffset]))
+	if before != "" || (rest != "" && !strings.HasPrefix(rest, "//") && !strings.HasPrefix(rest, "/*")) {
+		// The declaration shares its line with others: remove it alone
+		oldText := string(source.content[startOffset:endOffset])
+		edits = append(edits, lineEdit(file, loc.StartLine, loc.StartColumn, loc.EndLine, loc.EndColumn, oldText, strings.Join(replacement, " ")))
+		return edits, removedOneof
+	}
+
+	first, last := source.firstLine(loc), source.lastLine(loc, removedOneof == nil)
+	if last+1 >= len(source.lines) {
+		return nil, nil
+	}
+	firstOffset, _ := source.offset(first, 0)
+	nextOffset, _ := source.offset(last+1, 0)
+	var text strings.Builder
+	for _, statement := range replacement {
+		text.WriteString(source.indent(loc.StartLine) + statement + "\n")
+	}
+	edits = append(edits, lineEdit(file, first, 0, last+1, 0, string(source.content[firstOffset:nextOffset]), text.String()))
+	return edits, removedOneof
+}
+
+// hasPathPrefix checks if a source path starts with another
+func hasPathPrefix(path, prefix protoreflect.SourcePath) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// lineEdit builds an edit of a 0-based range of a file
+func lineEdit(file protoreflect.FileDescriptor, startLine, startColumn, endLine, endColumn int, oldText, newText string) textedit.Edit {
+	return textedit.Edit{
+		File:        file.Path(),
+		StartLine:   startLine + 1,
+		StartColumn: startColumn + 1,
+		EndLine:     endLine + 1,
+		EndColumn:   endColumn + 1,
+		OldText:     oldText,
+		NewText:     newText,
+	}
+}
@@ -0,0 +1,259 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/textedit"
+)
+
+const planFieldRemovalLibrary = `syntax = "proto3";
+
+package library.v1;
+
+import "google/api/annotations.proto";
+import "google/protobuf/descriptor.proto";
+
+extend google.protobuf.MessageOptions {
+  string pattern = 50000;
+}
+
+service Library {
+  // Updates a book; the mask may name title and author.name
+  rpc UpdateBook(UpdateBookRequest) returns (Book) {
+    option (google.api.http) = {
+      patch: "/v1/{book.shelf}/books/{book.id}"
+      body: "book"
+    };
+  }
+  rpc ListBooks(ListBooksRequest) returns (ListBooksResponse);
+  rpc GetTitle(Book) returns (Book) {
+    option (google.api.http) = {
+      get: "/v1/books/{id}"
+      response_body: "title"
+    };
+  }
+}
+
+// A book
+message Book {
+  option (pattern) = "shelves/{shelf}/books/{id}";
+
+  reserved 9;
+
+  string id = 1;
+  string shelf = 2;
+  // The title
+  string title = 3; // Shown in listings
+  oneof cover {
+    bytes image = 4;
+  }
+}
+
+message UpdateBookRequest {
+  Book book = 1;
+  // Fields to update, e.g. "title"
+  repeated string update_mask = 2;
+}
+
+message ListBooksRequest {
+  // At most this many books, whatever their title
+  int32 page_size = 1;
+  // Sort order, e.g. book.title
+  string order_by = 2;
+}
+
+message ListBooksResponse {
+  repeated Book books = 1;
+}
+
+message Inline { string name = 1; int32 size = 2; }
+
+message Choice {
+  oneof choice {
+    string a = 2;
+    string b = 3;
+  }
+}
+`
+
+func TestPlanFieldRemovalTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewPlanFieldRemovalTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "plan_field_removal" {
+		t.Fatalf("Expected tool name 'plan_field_removal', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestPlanFieldRemovalTool_Handle(t *testing.T) {
+	sources := map[string]string{"library/v1/library.proto": planFieldRemovalLibrary}
+	for _, file := range []string{"google/api/annotations.proto", "google/api/http.proto"} {
+		content, err := os.ReadFile(filepath.Join("testdata", filepath.FromSlash(file)))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		sources[file] = string(content)
+	}
+	project, err := CreateTempProject(t, sources)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewPlanFieldRemovalTool(mockProjectManager)
+
+	apply := func(t *testing.T, edits []textedit.Edit) string {
+		t.Helper()
+		edited, err := textedit.Apply([]byte(planFieldRemovalLibrary), edits)
+		if err != nil {
+			t.Fatalf("Failed to apply edits: %v", err)
+		}
+		return string(edited)
+	}
+
+	t.Run("references", func(t *testing.T) {
+		response := callTool[PlanFieldRemovalResponse](t, tool.Handle, "plan_field_removal", map[string]interface{}{"field": "library.v1.Book.title"})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+		if response.Number != 3 || response.JSONName != "title" {
+			t.Errorf("Unexpected field: %+v", response)
+		}
+
+		var kinds []string
+		for _, reference := range response.References {
+			kinds = append(kinds, reference.Kind+" "+reference.Element)
+		}
+		expected := []string{
+			"http_response_body library.v1.Library.GetTitle",
+			"comment library.v1.UpdateBookRequest.update_mask",
+			"comment library.v1.Library.UpdateBook",
+			"comment library.v1.ListBooksRequest.order_by",
+		}
+		if strings.Join(kinds, ", ") != strings.Join(expected, ", ") {
+			t.Errorf("Expected references %v, got %v", expected, kinds)
+		}
+
+		expectedFile := strings.Replace(planFieldRemovalLibrary, `  reserved 9;
+`, `  reserved 9;
+  reserved 3;
+  reserved "title";
+`, 1)
+		expectedFile = strings.Replace(expectedFile, `  // The title
+  string title = 3; // Shown in listings
+`, "", 1)
+		if edited := apply(t, response.Edits); edited != expectedFile {
+			t.Errorf("Unexpected edited file:\n%s", edited)
+		}
+	})
+
+	t.Run("path and option references", func(t *testing.T) {
+		response := callTool[PlanFieldRemovalResponse](t, tool.Handle, "plan_field_removal", map[string]interface{}{"field": "library.v1.Book.shelf"})
+		var kinds []string
+		for _, reference := range response.References {
+			kinds = append(kinds, reference.Kind+" "+reference.Element)
+		}
+		expected := []string{
+			"http_path library.v1.Library.UpdateBook",
+			"option library.v1.Book",
+		}
+		if strings.Join(kinds, ", ") != strings.Join(expected, ", ") {
+			t.Errorf("Expected references %v, got %v", expected, kinds)
+		}
+
+		response = callTool[PlanFieldRemovalResponse](t, tool.Handle, "plan_field_removal", map[string]interface{}{"field": "library.v1.UpdateBookRequest.book"})
+		kinds = nil
+		for _, reference := range response.References {
+			kinds = append(kinds, reference.Kind+" "+reference.Detail)
+		}
+		expected = []string{
+			"http_path PATCH /v1/{book.shelf}/books/{book.id} binds {book.shelf}",
+			"http_path PATCH /v1/{book.shelf}/books/{book.id} binds {book.id}",
+			`http_body body: "book"`,
+			"comment Updates a book; the mask may name title and author.name",
+		}
+		if strings.Join(kinds, ", ") != strings.Join(expected, ", ") {
+			t.Errorf("Expected references %v, got %v", expected, kinds)
+		}
+	})
+
+	t.Run("only member of a oneof", func(t *testing.T) {
+		response := callTool[PlanFieldRemovalResponse](t, tool.Handle, "plan_field_removal", map[string]interface{}{"field": "library.v1.Book.image"})
+		if !response.Success || len(response.Warnings) != 1 {
+			t.Fatalf("Expected a oneof warning, got %+v", response)
+		}
+		expectedFile := strings.Replace(planFieldRemovalLibrary, `  reserved 9;
+`, `  reserved 9;
+  reserved 4;
+  reserved "image";
+`, 1)
+		expectedFile = strings.Replace(expectedFile, `  oneof cover {
+    bytes image = 4;
+  }
+`, "", 1)
+		if edited := apply(t, response.Edits); edited != expectedFile {
+			t.Errorf("Unexpected edited file:\n%s", edited)
+		}
+	})
+
+	t.Run("without reserved statements", func(t *testing.T) {
+		response := callTool[PlanFieldRemovalResponse](t, tool.Handle, "plan_field_removal", map[string]interface{}{"field": "library.v1.UpdateBookRequest.update_mask"})
+		expectedFile := strings.Replace(planFieldRemovalLibrary, `  // Fields to update, e.g. "title"
+  repeated string update_mask = 2;
+`, `  reserved 2;
+  reserved "update_mask";
+`, 1)
+		if edited := apply(t, response.Edits); edited != expectedFile {
+			t.Errorf("Unexpected edited file:\n%s", edited)
+		}
+
+		response = callTool[PlanFieldRemovalResponse](t, tool.Handle, "plan_field_removal", map[string]interface{}{"field": "library.v1.Inline.name"})
+		expectedFile = strings.Replace(planFieldRemovalLibrary, `message Inline { string name = 1; int32 size = 2; }`, `message Inline { reserved 1; reserved "name"; int32 size = 2; }`, 1)
+		if edited := apply(t, response.Edits); edited != expectedFile {
+			t.Errorf("Unexpected edited file:\n%s", edited)
+		}
+
+		// Reserved statements are not allowed in the oneof keeping b
+		response = callTool[PlanFieldRemovalResponse](t, tool.Handle, "plan_field_removal", map[string]interface{}{"field": "library.v1.Choice.a"})
+		expectedFile = strings.Replace(planFieldRemovalLibrary, `  oneof choice {
+    string a = 2;
+    string b = 3;
+  }
+`, `  oneof choice {
+    string b = 3;
+  }
+  reserved 2;
+  reserved "a";
+`, 1)
+		if edited := apply(t, response.Edits); edited != expectedFile {
+			t.Errorf("Unexpected edited file:\n%s", edited)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		for _, field := range []string{"library.v1.Book.missing", "library.v1.Book", "library.v1.pattern"} {
+			if response := callTool[PlanFieldRemovalResponse](t, tool.Handle, "plan_field_removal", map[string]interface{}{"field": field}); response.Success {
+				t.Errorf("Expected success=false for %s", field)
+			}
+		}
+	})
+}
+
+func TestPlanFieldRemovalTool_NoProject(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewPlanFieldRemovalTool(mockProjectManager)
+
+	response := callTool[PlanFieldRemovalResponse](t, tool.Handle, "plan_field_removal", map[string]interface{}{"field": "test.v1.Book.id"})
+	if response.Success {
+		t.Errorf("Expected success=false without an activated project")
+	}
+}
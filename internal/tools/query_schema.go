@@ -0,0 +1,363 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// QuerySchemaTool implements the query_schema MCP tool using mcp-go
+type QuerySchemaTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewQuerySchemaTool creates a new QuerySchemaTool instance
+func NewQuerySchemaTool(projectManager ProjectManagerInterface) *QuerySchemaTool {
+	return &QuerySchemaTool{
+		projectManager: projectManager,
+	}
+}
+
+// queryKeys documents the keys understood by the query language, used in the
+// tool description and in parse errors
+var queryKeys = []string{
+	"kind", "name", "full_name", "package", "file", "type", "label", "number",
+	"option", "comment", "streaming", "http", "input", "output",
+}
+
+// GetTool returns the MCP tool definition
+func (t *QuerySchemaTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"query_schema",
+		mcp.WithDescription("Query schema elements with a small filter language and return only the matching declarations. "+
+			"Terms are key:value pairs combined with AND; prefix a term with '-' to negate it and quote values containing spaces. "+
+			"Values of name, full_name, package, file, type, input, output and option values accept * globs. "+
+			"Keys: kind (message, field, oneof, enum, enum_value, service, method, extension), name, full_name, package, file, "+
+			"type (scalar kind, 'map', or message/enum full name), label (optional, required, repeated, map), number, "+
+			"option (name or name=value), comment (text in comments; empty for any comment), "+
+			"streaming (client, server, bidi, any, none), http (true/false), input, output. "+
+			"A bare word is matched against names. "+
+			"Examples: 'kind:field type:int64 name:*_id', 'kind:method streaming:any http:false', 'option:deprecated=true package:example.*'"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Query (e.g. 'kind:field type:string option:deprecated=true package:example.*')"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of results to return (default 100)"),
+		),
+	)
+}
+
+// QuerySchemaResponse represents the response from query_schema tool
+type QuerySchemaResponse struct {
+	Success   bool          `json:"success"`
+	Message   string        `json:"message"`
+	Query     string        `json:"query,omitempty"`
+	Results   []QueryResult `json:"results,omitempty"`
+	Count     int           `json:"count"`
+	Truncated bool          `json:"truncated"`
+}
+
+// QueryResult represents a declaration matching a query. Type is the field
+// type for fields and extensions ("map<K, V>" for maps, the full name for
+// message and enum types) and the signature for methods.
+type QueryResult struct {
+	Kind     string          `json:"kind"`
+	FullName string          `json:"full_name"`
+	Type     string          `json:"type,omitempty"`
+	Location *protoutil.Span `json:"location,omitempty"`
+}
+
+// queryTerm is a single key:value filter of a query
+type queryTerm struct {
+	key    string
+	value  string
+	negate bool
+}
+
+// Handle handles the tool execution
+func (t *QuerySchemaTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := req.GetString("query", "")
+	if query == "" {
+		return mcp.NewToolResultError("query parameter is required"), nil
+	}
+	limit := req.GetInt("limit", 100)
+
+	terms, err := parseQuery(query)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid query: %v", err)), nil
+	}
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &QuerySchemaResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &QuerySchemaResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	results := []QueryResult{}
+	truncated := false
+	for _, file := range files {
+		forEachDeclaration(file, func(desc protoreflect.Descriptor) {
+			if !matchesQuery(desc, terms) {
+				return
+			}
+			if limit > 0 && len(results) >= limit {
+				truncated = true
+				return
+			}
+			results = append(results, QueryResult{
+				Kind:     descriptorKind(desc),
+				FullName: string(desc.FullName()),
+				Type:     queryResultType(desc),
+				Location: protoutil.SpanOf(desc),
+			})
+		})
+	}
+
+	response := &QuerySchemaResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("Found %d matching elements", len(results)),
+		Query:     query,
+		Results:   results,
+		Count:     len(results),
+		Truncated: truncated,
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// parseQuery splits a query into terms. Terms are separated by whitespace
+// outside double quotes; a bare word becomes a name term.
+func parseQuery(query string) ([]queryTerm, error) {
+	var words []string
+	var current strings.Builder
+	inQuotes := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case (r == ' ' || r == '\t' || r == '\n') && !inQuotes:
+			if current.Len() > 0 {
+				words = append(words, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if current.Len() > 0 {
+		words = append(words, current.String())
+	}
+
+	terms := make([]queryTerm, 0, len(words))
+	for _, word := range words {
+		term := queryTerm{}
+		if strings.HasPrefix(word, "-") {
+			term.negate = true
+			word = word[1:]
+		}
+		key, value, found := strings.Cut(word, ":")
+		if !found {
+			key, value = "name", key
+		}
+		term.key = strings.ToLower(key)
+		term.value = value
+		if !slices.Contains(queryKeys, term.key) {
+			return nil, fmt.Errorf("unknown key %q (expected one of %s)", key, strings.Join(queryKeys, ", "))
+		}
+		if _, err := path.Match(term.value, ""); err != nil && term.key != "comment" && term.key != "option" {
+			return nil, fmt.Errorf("invalid pattern %q for %s: %v", term.value, term.key, err)
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+	return terms, nil
+}
+
+// matchesQuery checks if a declaration satisfies every term of a query. A
+// term that does not apply to the declaration's kind (e.g. streaming on a
+// field) does not match, so negating it matches.
+func matchesQuery(desc protoreflect.Descriptor, terms []queryTerm) bool {
+	for _, term := range terms {
+		if matchesQueryTerm(desc, term) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesQueryTerm evaluates a single term against a declaration, ignoring negation
+func matchesQueryTerm(desc protoreflect.Descriptor, term queryTerm) bool {
+	switch term.key {
+	case "kind":
+		return descriptorKind(desc) == term.value
+	case "name":
+		return globMatch(term.value, string(desc.Name()))
+	case "full_name":
+		return globMatch(term.value, string(desc.FullName()))
+	case "package":
+		return matchesPackage(string(desc.ParentFile().Package()), term.value)
+	case "file":
+		return globMatch(term.value, desc.ParentFile().Path())
+	case "comment":
+		description := strings.ToLower(descriptionOf(desc, false))
+		return description != "" && strings.Contains(description, strings.ToLower(term.value))
+	case "option":
+		return matchesOptionTerm(desc, term.value)
+	case "number":
+		number, err := strconv.ParseInt(term.value, 10, 32)
+		if err != nil {
+			return false
+		}
+		switch d := desc.(type) {
+		case protoreflect.FieldDescriptor:
+			return int64(d.Number()) == number
+		case protoreflect.EnumValueDescriptor:
+			return int64(d.Number()) == number
+		}
+		return false
+	}
+
+	if field, ok := desc.(protoreflect.FieldDescriptor); ok {
+		switch term.key {
+		case "type":
+			if field.IsMap() {
+				return term.value == "map"
+			}
+			return globMatch(term.value, field.Kind().String()) ||
+				(typeNameOf(field) != "" && globMatch(term.value, typeNameOf(field)))
+		case "label":
+			switch term.value {
+			case "map":
+				return field.IsMap()
+			case "repeated":
+				return field.IsList()
+			case "required":
+				return field.Cardinality() == protoreflect.Required
+			case "optional":
+				return field.HasOptionalKeyword()
+			}
+		}
+		return false
+	}
+
+	if method, ok := desc.(protoreflect.MethodDescriptor); ok {
+		switch term.key {
+		case "streaming":
+			mode := streamingMode(method)
+			switch term.value {
+			case "client":
+				return method.IsStreamingClient()
+			case "server":
+				return method.IsStreamingServer()
+			case "bidi":
+				return mode == "bidi_streaming"
+			case "any", "true":
+				return mode != "unary"
+			case "none", "false":
+				return mode == "unary"
+			}
+		case "http":
+			hasBinding := len(protoutil.HTTPBindings(method)) > 0
+			return strconv.FormatBool(hasBinding) == term.value
+		case "input":
+			return globMatch(term.value, string(method.Input().FullName()))
+		case "output":
+			return globMatch(term.value, string(method.Output().FullName()))
+		}
+	}
+
+	return false
+}
+
+// matchesOptionTerm checks if a declaration sets an option, given either as
+// a name or as name=value. Extension names may be written with or without
+// parentheses.
+func matchesOptionTerm(desc protoreflect.Descriptor, value string) bool {
+	name, want, hasValue := strings.Cut(value, "=")
+	name = strings.Trim(name, "()")
+	for _, option := range optionsOf(desc) {
+		if option.Name != name {
+			continue
+		}
+		if !hasValue {
+			return true
+		}
+		if globMatch(want, formatOptionValue(option.Value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch matches a value against a * glob, treating invalid patterns as
+// literal text
+func globMatch(pattern, value string) bool {
+	matched, err := path.Match(pattern, value)
+	if err != nil {
+		return pattern == value
+	}
+	return matched
+}
+
+// queryResultType describes the type of a field or the signature of a method
+func queryResultType(desc protoreflect.Descriptor) string {
+	switch d := desc.(type) {
+	case protoreflect.FieldDescriptor:
+		if d.IsMap() {
+			return fmt.Sprintf("map<%s, %s>", queryFieldType(d.MapKey()), queryFieldType(d.MapValue()))
+		}
+		return queryFieldType(d)
+	case protoreflect.MethodDescriptor:
+		input, output := string(d.Input().FullName()), string(d.Output().FullName())
+		if d.IsStreamingClient() {
+			input = "stream " + input
+		}
+		if d.IsStreamingServer() {
+			output = "stream " + output
+		}
+		return fmt.Sprintf("(%s) returns (%s)", input, output)
+	}
+	return ""
+}
+
+// queryFieldType returns the full type name of a message or enum field, or its scalar kind
+func queryFieldType(field protoreflect.FieldDescriptor) string {
+	if typeName := typeNameOf(field); typeName != "" {
+		return typeName
+	}
+	return field.Kind().String()
+}
@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/compiler"
+)
+
+func callQuerySchema(t *testing.T, project *compiler.ProtobufProject, query string) QuerySchemaResponse {
+	t.Helper()
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewQuerySchemaTool(mockProjectManager)
+
+	return callToolSuccessfully[QuerySchemaResponse](t, tool.Handle, "query_schema", map[string]interface{}{"query": query})
+}
+
+func queryResultNames(response QuerySchemaResponse) []string {
+	names := []string{}
+	for _, result := range response.Results {
+		names = append(names, result.FullName)
+	}
+	return names
+}
+
+func TestQuerySchemaTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewQuerySchemaTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "query_schema" {
+		t.Fatalf("Expected tool name 'query_schema', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestQuerySchemaTool_Handle_Methods(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	tests := map[string][]string{
+		"kind:method streaming:any http:false": {
+			"example.simple.v1.GreetingService.StreamMessages",
+			"example.simple.v1.GreetingService.Chat",
+		},
+		"kind:method option:google.api.http": {
+			"example.simple.v1.GreetingService.SayHello",
+			"example.simple.v1.GreetingService.GetUser",
+		},
+		"kind:method output:*.User -http:true": {
+			"example.simple.v1.UserService.CreateUser",
+		},
+	}
+
+	for query, expected := range tests {
+		t.Run(query, func(t *testing.T) {
+			response := callQuerySchema(t, project, query)
+			if got := queryResultNames(response); !reflect.DeepEqual(got, expected) {
+				t.Errorf("Expected %v, got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestQuerySchemaTool_Handle_Fields(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"shop.proto": `syntax = "proto3";
+package example.shop;
+
+message Order {
+  int64 order_id = 1;
+  string customer_id = 2;
+  int64 total = 3;
+  int64 legacy_id = 4 [deprecated = true];
+  optional string note = 5;
+  repeated int64 item_ids = 6;
+  map<string, int64> counts = 7;
+}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	tests := map[string][]string{
+		"kind:field type:int64 name:*_id": {
+			"example.shop.Order.order_id",
+			"example.shop.Order.legacy_id",
+		},
+		"kind:field type:int64 name:*_id -option:deprecated=true": {
+			"example.shop.Order.order_id",
+		},
+		"option:deprecated=true package:example.*": {
+			"example.shop.Order.legacy_id",
+		},
+		"label:optional": {
+			"example.shop.Order.note",
+		},
+		"kind:field label:repeated": {
+			"example.shop.Order.item_ids",
+		},
+		"type:map number:7": {
+			"example.shop.Order.counts",
+		},
+		"Order": {
+			"example.shop.Order",
+		},
+	}
+
+	for query, expected := range tests {
+		t.Run(query, func(t *testing.T) {
+			response := callQuerySchema(t, project, query)
+			if got := queryResultNames(response); !reflect.DeepEqual(got, expected) {
+				t.Errorf("Expected %v, got %v", expected, got)
+			}
+		})
+	}
+
+	response := callQuerySchema(t, project, "name:counts")
+	if response.Results[0].Type != "map<string, int64>" {
+		t.Errorf("Expected map type description, got %q", response.Results[0].Type)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	terms, err := parseQuery(`kind:field -comment:"user email" User`)
+	if err != nil {
+		t.Fatalf("parseQuery failed: %v", err)
+	}
+	expected := []queryTerm{
+		{key: "kind", value: "field"},
+		{key: "comment", value: "user email", negate: true},
+		{key: "name", value: "User"},
+	}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, terms)
+	}
+
+	for _, query := range []string{"colour:red", `name:"unterminated`, "   "} {
+		if _, err := parseQuery(query); err == nil {
+			t.Errorf("Expected parse error for %q", query)
+		}
+	}
+}
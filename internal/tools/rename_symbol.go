@@ -0,0 +1,517 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bufbuild/protocompile/linker"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/breaking"
+	"github.com/yuemori/protobuf-mcp-server/internal/compiler"
+	"github.com/yuemori/protobuf-mcp-server/internal/lint"
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+	"github.com/yuemori/protobuf-mcp-server/internal/textedit"
+)
+
+// RenameSymbolTool implements the rename_symbol MCP tool using mcp-go
+type RenameSymbolTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewRenameSymbolTool creates a new RenameSymbolTool instance
+func NewRenameSymbolTool(projectManager ProjectManagerInterface) *RenameSymbolTool {
+	return &RenameSymbolTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *RenameSymbolTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"rename_symbol",
+		mcp.WithDescription("Rename a message, enum, enum value, service, RPC or field of the activated project. Returns the text edits renaming the declaration and every type reference to it across the compiled files, computed from source locations, and a report of the wire and JSON compatibility impact. Renaming a field also edits the google.api.http path variables, body and response_body naming it, and lists the options and comments that mention it, which are not edited. Use apply to write the edits; the project is compiled again afterwards"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Fully-qualified name of the symbol to rename (e.g. 'example.simple.v1.User' or 'example.simple.v1.User.email')"),
+		),
+		mcp.WithString("new_name",
+			mcp.Required(),
+			mcp.Description("New simple name (e.g. 'Account'), declared in the same scope"),
+		),
+		mcp.WithBoolean("apply",
+			mcp.Description("Write the edits to the proto files"),
+		),
+	)
+}
+
+// RenameSymbolResponse represents the response from rename_symbol tool
+type RenameSymbolResponse struct {
+	Success  bool                 `json:"success"`
+	Message  string               `json:"message"`
+	Symbol   string               `json:"symbol,omitempty"`
+	Kind     string               `json:"kind,omitempty"`
+	Renamed  string               `json:"renamed,omitempty"`
+	Edits    []textedit.Edit      `json:"edits,omitempty"`
+	Impact   *RenameImpact        `json:"impact,omitempty"`
+	Mentions []FieldReferenceInfo `json:"mentions,omitempty"`
+	Warnings []string             `json:"warnings,omitempty"`
+	Applied  []string             `json:"applied,omitempty"`
+}
+
+// RenameImpact describes the compatibility impact of a rename. Category is
+// the most lenient breaking change category that reports it (WIRE,
+// WIRE_JSON or PACKAGE), as used by check_breaking.
+type RenameImpact struct {
+	Category       string   `json:"category"`
+	WireCompatible bool     `json:"wire_compatible"`
+	JSONCompatible bool     `json:"json_compatible"`
+	Details        []string `json:"details"`
+}
+
+// Handle handles the tool execution
+func (t *RenameSymbolTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := req.GetString("name", "")
+	if name == "" {
+		return mcp.NewToolResultError("name parameter is required"), nil
+	}
+	newName := req.GetString("new_name", "")
+	if !identifierPattern.MatchString(newName) {
+		return mcp.NewToolResultError(fmt.Sprintf("new_name must be a valid identifier, got %q", newName)), nil
+	}
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &RenameSymbolResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &RenameSymbolResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	desc := findDescriptor(files, name)
+	if !renameable(desc) {
+		response := &RenameSymbolResponse{
+			Success: false,
+			Message: fmt.Sprintf("Symbol not found: %s (expected a message, enum, enum value, service, RPC or field)", name),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+	compiled := false
+	for _, file := range files {
+		compiled = compiled || file.Path() == desc.ParentFile().Path()
+	}
+	if !compiled {
+		response := &RenameSymbolResponse{
+			Success: false,
+			Message: fmt.Sprintf("%s is declared in %s, which is not a compiled file of the project", desc.FullName(), desc.ParentFile().Path()),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	edits, err := lint.Rename(breaking.FileDescriptors(files), desc, newName)
+	if err != nil {
+		response := &RenameSymbolResponse{
+			Success: false,
+			Message: fmt.Sprintf("Cannot rename %s: %v", desc.FullName(), err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	response := &RenameSymbolResponse{
+		Success: true,
+		Symbol:  string(desc.FullName()),
+		Kind:    descriptorKind(desc),
+		Renamed: string(desc.FullName().Parent().Append(protoreflect.Name(newName))),
+		Impact:  renameImpact(desc, newName),
+	}
+
+	// HTTP bindings name fields in strings the compiler does not check, so
+	// they are edited here. Those that cannot be edited stay in mentions and
+	// block apply, since the project would still compile with them broken.
+	uneditedBindings := 0
+	if field, ok := desc.(protoreflect.FieldDescriptor); ok {
+		bindingEdits, renamed, err := httpBindingRenameEdits(project, files, field, newName)
+		if err != nil {
+			response := &RenameSymbolResponse{
+				Success: false,
+				Message: fmt.Sprintf("Cannot rename %s: %v", desc.FullName(), err),
+			}
+			responseJSON, _ := json.Marshal(response)
+			return mcp.NewToolResultText(string(responseJSON)), nil
+		}
+		edits = append(edits, bindingEdits...)
+
+		references := fieldReferences(files, field)
+		var bindings []FieldReferenceInfo
+		for _, reference := range references {
+			if isHTTPReference(reference) {
+				bindings = append(bindings, reference)
+			} else {
+				response.Mentions = append(response.Mentions, reference)
+			}
+		}
+		response.Warnings = fieldNameWarnings(field.ContainingMessage(), newName, field)
+		if uneditedBindings = len(bindings) - renamed; uneditedBindings > 0 {
+			response.Mentions = append(bindings, response.Mentions...)
+			response.Warnings = append(response.Warnings, fmt.Sprintf("%d of the %d google.api.http references to %s could not be edited; update the bindings listed in mentions by hand", uneditedBindings, len(bindings), field.Name()))
+		}
+	}
+	response.Edits = edits
+
+	editedFiles := make(map[string]bool)
+	for _, edit := range edits {
+		editedFiles[edit.File] = true
+	}
+	response.Message = fmt.Sprintf("Rename %s to %s with %d edits in %d files", response.Symbol, response.Renamed, len(edits), len(editedFiles))
+
+	if req.GetBool("apply", false) && uneditedBindings > 0 {
+		response.Success = false
+		response.Message = fmt.Sprintf("Cannot apply the rename of %s: %d google.api.http references to it could not be edited and would be left broken; nothing was written", response.Symbol, uneditedBindings)
+	} else if req.GetBool("apply", false) {
+		response.Applied, err = textedit.ApplyFiles(edits, project.SourcePath)
+		if err != nil {
+			response := &RenameSymbolResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to apply edits: %v", err),
+			}
+			responseJSON, _ := json.Marshal(response)
+			return mcp.NewToolResultText(string(responseJSON)), nil
+		}
+		response.Message = fmt.Sprintf("Renamed %s to %s with %d edits in %d files", response.Symbol, response.Renamed, len(edits), len(response.Applied))
+
+		// Compile again, so that references the edits could not reach,
+		// such as enum values in option values, are reported
+		if _, err := project.CompileProtos(ctx); err != nil {
+			response.Success = false
+			response.Message += fmt.Sprintf(", but they no longer compile: %v", err)
+		}
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// isHTTPReference checks if a field reference is made by a google.api.http
+// binding
+func isHTTPReference(reference FieldReferenceInfo) bool {
+	switch reference.Kind {
+	case fieldReferenceKindHTTPPath, fieldReferenceKindHTTPBody, fieldReferenceKindHTTPResponseBody:
+		return true
+	}
+	return false
+}
+
+// httpBindingRenameEdits returns the edits renaming a field in the
+// google.api.http options of the compiled files: path variables and body
+// fields selecting it from the request, and response_body fields selecting it
+// from the response. The string literals are found through the source
+// locations of the option's fields. It also returns how many references were
+// renamed, counted like fieldReferences counts them.
+func httpBindingRenameEdits(project *compiler.ProtobufProject, files linker.Files, field protoreflect.FieldDescriptor, newName string) ([]textedit.Edit, int, error) {
+	var edits []textedit.Edit
+	renamed := 0
+	for _, file := range files {
+		var source *sourceLines
+		locations := file.SourceLocations()
+		for i := 0; i < file.Services().Len(); i++ {
+			methods := file.Services().Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				method := methods.Get(j)
+				rule := httpRuleExtension(method)
+				methodLoc := locations.ByDescriptor(method)
+				if rule == nil || methodLoc.Path == nil {
+					continue
+				}
+				prefix := append(append(protoreflect.SourcePath{}, methodLoc.Path...), optionsPathElement(method), int32(rule.Number()))
+
+				seen := make(map[int]bool)
+				for k := 0; k < locations.Len(); k++ {
+					loc := locations.Get(k)
+					if len(loc.Path) <= len(prefix) || !hasPathPrefix(loc.Path, prefix) {
+						continue
+					}
+					ruleField := httpRuleField(rule.Message(), loc.Path[len(prefix):])
+					if ruleField == nil {
+						continue
+					}
+					var root protoreflect.MessageDescriptor
+					template := false
+					switch {
+					case ruleField.ContainingMessage().Name() == "CustomHttpPattern" && ruleField.Name() == "path":
+						root, template = method.Input(), true
+					case ruleField.ContainingMessage().Name() != "HttpRule":
+						continue
+					case ruleField.Name() == "body":
+						root = method.Input()
+					case ruleField.Name() == "response_body":
+						root = method.Output()
+					case slices.Contains([]protoreflect.Name{"get", "put", "post", "delete", "patch"}, ruleField.Name()):
+						root, template = method.Input(), true
+					default:
+						continue
+					}
+
+					if source == nil {
+						var err error
+						if source, err = readSourceLines(project, file.Path()); err != nil {
+							return nil, 0, err
+						}
+					}
+					line, column, literal, ok := stringLiteralAt(source, loc)
+					if !ok || seen[line<<16|column] {
+						continue
+					}
+					seen[line<<16|column] = true
+
+					quote, value := literal[:1], literal[1:len(literal)-1]
+					count := 0
+					if template {
+						value = protoutil.ReplacePathVariables(value, func(variable string) string {
+							if renamedPath, ok := renameFieldPath(root, variable, field, newName); ok {
+								count++
+								return renamedPath
+							}
+							return variable
+						})
+					} else if renamedPath, ok := renameFieldPath(root, value, field, newName); ok {
+						value, count = renamedPath, 1
+					}
+					if count == 0 {
+						continue
+					}
+					renamed += count
+					edits = append(edits, lineEdit(file, line, column, line, column+utf8.RuneCountInString(literal), literal, quote+value+quote))
+				}
+			}
+		}
+	}
+	return edits, renamed, nil
+}
+
+// httpRuleExtension returns the google.api.http extension set on a method,
+// or nil
+func httpRuleExtension(method protoreflect.MethodDescriptor) protoreflect.FieldDescriptor {
+	var rule protoreflect.FieldDescriptor
+	if options := method.Options(); options != nil {
+		options.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+			if field.IsExtension() && field.FullName() == protoutil.HTTPOptionName && field.Message() != nil {
+				rule = field
+				return false
+			}
+			return true
+		})
+	}
+	return rule
+}
+
+// httpRuleField resolves the source path of a value set within a
+// google.api.HttpRule, such as additional_bindings[0].post, to the string
+// field it sets. Returns nil for paths ending at a message or list.
+func httpRuleField(message protoreflect.MessageDescriptor, path protoreflect.SourcePath) protoreflect.FieldDescriptor {
+	for i := 0; i < len(path); i++ {
+		if message == nil {
+			return nil
+		}
+		field := message.Fields().ByNumber(protoreflect.FieldNumber(path[i]))
+		if field == nil {
+			return nil
+		}
+		if field.IsList() {
+			// The element index follows the field number
+			i++
+		}
+		if i == len(path)-1 {
+			if field.Kind() != protoreflect.StringKind || field.IsList() {
+				return nil
+			}
+			return field
+		}
+		message = field.Message()
+	}
+	return nil
+}
+
+// stringLiteralAt returns the single-line string literal ending the value of
+// a source location, such as "/v1/{name}" in 'get: "/v1/{name}"' or in
+// 'option (google.api.http).get = "/v1/{name}";', with its 0-based position.
+// Literals with escapes or concatenated literals are not returned, since
+// their text differs from the value.
+func stringLiteralAt(source *sourceLines, loc protoreflect.SourceLocation) (int, int, string, bool) {
+	start, ok := source.offset(loc.StartLine, loc.StartColumn)
+	end, ok2 := source.offset(loc.EndLine, loc.EndColumn)
+	if !ok || !ok2 || end <= start {
+		return 0, 0, "", false
+	}
+	text := string(source.content[start:end])
+	trimmed := strings.TrimRight(text, " ;")
+	if trimmed == "" {
+		return 0, 0, "", false
+	}
+	quote := trimmed[len(trimmed)-1]
+	if quote != '"' && quote != '\'' {
+		return 0, 0, "", false
+	}
+	open := strings.LastIndexByte(trimmed[:len(trimmed)-1], quote)
+	if open < 0 {
+		return 0, 0, "", false
+	}
+	literal := trimmed[open:]
+	before := strings.TrimSpace(trimmed[:open])
+	if strings.ContainsAny(literal, "\\\n\t") || !(strings.HasSuffix(before, ":") || strings.HasSuffix(before, "=")) {
+		return 0, 0, "", false
+	}
+
+	// The literal and what follows it lie on the last line of the location
+	column := loc.EndColumn - utf8.RuneCountInString(text[open:])
+	if offset, ok := source.offset(loc.EndLine, column); !ok || offset != start+open {
+		return 0, 0, "", false
+	}
+	return loc.EndLine, column, literal, true
+}
+
+// renameFieldPath renames a field within a dotted field path, as used by
+// HTTP bindings, starting from a message. Returns false if the path does not
+// select or pass through the field.
+func renameFieldPath(message protoreflect.MessageDescriptor, path string, field protoreflect.FieldDescriptor, newName string) (string, bool) {
+	if path == "" || path == "*" {
+		return "", false
+	}
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		if message == nil {
+			return "", false
+		}
+		current := message.Fields().ByName(protoreflect.Name(strings.TrimSpace(name)))
+		if current == nil {
+			return "", false
+		}
+		if current.FullName() == field.FullName() {
+			names[i] = newName
+			return strings.Join(names, "."), true
+		}
+		message = current.Message()
+	}
+	return "", false
+}
+
+// renameable checks if a descriptor is a kind of declaration rename_symbol
+// supports
+func renameable(desc protoreflect.Descriptor) bool {
+	switch d := desc.(type) {
+	case protoreflect.MessageDescriptor:
+		return !d.IsMapEntry()
+	case protoreflect.FieldDescriptor:
+		return !d.IsExtension() && !d.ContainingMessage().IsMapEntry()
+	case protoreflect.EnumDescriptor, protoreflect.EnumValueDescriptor,
+		protoreflect.ServiceDescriptor, protoreflect.MethodDescriptor:
+		return true
+	}
+	return false
+}
+
+// renameImpact describes how renaming a declaration affects the binary and
+// JSON encodings, RPC calls and generated code
+func renameImpact(desc protoreflect.Descriptor, newName string) *RenameImpact {
+	renamed := desc.FullName().Parent().Append(protoreflect.Name(newName))
+	switch d := desc.(type) {
+	case protoreflect.MessageDescriptor:
+		return &RenameImpact{
+			Category:       breaking.CategoryPackage,
+			WireCompatible: true,
+			JSONCompatible: true,
+			Details: []string{
+				"The binary and JSON encodings do not contain message names",
+				fmt.Sprintf("google.protobuf.Any values embed the full name in their type URL: values packed as type.googleapis.com/%s no longer resolve once readers only know %s", d.FullName(), renamed),
+				"Generated code renames the type, which breaks code using it",
+			},
+		}
+	case protoreflect.EnumDescriptor:
+		return &RenameImpact{
+			Category:       breaking.CategoryPackage,
+			WireCompatible: true,
+			JSONCompatible: true,
+			Details: []string{
+				"The binary and JSON encodings do not contain enum names",
+				"Generated code renames the type, which breaks code using it",
+			},
+		}
+	case protoreflect.EnumValueDescriptor:
+		return &RenameImpact{
+			Category:       breaking.CategoryWireJSON,
+			WireCompatible: true,
+			JSONCompatible: false,
+			Details: []string{
+				fmt.Sprintf("The binary encoding uses the number %d, which is unchanged", d.Number()),
+				fmt.Sprintf("JSON and text format encode enum values by name: %s is written as %q instead of %q, which readers built before the rename reject", renamed, newName, d.Name()),
+				"Generated code renames the constant",
+			},
+		}
+	case protoreflect.FieldDescriptor:
+		impact := &RenameImpact{
+			Category:       breaking.CategoryWireJSON,
+			WireCompatible: true,
+			Details:        []string{fmt.Sprintf("The binary encoding uses the number %d, which is unchanged", d.Number())},
+		}
+		switch {
+		case d.JSONName() != protoutil.DefaultJSONName(string(d.Name())):
+			impact.Category = breaking.CategoryPackage
+			impact.JSONCompatible = true
+			impact.Details = append(impact.Details, fmt.Sprintf("JSON keeps the key %q set by json_name", d.JSONName()))
+		case d.JSONName() == protoutil.DefaultJSONName(newName):
+			impact.Category = breaking.CategoryPackage
+			impact.JSONCompatible = true
+			impact.Details = append(impact.Details, fmt.Sprintf("JSON keeps the key %q, which is the default JSON name of both names", d.JSONName()))
+		default:
+			impact.Details = append(impact.Details, fmt.Sprintf("The JSON key changes from %q to %q; add json_name = %q to the field to keep JSON compatible", d.JSONName(), protoutil.DefaultJSONName(newName), d.JSONName()))
+		}
+		impact.Details = append(impact.Details,
+			"Text format and field mask paths use the field name, so stored text and masks naming the field break",
+			"Generated code renames the accessors, which breaks code using them",
+		)
+		return impact
+	case protoreflect.ServiceDescriptor:
+		return &RenameImpact{
+			Category: breaking.CategoryWire,
+			Details: []string{
+				fmt.Sprintf("gRPC calls are routed by the full service name: every method moves from /%s/ to /%s/, so deployed clients calling the old paths fail", d.FullName(), renamed),
+				"Generated clients and servers are renamed, which breaks code using them",
+			},
+		}
+	case protoreflect.MethodDescriptor:
+		service := d.Parent().FullName()
+		return &RenameImpact{
+			Category: breaking.CategoryWire,
+			Details: []string{
+				fmt.Sprintf("The gRPC path changes from /%s/%s to /%s/%s, so deployed clients calling the old path fail", service, d.Name(), service, newName),
+				"HTTP bindings declared with google.api.http keep their paths",
+				"Generated client and server methods are renamed, which breaks code using them",
+			},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const renameSymbolTypes = `syntax = "proto3";
+
+package library.v1;
+
+// A book
+message Book {
+  message Page {}
+
+  string title = 1;
+  Page first_page = 2;
+  Format format = 3;
+}
+
+enum Format {
+  FORMAT_UNSPECIFIED = 0;
+  FORMAT_PAPERBACK = 1;
+}
+`
+
+const renameSymbolAPI = `syntax = "proto3";
+
+package library.v1;
+
+import "library/v1/types.proto";
+
+service Library {
+  rpc GetBook(GetBookRequest) returns (Book);
+}
+
+message GetBookRequest {
+  // Fields to return, e.g. "title"
+  repeated string read_mask = 1;
+}
+
+message Shelf {
+  repeated Book books = 1;
+  library.v1.Book.Page cover = 2;
+  string display_name = 3 [json_name = "name"];
+}
+`
+
+func TestRenameSymbolTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewRenameSymbolTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "rename_symbol" {
+		t.Fatalf("Expected tool name 'rename_symbol', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestRenameSymbolTool_Handle(t *testing.T) {
+	project, err := CreateTempProject(t, map[string]string{
+		"library/v1/types.proto": renameSymbolTypes,
+		"library/v1/api.proto":   renameSymbolAPI,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewRenameSymbolTool(mockProjectManager)
+
+	tests := []struct {
+		name           string
+		newName        string
+		kind           string
+		edits          int
+		category       string
+		wireCompatible bool
+		jsonCompatible bool
+		mentions       int
+	}{
+		{name: "library.v1.Book", newName: "Volume", kind: "message", edits: 4, category: "PACKAGE", wireCompatible: true, jsonCompatible: true},
+		{name: "library.v1.Format", newName: "Binding", kind: "enum", edits: 2, category: "PACKAGE", wireCompatible: true, jsonCompatible: true},
+		{name: "library.v1.FORMAT_PAPERBACK", newName: "FORMAT_SOFTCOVER", kind: "enum_value", edits: 1, category: "WIRE_JSON", wireCompatible: true},
+		{name: "library.v1.Book.title", newName: "name", kind: "field", edits: 1, category: "WIRE_JSON", wireCompatible: true, mentions: 1},
+		{name: "library.v1.Book.first_page", newName: "firstPage", kind: "field", edits: 1, category: "PACKAGE", wireCompatible: true, jsonCompatible: true},
+		{name: "library.v1.Shelf.display_name", newName: "label", kind: "field", edits: 1, category: "PACKAGE", wireCompatible: true, jsonCompatible: true},
+		{name: "library.v1.Library", newName: "Catalog", kind: "service", edits: 1, category: "WIRE"},
+		{name: "library.v1.Library.GetBook", newName: "FetchBook", kind: "method", edits: 1, category: "WIRE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := callTool[RenameSymbolResponse](t, tool.Handle, "rename_symbol", map[string]interface{}{"name": tt.name, "new_name": tt.newName})
+			if !response.Success {
+				t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+			}
+			if response.Kind != tt.kind || len(response.Edits) != tt.edits {
+				t.Errorf("Expected a %s with %d edits, got %s with %+v", tt.kind, tt.edits, response.Kind, response.Edits)
+			}
+			impact := response.Impact
+			if impact == nil || impact.Category != tt.category || impact.WireCompatible != tt.wireCompatible || impact.JSONCompatible != tt.jsonCompatible {
+				t.Errorf("Unexpected impact: %+v", impact)
+			}
+			if len(response.Mentions) != tt.mentions {
+				t.Errorf("Expected %d mentions, got %+v", tt.mentions, response.Mentions)
+			}
+		})
+	}
+
+	t.Run("taken name", func(t *testing.T) {
+		response := callTool[RenameSymbolResponse](t, tool.Handle, "rename_symbol", map[string]interface{}{"name": "library.v1.Book", "new_name": "Shelf"})
+		if response.Success || !strings.Contains(response.Message, "already declared") {
+			t.Errorf("Expected a taken name to fail, got %+v", response)
+		}
+	})
+
+	t.Run("unsupported symbol", func(t *testing.T) {
+		for _, name := range []string{"library.v1.Missing", "library.v1", "google.protobuf.Empty"} {
+			if response := callTool[RenameSymbolResponse](t, tool.Handle, "rename_symbol", map[string]interface{}{"name": name, "new_name": "Other"}); response.Success {
+				t.Errorf("Expected success=false for %s", name)
+			}
+		}
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		response := callTool[RenameSymbolResponse](t, tool.Handle, "rename_symbol", map[string]interface{}{"name": "library.v1.Book", "new_name": "Volume", "apply": true})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+		if strings.Join(response.Applied, ",") != "library/v1/types.proto,library/v1/api.proto" {
+			t.Errorf("Expected both files to be edited, got %v", response.Applied)
+		}
+
+		content, err := os.ReadFile(filepath.Join(project.ProjectRoot, "library", "v1", "api.proto"))
+		if err != nil {
+			t.Fatalf("Failed to read: %v", err)
+		}
+		expected := strings.NewReplacer(
+			"returns (Book)", "returns (Volume)",
+			"repeated Book books", "repeated Volume books",
+			"library.v1.Book.Page", "library.v1.Volume.Page",
+		).Replace(renameSymbolAPI)
+		if string(content) != expected {
+			t.Errorf("Unexpected renamed file:\n%s", content)
+		}
+	})
+}
+
+const renameSymbolHTTP = `syntax = "proto3";
+
+package library.v1;
+
+import "google/api/annotations.proto";
+
+service Library {
+  rpc UpdateBook(UpdateBookRequest) returns (Book) {
+    option (google.api.http) = {
+      patch: "/v1/{book.shelf=shelves/*}/books/{book.id}"
+      body: "book"
+      additional_bindings {
+        custom { kind: "MERGE" path: "/v1/books/{book.id}" }
+        body: "book"
+      }
+    };
+  }
+  rpc GetTitle(GetTitleRequest) returns (Book) {
+    option (google.api.http).get = "/v1/books/{id}/title";
+    option (google.api.http).response_body = "title";
+  }
+}
+
+message Book {
+  string id = 1;
+  string shelf = 2;
+  string title = 3;
+}
+
+message UpdateBookRequest {
+  Book book = 1;
+}
+
+message GetTitleRequest {
+  string id = 1;
+}
+
+message Concatenated {
+  string id = 1;
+}
+
+service Legacy {
+  rpc GetConcatenated(Concatenated) returns (Concatenated) {
+    option (google.api.http) = {
+      get: "/v1/legacy/" "{id}"
+    };
+  }
+}
+`
+
+func TestRenameSymbolTool_HTTPBindings(t *testing.T) {
+	sources := map[string]string{"library/v1/api.proto": renameSymbolHTTP}
+	for _, file := range []string{"google/api/annotations.proto", "google/api/http.proto"} {
+		content, err := os.ReadFile(filepath.Join("testdata", filepath.FromSlash(file)))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		sources[file] = string(content)
+	}
+	project, err := CreateTempProject(t, sources)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewRenameSymbolTool(mockProjectManager)
+
+	read := func(t *testing.T) string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(project.ProjectRoot, "library", "v1", "api.proto"))
+		if err != nil {
+			t.Fatalf("Failed to read: %v", err)
+		}
+		return string(content)
+	}
+
+	t.Run("path variables and bodies", func(t *testing.T) {
+		response := callTool[RenameSymbolResponse](t, tool.Handle, "rename_symbol", map[string]interface{}{"name": "library.v1.UpdateBookRequest.book", "new_name": "volume", "apply": true})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+		if len(response.Edits) != 5 || len(response.Mentions) != 0 {
+			t.Errorf("Expected 5 edits and no mentions, got %+v", response)
+		}
+		expected := strings.NewReplacer(
+			`patch: "/v1/{book.shelf=shelves/*}/books/{book.id}"`, `patch: "/v1/{volume.shelf=shelves/*}/books/{volume.id}"`,
+			`body: "book"`, `body: "volume"`,
+			`path: "/v1/books/{book.id}"`, `path: "/v1/books/{volume.id}"`,
+			"Book book = 1;", "Book volume = 1;",
+		).Replace(renameSymbolHTTP)
+		if content := read(t); content != expected {
+			t.Errorf("Unexpected renamed file:\n%s", content)
+		}
+	})
+
+	t.Run("option fields", func(t *testing.T) {
+		before := read(t)
+		response := callTool[RenameSymbolResponse](t, tool.Handle, "rename_symbol", map[string]interface{}{"name": "library.v1.Book.title", "new_name": "headline", "apply": true})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+		expected := strings.NewReplacer(
+			`option (google.api.http).response_body = "title";`, `option (google.api.http).response_body = "headline";`,
+			"string title = 3;", "string headline = 3;",
+		).Replace(before)
+		if content := read(t); content != expected {
+			t.Errorf("Unexpected renamed file:\n%s", content)
+		}
+	})
+
+	t.Run("binding that cannot be edited", func(t *testing.T) {
+		before := read(t)
+		response := callTool[RenameSymbolResponse](t, tool.Handle, "rename_symbol", map[string]interface{}{"name": "library.v1.Concatenated.id", "new_name": "key", "apply": true})
+		if response.Success || len(response.Applied) != 0 {
+			t.Errorf("Expected apply to be refused, got %+v", response)
+		}
+		if len(response.Mentions) != 1 || response.Mentions[0].Kind != "http_path" {
+			t.Errorf("Expected the binding in mentions, got %+v", response.Mentions)
+		}
+		if content := read(t); content != before {
+			t.Errorf("Expected nothing to be written, got:\n%s", content)
+		}
+	})
+}
+
+func TestRenameSymbolTool_InvalidParams(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewRenameSymbolTool(mockProjectManager)
+
+	for _, arguments := range []map[string]interface{}{
+		{"new_name": "Volume"},
+		{"name": "library.v1.Book"},
+		{"name": "library.v1.Book", "new_name": "library.v1.Volume"},
+	} {
+		req := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "rename_symbol",
+				Arguments: arguments,
+			},
+		}
+		result, err := tool.Handle(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("Expected an error result for %v", arguments)
+		}
+	}
+}
+
+func TestRenameSymbolTool_NoProject(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewRenameSymbolTool(mockProjectManager)
+
+	response := callTool[RenameSymbolResponse](t, tool.Handle, "rename_symbol", map[string]interface{}{"name": "library.v1.Book", "new_name": "Volume"})
+	if response.Success {
+		t.Errorf("Expected success=false without an activated project")
+	}
+}
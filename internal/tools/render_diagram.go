@@ -0,0 +1,359 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Supported diagram formats
+const (
+	diagramFormatMermaid  = "mermaid"
+	diagramFormatPlantUML = "plantuml"
+)
+
+// RenderDiagramTool implements the render_diagram MCP tool using mcp-go
+type RenderDiagramTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewRenderDiagramTool creates a new RenderDiagramTool instance
+func NewRenderDiagramTool(projectManager ProjectManagerInterface) *RenderDiagramTool {
+	return &RenderDiagramTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *RenderDiagramTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"render_diagram",
+		mcp.WithDescription("Render a class diagram of a service, message or enum and the types it references, as Mermaid or PlantUML"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Fully-qualified service, message or enum name to start from (e.g. 'example.simple.v1.GreetingService')"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Diagram format: 'mermaid' (default) or 'plantuml'"),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description("Maximum number of reference hops to follow (0 renders only the starting type; unlimited when omitted)"),
+		),
+		mcp.WithString("package",
+			mcp.Description("Only include referenced types from packages matching this name or glob (e.g. 'example.*')"),
+		),
+	)
+}
+
+// RenderDiagramResponse represents the response from render_diagram tool
+type RenderDiagramResponse struct {
+	Success   bool     `json:"success"`
+	Message   string   `json:"message"`
+	Format    string   `json:"format,omitempty"`
+	Diagram   string   `json:"diagram,omitempty"`
+	Types     []string `json:"types,omitempty"`
+	Truncated bool     `json:"truncated"`
+}
+
+// diagram is a format-independent class diagram of protobuf types
+type diagram struct {
+	classes   []diagramClass
+	edges     []diagramEdge
+	truncated bool
+}
+
+// diagramClass is a service, message or enum in a diagram
+type diagramClass struct {
+	id         string
+	name       string
+	stereotype string
+	members    []string
+}
+
+// diagramEdge is a reference from one class to another. Dependencies are
+// RPC inputs and outputs; other edges are field references.
+type diagramEdge struct {
+	from       string
+	to         string
+	label      string
+	dependency bool
+}
+
+// Handle handles the tool execution
+func (t *RenderDiagramTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := req.GetString("name", "")
+	if name == "" {
+		return mcp.NewToolResultError("name parameter is required"), nil
+	}
+	format := req.GetString("format", diagramFormatMermaid)
+	if format != diagramFormatMermaid && format != diagramFormatPlantUML {
+		return mcp.NewToolResultError(fmt.Sprintf("unsupported format %q: expected 'mermaid' or 'plantuml'", format)), nil
+	}
+	depth := req.GetInt("depth", -1)
+	packageFilter := req.GetString("package", "")
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &RenderDiagramResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &RenderDiagramResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	desc := findDescriptor(files, name)
+	switch desc.(type) {
+	case protoreflect.ServiceDescriptor, protoreflect.MessageDescriptor, protoreflect.EnumDescriptor:
+	default:
+		response := &RenderDiagramResponse{
+			Success: false,
+			Message: fmt.Sprintf("Type not found: %s (expected a fully-qualified service, message or enum name)", name),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	d := buildDiagram(desc, depth, packageFilter)
+
+	var rendered string
+	switch format {
+	case diagramFormatMermaid:
+		rendered = renderMermaidClassDiagram(d)
+	case diagramFormatPlantUML:
+		rendered = renderPlantUMLClassDiagram(d)
+	}
+
+	types := make([]string, 0, len(d.classes))
+	for _, class := range d.classes {
+		types = append(types, class.name)
+	}
+
+	response := &RenderDiagramResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("Rendered %s diagram of %s with %d types", format, desc.FullName(), len(d.classes)),
+		Format:    format,
+		Diagram:   rendered,
+		Types:     types,
+		Truncated: d.truncated,
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// buildDiagram walks field and RPC references breadth-first from root. The
+// root is always included; referenced types outside the package filter are
+// left out together with the edges pointing at them.
+func buildDiagram(root protoreflect.Descriptor, maxDepth int, packageFilter string) *diagram {
+	d := &diagram{}
+	seen := map[protoreflect.FullName]bool{root.FullName(): true}
+	current := []protoreflect.Descriptor{root}
+
+	for depth := 0; len(current) > 0; depth++ {
+		var next []protoreflect.Descriptor
+		for _, desc := range current {
+			class, refs := newDiagramClass(desc)
+			d.classes = append(d.classes, class)
+
+			for _, ref := range refs {
+				target := ref.target
+				if !matchesPackage(string(target.ParentFile().Package()), packageFilter) {
+					continue
+				}
+				if !seen[target.FullName()] {
+					if maxDepth >= 0 && depth >= maxDepth {
+						d.truncated = true
+						continue
+					}
+					seen[target.FullName()] = true
+					next = append(next, target)
+				}
+				d.addEdge(diagramEdge{
+					from:       class.id,
+					to:         diagramID(target.FullName()),
+					label:      ref.label,
+					dependency: ref.dependency,
+				})
+			}
+		}
+		current = next
+	}
+
+	return d
+}
+
+// addEdge adds an edge, merging the label into an existing edge of the same
+// kind between the same classes
+func (d *diagram) addEdge(edge diagramEdge) {
+	for i, existing := range d.edges {
+		if existing.from == edge.from && existing.to == edge.to && existing.dependency == edge.dependency {
+			d.edges[i].label += ", " + edge.label
+			return
+		}
+	}
+	d.edges = append(d.edges, edge)
+}
+
+// diagramRef is an outgoing reference of a diagram class
+type diagramRef struct {
+	target     protoreflect.Descriptor
+	label      string
+	dependency bool
+}
+
+// newDiagramClass converts a service, message or enum into a diagram class
+// and returns the types it references
+func newDiagramClass(desc protoreflect.Descriptor) (diagramClass, []diagramRef) {
+	class := diagramClass{
+		id:   diagramID(desc.FullName()),
+		name: string(desc.FullName()),
+	}
+	var refs []diagramRef
+
+	switch d := desc.(type) {
+	case protoreflect.ServiceDescriptor:
+		class.stereotype = "service"
+		for i := 0; i < d.Methods().Len(); i++ {
+			method := d.Methods().Get(i)
+			input := string(method.Input().Name())
+			if method.IsStreamingClient() {
+				input = "stream " + input
+			}
+			output := string(method.Output().Name())
+			if method.IsStreamingServer() {
+				output = "stream " + output
+			}
+			class.members = append(class.members, fmt.Sprintf("%s(%s) %s", method.Name(), input, output))
+			refs = append(refs,
+				diagramRef{target: method.Input(), label: string(method.Name()), dependency: true},
+				diagramRef{target: method.Output(), label: string(method.Name()), dependency: true},
+			)
+		}
+	case protoreflect.MessageDescriptor:
+		class.stereotype = "message"
+		for i := 0; i < d.Fields().Len(); i++ {
+			field := d.Fields().Get(i)
+			class.members = append(class.members, fmt.Sprintf("%s %s", diagramFieldType(field), field.Name()))
+			value := field
+			if field.IsMap() {
+				value = field.MapValue()
+			}
+			switch {
+			case value.Message() != nil:
+				refs = append(refs, diagramRef{target: value.Message(), label: string(field.Name())})
+			case value.Enum() != nil:
+				refs = append(refs, diagramRef{target: value.Enum(), label: string(field.Name())})
+			}
+		}
+	case protoreflect.EnumDescriptor:
+		class.stereotype = "enum"
+		for i := 0; i < d.Values().Len(); i++ {
+			class.members = append(class.members, string(d.Values().Get(i).Name()))
+		}
+	}
+
+	return class, refs
+}
+
+// diagramFieldType returns the short type of a field as shown in a diagram
+func diagramFieldType(field protoreflect.FieldDescriptor) string {
+	if field.IsMap() {
+		return fmt.Sprintf("map<%s, %s>", diagramScalarOrTypeName(field.MapKey()), diagramScalarOrTypeName(field.MapValue()))
+	}
+	typeName := diagramScalarOrTypeName(field)
+	if field.IsList() {
+		return "repeated " + typeName
+	}
+	return typeName
+}
+
+// diagramScalarOrTypeName returns the scalar kind or the short message or enum name of a field
+func diagramScalarOrTypeName(field protoreflect.FieldDescriptor) string {
+	switch {
+	case field.Message() != nil:
+		return string(field.Message().Name())
+	case field.Enum() != nil:
+		return string(field.Enum().Name())
+	default:
+		return field.Kind().String()
+	}
+}
+
+// diagramID converts a full name into an identifier usable in both Mermaid and PlantUML.
+// Underscores are escaped as _0 and dots become _, so distinct names such as
+// a.b_c and a_b.c never share an identifier: a name segment cannot start with
+// a digit, so _0 is never a converted dot.
+func diagramID(name protoreflect.FullName) string {
+	return strings.NewReplacer("_", "_0", ".", "_").Replace(string(name))
+}
+
+// renderMermaidClassDiagram renders a diagram as a Mermaid class diagram
+func renderMermaidClassDiagram(d *diagram) string {
+	// Mermaid uses ~ for generics; angle brackets would be parsed as markup
+	generics := strings.NewReplacer("<", "~", ">", "~")
+
+	var b strings.Builder
+	b.WriteString("classDiagram\n")
+	for _, class := range d.classes {
+		fmt.Fprintf(&b, "  class %s[\"%s\"] {\n", class.id, class.name)
+		fmt.Fprintf(&b, "    <<%s>>\n", class.stereotype)
+		for _, member := range class.members {
+			fmt.Fprintf(&b, "    %s\n", generics.Replace(member))
+		}
+		b.WriteString("  }\n")
+	}
+	for _, edge := range d.edges {
+		arrow := "-->"
+		if edge.dependency {
+			arrow = "..>"
+		}
+		fmt.Fprintf(&b, "  %s %s %s : %s\n", edge.from, arrow, edge.to, edge.label)
+	}
+	return b.String()
+}
+
+// renderPlantUMLClassDiagram renders a diagram as a PlantUML class diagram
+func renderPlantUMLClassDiagram(d *diagram) string {
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	for _, class := range d.classes {
+		keyword := "class"
+		if class.stereotype == "enum" {
+			keyword = "enum"
+		}
+		fmt.Fprintf(&b, "%s \"%s\" as %s <<%s>> {\n", keyword, class.name, class.id, class.stereotype)
+		for _, member := range class.members {
+			fmt.Fprintf(&b, "  %s\n", member)
+		}
+		b.WriteString("}\n")
+	}
+	for _, edge := range d.edges {
+		arrow := "-->"
+		if edge.dependency {
+			arrow = "..>"
+		}
+		fmt.Fprintf(&b, "%s %s %s : %s\n", edge.from, arrow, edge.to, edge.label)
+	}
+	b.WriteString("@enduml\n")
+	return b.String()
+}
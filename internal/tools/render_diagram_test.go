@@ -0,0 +1,195 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const diagramTestProto = `syntax = "proto3";
+package shop.v1;
+
+import "common/money.proto";
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_OPEN = 1;
+}
+
+message Item {
+  string id = 1;
+  common.Money price = 2;
+}
+
+message Cart {
+  repeated Item items = 1;
+  map<string, Item> by_id = 2;
+  Status status = 3;
+}
+
+service CartService {
+  rpc GetCart(Item) returns (Cart);
+  rpc WatchCart(Item) returns (stream Cart);
+}
+`
+
+func callRenderDiagram(t *testing.T, args map[string]interface{}) RenderDiagramResponse {
+	t.Helper()
+
+	project, err := CreateTempProject(t, map[string]string{
+		"shop/v1/shop.proto": diagramTestProto,
+		"common/money.proto": `syntax = "proto3";
+package common;
+
+message Money {
+  int64 units = 1;
+}
+`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewRenderDiagramTool(mockProjectManager)
+
+	return callTool[RenderDiagramResponse](t, tool.Handle, "render_diagram", args)
+}
+
+func TestRenderDiagramTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewRenderDiagramTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "render_diagram" {
+		t.Fatalf("Expected tool name 'render_diagram', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestRenderDiagramTool_Handle_Mermaid(t *testing.T) {
+	response := callRenderDiagram(t, map[string]interface{}{"name": "shop.v1.CartService"})
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+
+	expected := `classDiagram
+  class shop_v1_CartService["shop.v1.CartService"] {
+    <<service>>
+    GetCart(Item) Cart
+    WatchCart(Item) stream Cart
+  }
+  class shop_v1_Item["shop.v1.Item"] {
+    <<message>>
+    string id
+    Money price
+  }
+  class shop_v1_Cart["shop.v1.Cart"] {
+    <<message>>
+    repeated Item items
+    map~string, Item~ by_id
+    Status status
+  }
+  class common_Money["common.Money"] {
+    <<message>>
+    int64 units
+  }
+  class shop_v1_Status["shop.v1.Status"] {
+    <<enum>>
+    STATUS_UNSPECIFIED
+    STATUS_OPEN
+  }
+  shop_v1_CartService ..> shop_v1_Item : GetCart, WatchCart
+  shop_v1_CartService ..> shop_v1_Cart : GetCart, WatchCart
+  shop_v1_Item --> common_Money : price
+  shop_v1_Cart --> shop_v1_Item : items, by_id
+  shop_v1_Cart --> shop_v1_Status : status
+`
+	if response.Diagram != expected {
+		t.Errorf("Unexpected diagram:\n%s\nexpected:\n%s", response.Diagram, expected)
+	}
+	if response.Truncated {
+		t.Errorf("Expected truncated=false")
+	}
+}
+
+func TestRenderDiagramTool_Handle_DepthAndPackage(t *testing.T) {
+	response := callRenderDiagram(t, map[string]interface{}{
+		"name":    "shop.v1.Cart",
+		"format":  "plantuml",
+		"depth":   1,
+		"package": "shop.*",
+	})
+	if !response.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+	}
+
+	// common.Money is filtered out by package, so the depth limit is never hit
+	expectedTypes := []string{"shop.v1.Cart", "shop.v1.Item", "shop.v1.Status"}
+	if !reflect.DeepEqual(response.Types, expectedTypes) {
+		t.Errorf("Expected types %v, got %v", expectedTypes, response.Types)
+	}
+	if response.Truncated {
+		t.Errorf("Expected truncated=false")
+	}
+
+	expected := `@startuml
+class "shop.v1.Cart" as shop_v1_Cart <<message>> {
+  repeated Item items
+  map<string, Item> by_id
+  Status status
+}
+class "shop.v1.Item" as shop_v1_Item <<message>> {
+  string id
+  Money price
+}
+enum "shop.v1.Status" as shop_v1_Status <<enum>> {
+  STATUS_UNSPECIFIED
+  STATUS_OPEN
+}
+shop_v1_Cart --> shop_v1_Item : items, by_id
+shop_v1_Cart --> shop_v1_Status : status
+@enduml
+`
+	if response.Diagram != expected {
+		t.Errorf("Unexpected diagram:\n%s\nexpected:\n%s", response.Diagram, expected)
+	}
+
+	response = callRenderDiagram(t, map[string]interface{}{"name": "shop.v1.Cart", "depth": 0})
+	if !reflect.DeepEqual(response.Types, []string{"shop.v1.Cart"}) || !response.Truncated {
+		t.Errorf("Expected only the root and truncated=true at depth 0, got %v (truncated=%v)", response.Types, response.Truncated)
+	}
+}
+
+func TestRenderDiagramTool_Handle_NotFound(t *testing.T) {
+	response := callRenderDiagram(t, map[string]interface{}{"name": "shop.v1.Cart.items"})
+	if response.Success {
+		t.Fatalf("Expected success=false for a field name")
+	}
+}
+
+func TestDiagramID(t *testing.T) {
+	tests := map[string]string{
+		"shop.v1.Cart": "shop_v1_Cart",
+		"a.b_c":        "a_b_0c",
+		"a_b.c":        "a_0b_c",
+		"a._b":         "a__0b",
+		"a_.b":         "a_0_b",
+	}
+	ids := make(map[string]string)
+	for name, expected := range tests {
+		id := diagramID(protoreflect.FullName(name))
+		if id != expected {
+			t.Errorf("Expected %s to become %s, got %s", name, expected, id)
+		}
+		if other, ok := ids[id]; ok {
+			t.Errorf("%s and %s share the identifier %s", name, other, id)
+		}
+		ids[id] = name
+	}
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ResolveTypeTool implements the resolve_type MCP tool using mcp-go
+type ResolveTypeTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewResolveTypeTool creates a new ResolveTypeTool instance
+func NewResolveTypeTool(projectManager ProjectManagerInterface) *ResolveTypeTool {
+	return &ResolveTypeTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *ResolveTypeTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"resolve_type",
+		mcp.WithDescription("Resolve a message or enum by its exact fully-qualified name and return it together with every type it transitively references"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Fully-qualified type name (e.g. 'example.simple.v1.CreateUserRequest')"),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description("Maximum number of reference hops to follow (0 returns only the type itself; unlimited when omitted)"),
+		),
+		mcp.WithBoolean("include_detached_comments",
+			mcp.Description("Include detached comments (separated by a blank line) in descriptions"),
+		),
+	)
+}
+
+// ResolveTypeResponse represents the response from resolve_type tool
+type ResolveTypeResponse struct {
+	Success       bool          `json:"success"`
+	Message       string        `json:"message"`
+	SchemaVersion string        `json:"schema_version,omitempty"`
+	Root          string        `json:"root,omitempty"`
+	Kind          string        `json:"kind,omitempty"`
+	Messages      []MessageInfo `json:"messages,omitempty"`
+	Enums         []EnumInfo    `json:"enums,omitempty"`
+	Truncated     bool          `json:"truncated"`
+	Count         int           `json:"count"`
+}
+
+// Handle handles the tool execution
+func (t *ResolveTypeTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := req.GetString("name", "")
+	if name == "" {
+		return mcp.NewToolResultError("name parameter is required"), nil
+	}
+	depth := req.GetInt("depth", -1)
+	opts := convertOptions{IncludeDetachedComments: req.GetBool("include_detached_comments", false)}
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &ResolveTypeResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &ResolveTypeResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	var kind string
+	desc := findDescriptor(files, name)
+	switch desc.(type) {
+	case protoreflect.MessageDescriptor:
+		kind = "message"
+	case protoreflect.EnumDescriptor:
+		kind = "enum"
+	default:
+		response := &ResolveTypeResponse{
+			Success: false,
+			Message: fmt.Sprintf("Type not found: %s (expected a fully-qualified message or enum name)", name),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	messages, enums, truncated := typeClosure([]protoreflect.Descriptor{desc}, depth)
+
+	messageInfos := make([]MessageInfo, 0, len(messages))
+	for _, message := range messages {
+		messageInfos = append(messageInfos, newMessageInfo(message, opts))
+	}
+	enumInfos := make([]EnumInfo, 0, len(enums))
+	for _, enum := range enums {
+		enumInfos = append(enumInfos, newEnumInfo(enum, opts))
+	}
+
+	count := len(messageInfos) + len(enumInfos)
+	response := &ResolveTypeResponse{
+		Success:       true,
+		Message:       fmt.Sprintf("Resolved %s with %d referenced types", desc.FullName(), count-1),
+		SchemaVersion: SchemaVersion,
+		Root:          string(desc.FullName()),
+		Kind:          kind,
+		Messages:      messageInfos,
+		Enums:         enumInfos,
+		Truncated:     truncated,
+		Count:         count,
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestResolveTypeTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewResolveTypeTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "resolve_type" {
+		t.Fatalf("Expected tool name 'resolve_type', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestResolveTypeTool_Handle(t *testing.T) {
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	tool := NewResolveTypeTool(mockProjectManager)
+
+	callResolveType := func(arguments map[string]interface{}) ResolveTypeResponse {
+		req := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "resolve_type",
+				Arguments: arguments,
+			},
+		}
+
+		result, err := tool.Handle(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+
+		var response ResolveTypeResponse
+		if textContent, ok := mcp.AsTextContent(result.Content[0]); ok {
+			if err := json.Unmarshal([]byte(textContent.Text), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+		} else {
+			t.Fatalf("Expected text content in response")
+		}
+		return response
+	}
+
+	names := func(response ResolveTypeResponse) (messages, enums []string) {
+		for _, message := range response.Messages {
+			messages = append(messages, message.Name)
+		}
+		for _, enum := range response.Enums {
+			enums = append(enums, enum.Name)
+		}
+		return messages, enums
+	}
+
+	t.Run("FullClosure", func(t *testing.T) {
+		response := callResolveType(map[string]interface{}{"name": "example.simple.v1.User"})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+
+		messages, enums := names(response)
+		if strings.Join(messages, ",") != "User,UserPreferences,Address,Coordinates" {
+			t.Fatalf("Unexpected messages: %v", messages)
+		}
+		if strings.Join(enums, ",") != "UserRole,UserStatus,Theme" {
+			t.Fatalf("Unexpected enums: %v", enums)
+		}
+		if response.Root != "example.simple.v1.User" || response.Kind != "message" {
+			t.Fatalf("Unexpected root: %s (%s)", response.Root, response.Kind)
+		}
+		if response.Truncated {
+			t.Fatalf("Expected full closure not to be truncated")
+		}
+		if response.Count != 7 {
+			t.Fatalf("Expected count=7, got %d", response.Count)
+		}
+	})
+
+	t.Run("DepthLimit", func(t *testing.T) {
+		response := callResolveType(map[string]interface{}{"name": ".example.simple.v1.User", "depth": 1})
+		if !response.Success {
+			t.Fatalf("Expected success=true, got success=false: %s", response.Message)
+		}
+
+		messages, enums := names(response)
+		if strings.Join(messages, ",") != "User,UserPreferences,Address" {
+			t.Fatalf("Unexpected messages: %v", messages)
+		}
+		if strings.Join(enums, ",") != "UserRole,UserStatus" {
+			t.Fatalf("Unexpected enums: %v", enums)
+		}
+		if !response.Truncated {
+			t.Fatalf("Expected depth-limited closure to be truncated")
+		}
+	})
+
+	t.Run("Enum", func(t *testing.T) {
+		response := callResolveType(map[string]interface{}{"name": "example.simple.v1.Theme"})
+		if !response.Success || response.Kind != "enum" || len(response.Enums) != 1 || len(response.Messages) != 0 {
+			t.Fatalf("Expected a single enum, got %+v", response)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		response := callResolveType(map[string]interface{}{"name": "User"})
+		if response.Success {
+			t.Fatalf("Expected success=false for a short name")
+		}
+		if !strings.Contains(response.Message, "Type not found") {
+			t.Fatalf("Expected type not found message, got: %s", response.Message)
+		}
+	})
+}
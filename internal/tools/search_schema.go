@@ -0,0 +1,423 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+)
+
+// Search modes supported by search_schema
+const (
+	searchModeFuzzy = "fuzzy"
+	searchModeRegex = "regex"
+)
+
+// Areas of a declaration that a search can match
+const (
+	searchAreaName        = "name"
+	searchAreaFullName    = "full_name"
+	searchAreaDescription = "description"
+	searchAreaOption      = "option"
+)
+
+// searchAreaWeights ranks matches by where they occur; a match in a name
+// outranks one in a comment
+var searchAreaWeights = map[string]float64{
+	searchAreaName:        10,
+	searchAreaFullName:    6,
+	searchAreaDescription: 3,
+	searchAreaOption:      2,
+}
+
+// searchStopWords are ignored in fuzzy queries so that natural-language
+// questions rank on their meaningful words
+var searchStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "do": true, "does": true,
+	"for": true, "how": true, "in": true, "is": true, "of": true, "or": true,
+	"the": true, "to": true, "we": true, "what": true, "where": true, "which": true,
+	"who": true, "with": true,
+}
+
+// SearchSchemaTool implements the search_schema MCP tool using mcp-go
+type SearchSchemaTool struct {
+	projectManager ProjectManagerInterface
+}
+
+// NewSearchSchemaTool creates a new SearchSchemaTool instance
+func NewSearchSchemaTool(projectManager ProjectManagerInterface) *SearchSchemaTool {
+	return &SearchSchemaTool{
+		projectManager: projectManager,
+	}
+}
+
+// GetTool returns the MCP tool definition
+func (t *SearchSchemaTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		"search_schema",
+		mcp.WithDescription("Search names, comments, field names, enum values and option values across the schema and return ranked hits with their kind, full name and source location"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Search words (e.g. 'email address') or, in regex mode, a regular expression"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("Search mode: 'fuzzy' (default) ranks partial word matches, 'regex' matches a case-insensitive regular expression"),
+		),
+		mcp.WithString("kind",
+			mcp.Description("Only return hits of this kind: message, field, oneof, enum, enum_value, service, method or extension"),
+		),
+		mcp.WithString("package",
+			mcp.Description("Filter by package name or glob (e.g. 'example.*')"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of hits to return (default 50)"),
+		),
+	)
+}
+
+// SearchSchemaResponse represents the response from search_schema tool
+type SearchSchemaResponse struct {
+	Success   bool        `json:"success"`
+	Message   string      `json:"message"`
+	Query     string      `json:"query,omitempty"`
+	Mode      string      `json:"mode,omitempty"`
+	Hits      []SearchHit `json:"hits,omitempty"`
+	Count     int         `json:"count"`
+	Truncated bool        `json:"truncated"`
+}
+
+// SearchHit represents a declaration matching a search. MatchedIn lists the
+// areas that matched and Snippet holds the matching comment or option text
+// when the match was not in a name.
+type SearchHit struct {
+	Kind      string          `json:"kind"`
+	Name      string          `json:"name"`
+	FullName  string          `json:"full_name"`
+	Score     float64         `json:"score"`
+	MatchedIn []string        `json:"matched_in"`
+	Snippet   string          `json:"snippet,omitempty"`
+	Location  *protoutil.Span `json:"location,omitempty"`
+}
+
+// searchDocument holds the searchable text of a declaration
+type searchDocument struct {
+	desc        protoreflect.Descriptor
+	description string
+	options     []string
+}
+
+// searchMatcher scores a document, returning zero when it does not match
+type searchMatcher func(doc searchDocument) (score float64, matchedIn []string, snippet string)
+
+// Handle handles the tool execution
+func (t *SearchSchemaTool) Handle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := req.GetString("query", "")
+	if query == "" {
+		return mcp.NewToolResultError("query parameter is required"), nil
+	}
+	mode := req.GetString("mode", searchModeFuzzy)
+	kind := req.GetString("kind", "")
+	packageFilter := req.GetString("package", "")
+	limit := req.GetInt("limit", 50)
+
+	var matcher searchMatcher
+	switch mode {
+	case searchModeFuzzy:
+		matcher = newFuzzyMatcher(query)
+	case searchModeRegex:
+		re, err := regexp.Compile("(?i)" + query)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid regular expression: %v", err)), nil
+		}
+		matcher = newRegexMatcher(re)
+	default:
+		return mcp.NewToolResultError(fmt.Sprintf("unsupported mode %q: expected 'fuzzy' or 'regex'", mode)), nil
+	}
+
+	// Get current project
+	project := t.projectManager.GetProject()
+	if project == nil {
+		response := &SearchSchemaResponse{
+			Success: false,
+			Message: "No project activated. Use activate_project first.",
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	files, err := project.CompileProtos(ctx)
+	if err != nil {
+		response := &SearchSchemaResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to compile proto files: %v", err),
+		}
+		responseJSON, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+
+	hits := []SearchHit{}
+	for _, file := range files {
+		if !matchesPackage(string(file.Package()), packageFilter) {
+			continue
+		}
+		forEachDeclaration(file, func(desc protoreflect.Descriptor) {
+			if kind != "" && descriptorKind(desc) != kind {
+				return
+			}
+			score, matchedIn, snippet := matcher(newSearchDocument(desc))
+			if score <= 0 {
+				return
+			}
+			hits = append(hits, SearchHit{
+				Kind:      descriptorKind(desc),
+				Name:      string(desc.Name()),
+				FullName:  string(desc.FullName()),
+				Score:     score,
+				MatchedIn: matchedIn,
+				Snippet:   snippet,
+				Location:  protoutil.SpanOf(desc),
+			})
+		})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].FullName < hits[j].FullName
+	})
+
+	truncated := false
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+		truncated = true
+	}
+
+	response := &SearchSchemaResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("Found %d matches for %q", len(hits), query),
+		Query:     query,
+		Mode:      mode,
+		Hits:      hits,
+		Count:     len(hits),
+		Truncated: truncated,
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// newSearchDocument collects the searchable text of a declaration
+func newSearchDocument(desc protoreflect.Descriptor) searchDocument {
+	doc := searchDocument{
+		desc:        desc,
+		description: descriptionOf(desc, false),
+	}
+	for _, option := range optionsOf(desc) {
+		doc.options = append(doc.options, fmt.Sprintf("%s=%s", option.Name, formatOptionValue(option.Value)))
+	}
+	return doc
+}
+
+// newRegexMatcher matches a regular expression against names, descriptions
+// and option values. The score is the weight of the best matching area.
+func newRegexMatcher(re *regexp.Regexp) searchMatcher {
+	return func(doc searchDocument) (float64, []string, string) {
+		var score float64
+		var matchedIn []string
+		var snippet string
+		match := func(area, text string) {
+			if !re.MatchString(text) {
+				return
+			}
+			matchedIn = append(matchedIn, area)
+			score = max(score, searchAreaWeights[area])
+			if snippet == "" && (area == searchAreaDescription || area == searchAreaOption) {
+				snippet = matchingLine(text, re.MatchString)
+			}
+		}
+
+		match(searchAreaName, string(doc.desc.Name()))
+		match(searchAreaFullName, string(doc.desc.FullName()))
+		match(searchAreaDescription, doc.description)
+		for _, option := range doc.options {
+			if re.MatchString(option) {
+				match(searchAreaOption, option)
+				break
+			}
+		}
+		return score, matchedIn, snippet
+	}
+}
+
+// newFuzzyMatcher ranks declarations by how many query words they match and
+// how closely. Words match exactly, by prefix (so "address" finds
+// "addresses") or, for names, as an in-order subsequence of characters.
+// Each query word scores its best match weighted by area, and the total is
+// scaled by the fraction of query words that matched.
+func newFuzzyMatcher(query string) searchMatcher {
+	var tokens []string
+	for _, word := range searchWords(query) {
+		if !searchStopWords[word] {
+			tokens = append(tokens, word)
+		}
+	}
+	if len(tokens) == 0 {
+		tokens = searchWords(query)
+	}
+	compact := strings.Join(tokens, "")
+
+	return func(doc searchDocument) (float64, []string, string) {
+		nameWords := searchWords(string(doc.desc.Name()))
+		fullNameWords := searchWords(string(doc.desc.FullName()))
+		descriptionWords := searchWords(doc.description)
+
+		var total float64
+		matchedTokens := 0
+		areas := make(map[string]bool)
+		var snippet string
+
+		for _, token := range tokens {
+			best, bestArea := 0.0, ""
+			consider := func(area string, closeness float64) {
+				if score := closeness * searchAreaWeights[area]; score > best {
+					best, bestArea = score, area
+				}
+			}
+
+			consider(searchAreaName, wordsCloseness(token, nameWords, true))
+			consider(searchAreaFullName, wordsCloseness(token, fullNameWords, false))
+			consider(searchAreaDescription, wordsCloseness(token, descriptionWords, false))
+			for _, option := range doc.options {
+				if closeness := wordsCloseness(token, searchWords(option), false); closeness > 0 {
+					consider(searchAreaOption, closeness)
+					if snippet == "" && bestArea == searchAreaOption {
+						snippet = option
+					}
+				}
+			}
+
+			if best > 0 {
+				total += best
+				matchedTokens++
+				areas[bestArea] = true
+				if snippet == "" && bestArea == searchAreaDescription {
+					snippet = matchingLine(doc.description, func(line string) bool {
+						return wordsCloseness(token, searchWords(line), false) > 0
+					})
+				}
+			}
+		}
+
+		if matchedTokens == 0 {
+			return 0, nil, ""
+		}
+		score := total * float64(matchedTokens) / float64(len(tokens))
+		// Prefer names made up mostly of query words, so "email" ranks above
+		// "email_notifications"
+		covered := 0
+		for _, word := range nameWords {
+			for _, token := range tokens {
+				if wordsCloseness(token, []string{word}, false) > 0 {
+					covered++
+					break
+				}
+			}
+		}
+		if len(nameWords) > 0 {
+			score += float64(covered) / float64(len(nameWords))
+		}
+		// Bonus for a name that is exactly the query, e.g. "user id" for user_id
+		if strings.Join(nameWords, "") == compact {
+			score += searchAreaWeights[searchAreaName]
+		}
+
+		var matchedIn []string
+		for _, area := range []string{searchAreaName, searchAreaFullName, searchAreaDescription, searchAreaOption} {
+			if areas[area] {
+				matchedIn = append(matchedIn, area)
+			}
+		}
+		return score, matchedIn, snippet
+	}
+}
+
+// wordsCloseness returns how closely a query token matches the best of a set
+// of words, from 1 for an exact match down to 0 for no match
+func wordsCloseness(token string, words []string, allowSubsequence bool) float64 {
+	best := 0.0
+	for _, word := range words {
+		switch {
+		case word == token:
+			return 1
+		case len(token) >= 3 && len(word) >= 3 && (strings.HasPrefix(word, token) || strings.HasPrefix(token, word)):
+			best = max(best, 0.7)
+		case allowSubsequence && len(token) >= 3 && isSubsequence(token, word):
+			best = max(best, 0.4)
+		}
+	}
+	return best
+}
+
+// isSubsequence checks if the characters of s appear in order in t
+func isSubsequence(s, t string) bool {
+	i := 0
+	for j := 0; i < len(s) && j < len(t); j++ {
+		if s[i] == t[j] {
+			i++
+		}
+	}
+	return i == len(s)
+}
+
+// searchWords splits text into lowercase words at non-alphanumeric characters
+// and camelCase boundaries, so "UserEmail" and "user_email" both yield
+// "user" and "email"
+func searchWords(text string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1]))):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// matchingLine returns the first line of text accepted by match, trimmed
+func matchingLine(text string, match func(string) bool) string {
+	for _, line := range strings.Split(text, "\n") {
+		if match(line) {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newSearchSchemaTestTool(t *testing.T) *SearchSchemaTool {
+	t.Helper()
+
+	project, err := CreateTestProject(t)
+	if err != nil {
+		t.Fatalf("Failed to create test project: %v", err)
+	}
+
+	mockProjectManager := &MockProjectManager{}
+	mockProjectManager.SetProject(project)
+	return NewSearchSchemaTool(mockProjectManager)
+}
+
+func TestSearchSchemaTool_GetTool(t *testing.T) {
+	mockProjectManager := &MockProjectManager{}
+	tool := NewSearchSchemaTool(mockProjectManager)
+
+	mcpTool := tool.GetTool()
+	if mcpTool.Name != "search_schema" {
+		t.Fatalf("Expected tool name 'search_schema', got '%s'", mcpTool.Name)
+	}
+
+	if mcpTool.Description == "" {
+		t.Fatalf("Expected non-empty description")
+	}
+}
+
+func TestSearchSchemaTool_Handle_Fuzzy(t *testing.T) {
+	tool := newSearchSchemaTestTool(t)
+	response := callToolSuccessfully[SearchSchemaResponse](t, tool.Handle, "search_schema", map[string]interface{}{
+		"query": "where do we store email addresses?",
+		"limit": 3,
+	})
+
+	if response.Count != 3 || !response.Truncated {
+		t.Fatalf("Expected 3 truncated hits, got %d (truncated=%v)", response.Count, response.Truncated)
+	}
+	for _, hit := range response.Hits[:2] {
+		if hit.Kind != "field" || hit.Name != "email" {
+			t.Errorf("Expected email fields to rank first, got %+v", response.Hits)
+		}
+		if hit.Location == nil {
+			t.Errorf("Expected hit location for %s", hit.FullName)
+		}
+	}
+}
+
+func TestSearchSchemaTool_Handle_Description(t *testing.T) {
+	tool := newSearchSchemaTestTool(t)
+	response := callToolSuccessfully[SearchSchemaResponse](t, tool.Handle, "search_schema", map[string]interface{}{
+		"query": "pagination",
+		"kind":  "method",
+	})
+
+	if response.Count != 1 || response.Hits[0].FullName != "example.simple.v1.UserService.ListUsers" {
+		t.Fatalf("Expected ListUsers to match its comment, got %+v", response.Hits)
+	}
+	hit := response.Hits[0]
+	if !reflect.DeepEqual(hit.MatchedIn, []string{searchAreaDescription}) || hit.Snippet != "List users with pagination" {
+		t.Errorf("Expected description match with snippet, got %+v", hit)
+	}
+}
+
+func TestSearchSchemaTool_Handle_Regex(t *testing.T) {
+	tool := newSearchSchemaTestTool(t)
+	response := callToolSuccessfully[SearchSchemaResponse](t, tool.Handle, "search_schema", map[string]interface{}{
+		"query": "^user_?id$",
+		"mode":  "regex",
+		"kind":  "field",
+	})
+
+	if response.Count == 0 {
+		t.Fatalf("Expected user_id fields to match")
+	}
+	for _, hit := range response.Hits {
+		if hit.Name != "user_id" {
+			t.Errorf("Expected only user_id fields, got %s", hit.FullName)
+		}
+	}
+
+	response = callToolSuccessfully[SearchSchemaResponse](t, tool.Handle, "search_schema", map[string]interface{}{
+		"query": "/v1/users/",
+		"mode":  "regex",
+	})
+	if response.Count != 1 || response.Hits[0].FullName != "example.simple.v1.GreetingService.GetUser" {
+		t.Fatalf("Expected GetUser to match its HTTP option, got %+v", response.Hits)
+	}
+	if response.Hits[0].Snippet == "" || response.Hits[0].MatchedIn[0] != searchAreaOption {
+		t.Errorf("Expected option match with snippet, got %+v", response.Hits[0])
+	}
+}
+
+func TestSearchWords(t *testing.T) {
+	tests := map[string][]string{
+		"user_email":         {"user", "email"},
+		"UserEmail":          {"user", "email"},
+		"HTTPRule":           {"http", "rule"},
+		"example.v1.GetUser": {"example", "v1", "get", "user"},
+	}
+	for input, expected := range tests {
+		if got := searchWords(input); !reflect.DeepEqual(got, expected) {
+			t.Errorf("searchWords(%q) = %v, expected %v", input, got, expected)
+		}
+	}
+}
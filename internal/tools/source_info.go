@@ -0,0 +1,328 @@
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/yuemori/protobuf-mcp-server/internal/compiler"
+	"github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+	"github.com/yuemori/protobuf-mcp-server/internal/textedit"
+)
+
+// descriptionOf builds a description from the comments attached to a
+// descriptor. Leading and trailing comments are always combined; detached
+// comments (separated from the element by a blank line) are prepended when
+// includeDetached is set.
+func descriptionOf(desc protoreflect.Descriptor, includeDetached bool) string {
+	file := desc.ParentFile()
+	if file == nil {
+		return ""
+	}
+	loc := file.SourceLocations().ByDescriptor(desc)
+
+	var parts []string
+	if includeDetached {
+		for _, comment := range loc.LeadingDetachedComments {
+			if comment = strings.TrimSpace(comment); comment != "" {
+				parts = append(parts, comment)
+			}
+		}
+	}
+	if comment := strings.TrimSpace(loc.LeadingComments); comment != "" {
+		parts = append(parts, comment)
+	}
+	if comment := strings.TrimSpace(loc.TrailingComments); comment != "" {
+		parts = append(parts, comment)
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// Field numbers of the descriptor proto elements that hold type references,
+// used to locate the reference within a declaration
+const (
+	fieldTypeNamePathElement = 6 // FieldDescriptorProto.type_name
+	fieldExtendeePathElement = 2 // FieldDescriptorProto.extendee
+	methodInputPathElement   = 2 // MethodDescriptorProto.input_type
+	methodOutputPathElement  = 3 // MethodDescriptorProto.output_type
+)
+
+// subSourceSpanOf returns the source span of a part of a declaration, such as
+// the type name of a field, identified by its descriptor field number. Falls
+// back to the span of the whole declaration when the part has no location.
+func subSourceSpanOf(desc protoreflect.Descriptor, element int32) *protoutil.Span {
+	file := desc.ParentFile()
+	if file == nil {
+		return nil
+	}
+	loc := file.SourceLocations().ByDescriptor(desc)
+	if loc.Path == nil {
+		return nil
+	}
+
+	path := make(protoreflect.SourcePath, 0, len(loc.Path)+1)
+	path = append(path, loc.Path...)
+	path = append(path, element)
+
+	if span := pathSourceSpan(file, path); span != nil {
+		return span
+	}
+	return protoutil.SpanOf(desc)
+}
+
+// pathSourceSpan returns the source span of the element at a source path in
+// a file, or nil if there is no location for it
+func pathSourceSpan(file protoreflect.FileDescriptor, path protoreflect.SourcePath) *protoutil.Span {
+	return protoutil.SpanOfLocation(file, file.SourceLocations().ByPath(path))
+}
+
+// Source path elements of the declarations nested in each descriptor kind,
+// as numbered in descriptor.proto
+const (
+	filePackagePathElement           = 2
+	fileMessagesPathElement          = 4
+	fileEnumsPathElement             = 5
+	fileServicesPathElement          = 6
+	fileExtensionsPathElement        = 7
+	messageFieldsPathElement         = 2
+	messageNestedPathElement         = 3
+	messageEnumsPathElement          = 4
+	messageExtensionsPathElement     = 6
+	messageOneofsPathElement         = 8
+	messageReservedRangesPathElement = 9
+	messageReservedNamesPathElement  = 10
+	enumValuesPathElement            = 2
+	serviceMethodsPathElement        = 2
+)
+
+// descriptorAtPath walks a source path from a file to the innermost
+// declaration it addresses. The remainder of the path, relative to that
+// declaration (e.g. {1} for its name or {6} for a field's type), is
+// returned alongside it.
+func descriptorAtPath(file protoreflect.FileDescriptor, path protoreflect.SourcePath) (protoreflect.Descriptor, protoreflect.SourcePath) {
+	var desc protoreflect.Descriptor = file
+	for len(path) >= 2 {
+		child := childDescriptor(desc, path[0], int(path[1]))
+		if child == nil {
+			break
+		}
+		desc = child
+		path = path[2:]
+	}
+	return desc, path
+}
+
+// childDescriptor returns the declaration at index of the list identified by
+// a source path element, or nil if there is none
+func childDescriptor(desc protoreflect.Descriptor, element int32, index int) protoreflect.Descriptor {
+	type list interface{ Len() int }
+	get := func(l list, fn func(int) protoreflect.Descriptor) protoreflect.Descriptor {
+		if index < 0 || index >= l.Len() {
+			return nil
+		}
+		return fn(index)
+	}
+
+	switch d := desc.(type) {
+	case protoreflect.FileDescriptor:
+		switch element {
+		case fileMessagesPathElement:
+			return get(d.Messages(), func(i int) protoreflect.Descriptor { return d.Messages().Get(i) })
+		case fileEnumsPathElement:
+			return get(d.Enums(), func(i int) protoreflect.Descriptor { return d.Enums().Get(i) })
+		case fileServicesPathElement:
+			return get(d.Services(), func(i int) protoreflect.Descriptor { return d.Services().Get(i) })
+		case fileExtensionsPathElement:
+			return get(d.Extensions(), func(i int) protoreflect.Descriptor { return d.Extensions().Get(i) })
+		}
+	case protoreflect.MessageDescriptor:
+		switch element {
+		case messageFieldsPathElement:
+			return get(d.Fields(), func(i int) protoreflect.Descriptor { return d.Fields().Get(i) })
+		case messageNestedPathElement:
+			return get(d.Messages(), func(i int) protoreflect.Descriptor { return d.Messages().Get(i) })
+		case messageEnumsPathElement:
+			return get(d.Enums(), func(i int) protoreflect.Descriptor { return d.Enums().Get(i) })
+		case messageExtensionsPathElement:
+			return get(d.Extensions(), func(i int) protoreflect.Descriptor { return d.Extensions().Get(i) })
+		case messageOneofsPathElement:
+			return get(d.Oneofs(), func(i int) protoreflect.Descriptor { return d.Oneofs().Get(i) })
+		}
+	case protoreflect.EnumDescriptor:
+		if element == enumValuesPathElement {
+			return get(d.Values(), func(i int) protoreflect.Descriptor { return d.Values().Get(i) })
+		}
+	case protoreflect.ServiceDescriptor:
+		if element == serviceMethodsPathElement {
+			return get(d.Methods(), func(i int) protoreflect.Descriptor { return d.Methods().Get(i) })
+		}
+	}
+	return nil
+}
+
+// optionsPathElement returns the source path element of a declaration's
+// options, as numbered in descriptor.proto
+func optionsPathElement(desc protoreflect.Descriptor) int32 {
+	switch desc.(type) {
+	case protoreflect.FileDescriptor:
+		return 8
+	case protoreflect.MessageDescriptor:
+		return 7
+	case protoreflect.FieldDescriptor:
+		return 8
+	case protoreflect.OneofDescriptor:
+		return 2
+	case protoreflect.MethodDescriptor:
+		return 4
+	default:
+		// Enums, enum values and services
+		return 3
+	}
+}
+
+// innermostLocation returns the smallest source location of a file that
+// contains a 1-based position, or false if no location contains it
+func innermostLocation(file protoreflect.FileDescriptor, line, column int) (protoreflect.SourceLocation, bool) {
+	// Source locations are 0-based with an exclusive end column
+	line, column = line-1, column-1
+
+	var best protoreflect.SourceLocation
+	found := false
+	locs := file.SourceLocations()
+	for i := 0; i < locs.Len(); i++ {
+		loc := locs.Get(i)
+		if !locationContains(loc, line, column) {
+			continue
+		}
+		if !found || locationWithin(loc, best) {
+			best, found = loc, true
+		}
+	}
+	return best, found
+}
+
+// locationContains checks if a 0-based position falls within a location
+func locationContains(loc protoreflect.SourceLocation, line, column int) bool {
+	if line < loc.StartLine || (line == loc.StartLine && column < loc.StartColumn) {
+		return false
+	}
+	return line < loc.EndLine || (line == loc.EndLine && column < loc.EndColumn)
+}
+
+// locationWithin checks if a location is nested inside another, preferring
+// the longer path when two locations cover the same span
+func locationWithin(loc, other protoreflect.SourceLocation) bool {
+	if loc.StartLine != other.StartLine || loc.StartColumn != other.StartColumn ||
+		loc.EndLine != other.EndLine || loc.EndColumn != other.EndColumn {
+		startsAfter := loc.StartLine > other.StartLine || (loc.StartLine == other.StartLine && loc.StartColumn >= other.StartColumn)
+		endsBefore := loc.EndLine < other.EndLine || (loc.EndLine == other.EndLine && loc.EndColumn <= other.EndColumn)
+		return startsAfter && endsBefore
+	}
+	return len(loc.Path) > len(other.Path)
+}
+
+// sourceLines holds the content of a proto source file split into lines, to
+// compute edits around the declarations located by its source info. Lines
+// and columns are 0-based like in source locations.
+type sourceLines struct {
+	content []byte
+	lines   []string
+}
+
+// newSourceLines splits the content of a source file into lines
+func newSourceLines(content []byte) *sourceLines {
+	return &sourceLines{content: content, lines: strings.Split(string(content), "\n")}
+}
+
+// readSourceLines reads a compiled file of a project from its import paths
+func readSourceLines(project *compiler.ProtobufProject, file string) (*sourceLines, error) {
+	path, err := project.SourcePath(file)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return newSourceLines(content), nil
+}
+
+// line returns a line without its line break, or "" past the end of the file
+func (s *sourceLines) line(i int) string {
+	if i < 0 || i >= len(s.lines) {
+		return ""
+	}
+	return strings.TrimSuffix(s.lines[i], "\r")
+}
+
+// indent returns the leading whitespace of a line
+func (s *sourceLines) indent(i int) string {
+	text := s.line(i)
+	return text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+}
+
+// offset returns the byte offset of a position
+func (s *sourceLines) offset(line, column int) (int, bool) {
+	offset, err := textedit.Offset(s.content, line+1, column+1)
+	return offset, err == nil
+}
+
+// rest returns the text following a position on its line, trimmed
+func (s *sourceLines) rest(line, column int) (string, bool) {
+	offset, ok := s.offset(line, column)
+	if !ok {
+		return "", false
+	}
+	rest := s.content[offset:]
+	if end := bytes.IndexByte(rest, '\n'); end >= 0 {
+		rest = rest[:end]
+	}
+	return strings.TrimSpace(string(rest)), true
+}
+
+// commentLines counts the lines of a comment taken from source info
+func commentLines(comment string) int {
+	if comment == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(comment, "\n"), "\n") + 1
+}
+
+// firstLine returns the first line of a declaration, including the leading
+// comment lines directly above it
+func (s *sourceLines) firstLine(loc protoreflect.SourceLocation) int {
+	first := loc.StartLine - commentLines(loc.LeadingComments)
+	for i := first; i < loc.StartLine; i++ {
+		text := strings.TrimSpace(s.line(i))
+		if !strings.HasPrefix(text, "//") && !strings.HasPrefix(text, "/*") && !strings.HasPrefix(text, "*") {
+			return loc.StartLine
+		}
+	}
+	return first
+}
+
+// lastLine returns the last line of a declaration. The trailing comment of
+// the declaration is included when trailing is set, as are comment lines
+// between the declaration and a closing brace, which continue the trailing
+// comment even when the compiler does not attach them.
+func (s *sourceLines) lastLine(loc protoreflect.SourceLocation, trailing bool) int {
+	last := loc.EndLine
+	if count := commentLines(loc.TrailingComments); trailing && count > 0 {
+		if rest, _ := s.rest(loc.EndLine, loc.EndColumn); rest != "" {
+			count--
+		}
+		last += count
+	}
+	next := last + 1
+	for strings.HasPrefix(strings.TrimSpace(s.line(next)), "//") {
+		next++
+	}
+	if strings.HasPrefix(strings.TrimSpace(s.line(next)), "}") {
+		last = next - 1
+	}
+	return last
+}
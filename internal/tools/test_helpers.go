@@ -1,11 +1,16 @@
 package tools
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
 	"github.com/yuemori/protobuf-mcp-server/internal/compiler"
 	"github.com/yuemori/protobuf-mcp-server/internal/config"
 )
@@ -60,3 +65,98 @@ func CreateTestProject(t *testing.T) (*compiler.ProtobufProject, error) {
 
 	return project, nil
 }
+
+// CreateTempProject creates a project in a temporary directory from the given
+// proto sources, keyed by path relative to the project root
+func CreateTempProject(t *testing.T, sources map[string]string) (*compiler.ProtobufProject, error) {
+	// Compilation changes the working directory, so restore it afterwards
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(cwd); err != nil {
+			t.Fatalf("Failed to restore working directory: %v", err)
+		}
+	})
+
+	rootDir := t.TempDir()
+	for path, content := range sources {
+		fullPath := filepath.Join(rootDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", path, err)
+		}
+	}
+
+	cfg := &config.ProjectConfig{
+		ProtoFiles:  []string{"**/*.proto"},
+		ImportPaths: []string{"."},
+	}
+
+	return compiler.NewProtobufProject(rootDir, cfg)
+}
+
+// callTool calls a tool handler with the given arguments and decodes its JSON
+// response into R. The test fails if the handler returns an error result.
+func callTool[R any](t *testing.T, handle server.ToolHandlerFunc, name string, arguments map[string]interface{}) R {
+	t.Helper()
+
+	var response R
+	if err := json.Unmarshal([]byte(callToolText(t, handle, name, arguments)), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	return response
+}
+
+// callToolSuccessfully is like callTool but also fails the test unless the
+// response reports success
+func callToolSuccessfully[R any](t *testing.T, handle server.ToolHandlerFunc, name string, arguments map[string]interface{}) R {
+	t.Helper()
+
+	text := callToolText(t, handle, name, arguments)
+	var status struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(text), &status); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if !status.Success {
+		t.Fatalf("Expected success=true, got success=false: %s", status.Message)
+	}
+
+	var response R
+	if err := json.Unmarshal([]byte(text), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	return response
+}
+
+// callToolText calls a tool handler and returns the text of its response
+func callToolText(t *testing.T, handle server.ToolHandlerFunc, name string, arguments map[string]interface{}) string {
+	t.Helper()
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      name,
+			Arguments: arguments,
+		},
+	}
+
+	result, err := handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Expected a response, got error result: %v", result.Content)
+	}
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatalf("Expected text content in response")
+	}
+	return textContent.Text
+}
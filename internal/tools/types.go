@@ -1,25 +1,115 @@
 package tools
 
+import "github.com/yuemori/protobuf-mcp-server/internal/protoutil"
+
+// SchemaVersion is the version of the JSON output schema shared by all tools.
+// It is bumped whenever a field is removed or changes meaning; see
+// docs/output-schema.md for the documented format.
+const SchemaVersion = "1"
+
 // ServiceInfo represents information about a protobuf service
 type ServiceInfo struct {
-	Name        string       `json:"name"`
-	FullName    string       `json:"full_name"`
-	Methods     []MethodInfo `json:"methods"`
-	File        string       `json:"file"`
-	Package     string       `json:"package"`
-	Description string       `json:"description"`
-	Options     []OptionInfo `json:"options,omitempty"`
+	Name        string          `json:"name"`
+	FullName    string          `json:"full_name"`
+	Methods     []MethodInfo    `json:"methods"`
+	File        string          `json:"file"`
+	Package     string          `json:"package"`
+	Description string          `json:"description"`
+	Options     []OptionInfo    `json:"options,omitempty"`
+	Location    *protoutil.Span `json:"location,omitempty"`
 }
 
 // MethodInfo represents information about a protobuf service method
 type MethodInfo struct {
-	Name            string       `json:"name"`
-	InputType       string       `json:"input_type"`
-	OutputType      string       `json:"output_type"`
-	ClientStreaming bool         `json:"client_streaming"`
-	ServerStreaming bool         `json:"server_streaming"`
-	Description     string       `json:"description"`
-	Options         []OptionInfo `json:"options,omitempty"`
+	Name            string                  `json:"name"`
+	FullName        string                  `json:"full_name"`
+	InputType       string                  `json:"input_type"`
+	OutputType      string                  `json:"output_type"`
+	ClientStreaming bool                    `json:"client_streaming"`
+	ServerStreaming bool                    `json:"server_streaming"`
+	Description     string                  `json:"description"`
+	Options         []OptionInfo            `json:"options,omitempty"`
+	HTTPBindings    []protoutil.HTTPBinding `json:"http_bindings,omitempty"`
+	Location        *protoutil.Span         `json:"location,omitempty"`
+}
+
+// MessageInfo represents detailed information about a protobuf message
+type MessageInfo struct {
+	Name                string          `json:"name"`
+	FullName            string          `json:"full_name"`
+	Fields              []FieldInfo     `json:"fields"`
+	NestedMessages      []MessageInfo   `json:"nested_messages,omitempty"`
+	NestedEnums         []EnumInfo      `json:"nested_enums,omitempty"`
+	File                string          `json:"file"`
+	Package             string          `json:"package"`
+	Description         string          `json:"description"`
+	Options             []OptionInfo    `json:"options,omitempty"`
+	ReservedRanges      []RangeInfo     `json:"reserved_ranges,omitempty"`
+	ReservedNames       []string        `json:"reserved_names,omitempty"`
+	ExtensionRanges     []RangeInfo     `json:"extension_ranges,omitempty"`
+	NextFreeFieldNumber int32           `json:"next_free_field_number"`
+	Location            *protoutil.Span `json:"location,omitempty"`
+}
+
+// FieldInfo represents information about a message field
+type FieldInfo struct {
+	Name         string          `json:"name"`
+	JSONName     string          `json:"json_name"`
+	Number       int32           `json:"number"`
+	Type         string          `json:"type"`
+	TypeName     string          `json:"type_name,omitempty"`
+	MapKeyType   string          `json:"map_key_type,omitempty"`
+	MapValueType string          `json:"map_value_type,omitempty"`
+	Oneof        string          `json:"oneof,omitempty"`
+	Optional     bool            `json:"optional"`
+	Repeated     bool            `json:"repeated"`
+	Description  string          `json:"description"`
+	Options      []OptionInfo    `json:"options,omitempty"`
+	Location     *protoutil.Span `json:"location,omitempty"`
+}
+
+// EnumInfo represents detailed information about a protobuf enum
+type EnumInfo struct {
+	Name           string          `json:"name"`
+	FullName       string          `json:"full_name"`
+	Values         []EnumValueInfo `json:"values"`
+	File           string          `json:"file"`
+	Package        string          `json:"package"`
+	Description    string          `json:"description"`
+	Options        []OptionInfo    `json:"options,omitempty"`
+	ReservedRanges []RangeInfo     `json:"reserved_ranges,omitempty"`
+	ReservedNames  []string        `json:"reserved_names,omitempty"`
+	Location       *protoutil.Span `json:"location,omitempty"`
+}
+
+// EnumValueInfo represents information about an enum value
+type EnumValueInfo struct {
+	Name        string          `json:"name"`
+	Number      int32           `json:"number"`
+	Description string          `json:"description"`
+	Options     []OptionInfo    `json:"options,omitempty"`
+	Location    *protoutil.Span `json:"location,omitempty"`
+}
+
+// FileInfo represents file-level metadata of a compiled proto file
+type FileInfo struct {
+	Path           string       `json:"path"`
+	Package        string       `json:"package"`
+	Syntax         string       `json:"syntax"`
+	Edition        string       `json:"edition,omitempty"`
+	Imports        []ImportInfo `json:"imports"`
+	Options        []OptionInfo `json:"options,omitempty"`
+	MessageCount   int          `json:"message_count"`
+	EnumCount      int          `json:"enum_count"`
+	ServiceCount   int          `json:"service_count"`
+	ExtensionCount int          `json:"extension_count"`
+}
+
+// ImportInfo represents an import statement of a proto file
+type ImportInfo struct {
+	Path   string `json:"path"`
+	Public bool   `json:"public"`
+	Weak   bool   `json:"weak"`
 }
 
 // OptionInfo represents information about protobuf options
@@ -28,6 +118,12 @@ type OptionInfo struct {
 	Value interface{} `json:"value"`
 }
 
+// RangeInfo represents an inclusive range of field or enum value numbers
+type RangeInfo struct {
+	Start int32 `json:"start"`
+	End   int32 `json:"end"`
+}
+
 // Helper functions for creating protobuf values
 func stringPtr(s string) *string {
 	return &s